@@ -9,15 +9,25 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Streak days need IANA timezones even where the host has no zoneinfo
+	_ "time/tzdata"
 
+	"github.com/yourusername/skilltree/internal/achievements"
 	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/curriculum"
 	"github.com/yourusername/skilltree/internal/database"
+	"github.com/yourusername/skilltree/internal/events"
 	"github.com/yourusername/skilltree/internal/handler"
 	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/progression"
+	"github.com/yourusername/skilltree/internal/prompts"
+	"github.com/yourusername/skilltree/internal/ratelimit"
 	"github.com/yourusername/skilltree/internal/repository"
 	"github.com/yourusername/skilltree/internal/router"
+	"github.com/yourusername/skilltree/internal/sandbox"
 	"github.com/yourusername/skilltree/internal/service"
 	"github.com/yourusername/skilltree/pkg/firebase"
+	"github.com/yourusername/skilltree/pkg/httpfixture"
 )
 
 func main() {
@@ -26,6 +36,9 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := curriculum.Validate(); err != nil {
+		log.Fatalf("Invalid curriculum catalog: %v", err)
+	}
 
 	// Initialize database
 	db, err := database.NewMySQLConnection(cfg.GetDSN(), cfg.DBMaxConnections)
@@ -48,28 +61,188 @@ func main() {
 	}
 	log.Println("Firebase initialized")
 
+	// Load prompt templates (embedded defaults, optionally overridden on disk)
+	promptRegistry, err := prompts.Load(cfg.PromptsDir)
+	if err != nil {
+		log.Fatalf("Failed to load prompts: %v", err)
+	}
+
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	masteryRepo := repository.NewMasteryRepository(db)
 	checkpointRepo := repository.NewCheckpointRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	submissionRepo := repository.NewSubmissionRepository(db)
+	usageRepo := repository.NewUsageRepository(db)
+	securityEventRepo := repository.NewSecurityEventRepository(db)
+	hintRepo := repository.NewHintRepository(db)
+	fingerprintRepo := repository.NewFingerprintRepository(db)
+	quizRepo := repository.NewQuizRepository(db)
+	progressRepo := repository.NewProgressRepository(db)
+	settingsRepo := repository.NewSettingsRepository(db)
+	achievementRepo := repository.NewAchievementRepository(db)
+	leaderboardRepo := repository.NewLeaderboardRepository(db)
+	cohortRepo := repository.NewCohortRepository(db)
+	problemRepo := repository.NewProblemRepository(db)
+
+	// AI response cache: in-memory LRU, optionally backed by MySQL
+	var aiCacheStore service.CacheStore
+	if cfg.AICacheStore == "mysql" {
+		aiCacheStore = repository.NewAICacheRepository(db)
+	}
+	aiCache := service.NewResponseCache(time.Duration(cfg.AICacheTTLMinutes)*time.Minute, cfg.AICacheMaxEntries, aiCacheStore)
 
 	// Initialize services
 	authService := service.NewAuthService(userRepo, masteryRepo, checkpointRepo)
 	masteryService := service.NewMasteryService(masteryRepo, userRepo)
-	geminiService := service.NewGeminiService(cfg.GeminiAPIKey, cfg.GeminiAPIURL)
-	checkpointService := service.NewCheckpointService(checkpointRepo, masteryRepo, geminiService)
+	quotaService := service.NewQuotaService(usageRepo, int64(cfg.LLMDailyTokenBudget))
+	breakerCooldown := time.Duration(cfg.GeminiBreakerCooldownSeconds) * time.Second
+	// Gemini HTTP fixtures: record real exchanges or replay them offline
+	geminiTransport, err := httpfixture.NewTransport(cfg.GeminiFixtureMode, cfg.GeminiFixtureDir, nil)
+	if err != nil {
+		log.Fatalf("Failed to set up Gemini fixtures: %v", err)
+	}
+	if cfg.GeminiFixtureMode != httpfixture.ModeOff {
+		log.Printf("Gemini fixtures: %s (%s)", cfg.GeminiFixtureMode, cfg.GeminiFixtureDir)
+	}
+
+	geminiService := service.NewGeminiService(cfg.GeminiAPIKey, cfg.GeminiAPIURL, promptRegistry)
+	geminiService.SetTransport(geminiTransport)
+	geminiService.SetUsageRecorder(quotaService)
+	geminiService.SetCache(aiCache)
+	geminiService.SetCircuitBreaker(service.NewCircuitBreaker(cfg.GeminiBreakerFailures, breakerCooldown))
+	aiProviders := []*service.GeminiService{geminiService}
+
+	// Checkpoint judge panel: the primary model plus any extra models
+	// configured, each optionally with its own prompt templates
+	judgePanel := []service.JudgePanelMember{{Name: geminiService.Model(), Judge: geminiService}}
+	for i, apiURL := range cfg.JudgePanelAPIURLs {
+		panelPrompts, name := promptRegistry, ""
+		if i < len(cfg.JudgePanelPromptDirs) && cfg.JudgePanelPromptDirs[i] != "" {
+			dir := cfg.JudgePanelPromptDirs[i]
+			if panelPrompts, err = prompts.Load(dir); err != nil {
+				log.Fatalf("Failed to load prompts for judge panel member %d: %v", i+1, err)
+			}
+			name = " (" + dir + ")"
+		}
+		panelService := service.NewGeminiService(cfg.GeminiAPIKey, apiURL, panelPrompts)
+		panelService.SetTransport(geminiTransport)
+		panelService.SetUsageRecorder(quotaService)
+		panelService.SetCircuitBreaker(service.NewCircuitBreaker(cfg.GeminiBreakerFailures, breakerCooldown))
+		aiProviders = append(aiProviders, panelService)
+		judgePanel = append(judgePanel, service.JudgePanelMember{
+			Name:  panelService.Model() + name,
+			Judge: panelService,
+		})
+	}
+	checkpointJudge := service.NewConsensusJudge(judgePanel, cfg.JudgeConsensusVotes, cfg.JudgeConsensusMode)
+
+	// Hints discount the mastery credit of the problems they were used on
+	hintService := service.NewHintService(geminiService, hintRepo, cfg.HintCreditPenalties)
+	masteryService.SetHintService(hintService)
+	codeReviewService := service.NewCodeReviewService(geminiService)
+
+	// Sandbox for empirical complexity profiling and for checking the
+	// reference solutions of authored and generated problems
+	var sandboxRunner *sandbox.Runner
+	if cfg.SandboxEnabled {
+		sandboxRunner = sandbox.NewRunner(sandbox.Config{
+			NodePath:      cfg.SandboxNodePath,
+			PythonPath:    cfg.SandboxPythonPath,
+			Timeout:       time.Duration(cfg.SandboxTimeoutSeconds) * time.Second,
+			MemoryMB:      cfg.SandboxMemoryMB,
+			MaxInputSize:  cfg.SandboxMaxInputSize,
+			MaxConcurrent: cfg.SandboxMaxConcurrent,
+			MaxProcesses:  cfg.SandboxMaxProcesses,
+			Isolation:     cfg.SandboxIsolation,
+			UID:           cfg.SandboxUID,
+			GID:           cfg.SandboxGID,
+			BwrapPath:     cfg.SandboxBwrapPath,
+		})
+		// Untrusted code must never run with the API's privileges
+		if err := sandboxRunner.Check(context.Background()); err != nil {
+			log.Fatalf("Sandbox is enabled but not isolated: %v", err)
+		}
+	}
+	complexityService := service.NewComplexityService(geminiService, sandboxRunner)
+
+	submissionGuard := service.NewSubmissionGuard(securityEventRepo)
+	similarityService := service.NewSimilarityService(fingerprintRepo, securityEventRepo, cfg.SimilarityThresholdPercent, cfg.SimilarityMinFingerprints)
+	// Passes that look AI-generated must be explained in a quiz to count
+	aiRiskService := service.NewAIRiskService(checkpointRepo, submissionRepo, cfg.AIRiskQuizThreshold)
+	quizService := service.NewQuizService(geminiService, quizRepo, cfg.QuizQuestions, cfg.QuizPassPercent, time.Duration(cfg.QuizTTLMinutes)*time.Minute)
+	checkpointService := service.NewCheckpointService(checkpointRepo, masteryRepo, reviewRepo, checkpointJudge, submissionGuard, similarityService, aiRiskService, quizService)
+	judgeService := service.NewJudgeService(geminiService, masteryService, submissionRepo, reviewRepo, submissionGuard)
+	judgeService.SetQuizService(quizService, cfg.PracticeQuizTopics, cfg.QuizUnverifiedCreditPenalty)
+	reviewService := service.NewReviewService(reviewRepo, submissionRepo, checkpointRepo, masteryService, checkpointService)
+
+	// Domain events feed XP and streaks, then badges (which read them)
+	eventBus := events.NewBus()
+	progressService := service.NewProgressService(progressRepo, settingsRepo, progression.StreakRules{
+		FreezeEvery:     cfg.StreakFreezeIntervalDays,
+		MaxFreezeTokens: cfg.StreakFreezeMaxTokens,
+	})
+	achievementService := service.NewAchievementService(achievementRepo, progressRepo, checkpointRepo, masteryService, achievements.Rules)
+	leaderboardService := service.NewLeaderboardService(leaderboardRepo)
+	cohortService := service.NewCohortService(cohortRepo, leaderboardService)
+	curriculumService := service.NewCurriculumService(settingsRepo, cohortRepo, masteryRepo, checkpointRepo)
+	checkpointService.SetCurriculumService(curriculumService)
+	judgeService.SetCurriculumService(curriculumService)
+	// Published authored problems join the built-in catalog
+	problemService := service.NewProblemService(problemRepo, sandboxRunner)
+	problemService.SetGeminiService(geminiService)
+	judgeService.SetProblemService(problemService)
+	hintService.SetProblemService(problemService)
+	codeReviewService.SetProblemService(problemService)
+	reviewService.SetProblemService(problemService)
+	cohortService.SetProblemService(problemService)
+	achievementService.SetProblemService(problemService)
+	eventBus.Subscribe("progress", progressService.Handle)
+	eventBus.Subscribe("achievements", achievementService.Handle)
+	judgeService.SetEventBus(eventBus)
+	checkpointService.SetEventBus(eventBus)
+	reviewService.SetEventBus(eventBus)
 
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
 	masteryHandler := handler.NewMasteryHandler(masteryService)
-	aiHandler := handler.NewAIHandler(geminiService, masteryService)
+	aiHandler := handler.NewAIHandler(geminiService, complexityService, judgeService, hintService, codeReviewService, quotaService)
 	checkpointHandler := handler.NewCheckpointHandler(checkpointService)
+	reviewHandler := handler.NewReviewHandler(reviewService)
+	healthHandler := handler.NewHealthHandler(aiProviders...)
+	promptHandler := handler.NewPromptHandler(promptRegistry)
+	securityHandler := handler.NewSecurityHandler(submissionGuard)
+	similarityHandler := handler.NewSimilarityHandler(similarityService)
+	profileHandler := handler.NewProfileHandler(progressService)
+	achievementHandler := handler.NewAchievementHandler(achievementService)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardService)
+	cohortHandler := handler.NewCohortHandler(cohortService)
+	curriculumHandler := handler.NewCurriculumHandler(curriculumService)
+	problemHandler := handler.NewProblemHandler(problemService)
 
 	// Initialize middleware
 	corsMiddleware := middleware.CORSMiddleware(cfg.CORSAllowedOrigins)
 
+	// Rate limit buckets live in-process unless a shared store is requested
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore()
+	if cfg.RateLimitStore == "mysql" {
+		rateLimitStore = repository.NewRateLimitRepository(db)
+	}
+	aiRule := ratelimit.Rule{PerMinute: float64(cfg.AIRateLimitPerMinute), Burst: cfg.AIRateLimitBurst}
+	aiRateLimits := map[string]ratelimit.Rule{
+		"ai_chat":       aiRule,
+		"ai_complexity": aiRule,
+		"ai_judge":      {PerMinute: float64(cfg.AIJudgeRateLimitPerMinute), Burst: cfg.AIRateLimitBurst},
+		"ai_judge_quiz": {PerMinute: float64(cfg.AIQuizRateLimitPerMinute), Burst: cfg.AIRateLimitBurst},
+		"ai_hint":       aiRule,
+		"ai_review":     aiRule,
+		// Problem submissions run the reference solution in the sandbox
+		"problem_submit": aiRule,
+	}
+
 	// Setup router
-	r := router.NewRouter(authHandler, masteryHandler, aiHandler, checkpointHandler, firebaseAuth, corsMiddleware)
+	r := router.NewRouter(authHandler, masteryHandler, aiHandler, checkpointHandler, reviewHandler, healthHandler, promptHandler, securityHandler, similarityHandler, profileHandler, achievementHandler, leaderboardHandler, cohortHandler, curriculumHandler, problemHandler,
+		rateLimitStore, aiRateLimits, time.Duration(cfg.AIRequestTimeoutSeconds)*time.Second, quotaService, firebaseAuth, corsMiddleware)
 
 	// Create server
 	srv := &http.Server{
@@ -0,0 +1,226 @@
+// Command evaljudge runs a labeled corpus of practice submissions through the
+// JudgeAudit prompt and reports how well the verdicts match the labels and how
+// they changed against a baseline run.
+//
+//	go run ./cmd/evaljudge -provider fake
+//	go run ./cmd/evaljudge -provider gemini -prompts ./my-prompts -out run.json
+//	go run ./cmd/evaljudge -provider recorded -recording run.json -baseline old.json
+//	go run ./cmd/evaljudge -provider gemini -fixture-mode replay
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/prompts"
+)
+
+// Case is one labeled submission in the corpus
+type Case struct {
+	ID        string `json:"id"`
+	TopicKey  string `json:"topic_key"`
+	ProblemID string `json:"problem_id"`
+	Code      string `json:"code"`
+	// Expected is the correct verdict: ADVANCE or REPEAT
+	Expected string `json:"expected"`
+}
+
+// CaseResult is the judge's verdict on one case
+type CaseResult struct {
+	ID            string `json:"id"`
+	TopicKey      string `json:"topic_key"`
+	ProblemID     string `json:"problem_id"`
+	Expected      string `json:"expected"`
+	Verdict       string `json:"verdict"`
+	Feedback      string `json:"feedback,omitempty"`
+	PromptVersion string `json:"prompt_version,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Run is the output of one evaluation; it doubles as a recording and a baseline
+type Run struct {
+	Provider string       `json:"provider"`
+	Model    string       `json:"model,omitempty"`
+	RanAt    time.Time    `json:"ran_at"`
+	Results  []CaseResult `json:"results"`
+}
+
+func main() {
+	corpusPath := flag.String("corpus", "eval/judge_corpus.jsonl", "labeled corpus (JSON lines)")
+	providerName := flag.String("provider", "fake", "judge provider: fake, gemini or recorded")
+	recordingPath := flag.String("recording", "", "run file to replay with -provider recorded")
+	promptsDir := flag.String("prompts", "", "prompt template overrides (defaults to the built-in prompts)")
+	apiURL := flag.String("api-url", "", "Gemini model URL (defaults to GEMINI_API_URL)")
+	baselinePath := flag.String("baseline", "", "earlier run file to diff verdicts against")
+	outPath := flag.String("out", "", "write this run's results to a file")
+	fixtureMode := flag.String("fixture-mode", "off", "Gemini HTTP fixtures: off, record or replay")
+	fixtureDir := flag.String("fixture-dir", "eval/fixtures", "directory for Gemini HTTP fixtures")
+	concurrency := flag.Int("concurrency", 4, "parallel judge calls")
+	flag.Parse()
+
+	_ = godotenv.Load()
+
+	corpus, err := loadCorpus(*corpusPath)
+	if err != nil {
+		log.Fatalf("Failed to load corpus: %v", err)
+	}
+
+	registry, err := prompts.Load(*promptsDir)
+	if err != nil {
+		log.Fatalf("Failed to load prompts: %v", err)
+	}
+
+	judge, err := newProvider(*providerName, *recordingPath, *apiURL, *fixtureMode, *fixtureDir, registry)
+	if err != nil {
+		log.Fatalf("Failed to create provider: %v", err)
+	}
+
+	run := evaluate(context.Background(), judge, corpus, *concurrency)
+
+	var baseline *Run
+	if *baselinePath != "" {
+		baseline, err = loadRun(*baselinePath)
+		if err != nil {
+			log.Fatalf("Failed to load baseline: %v", err)
+		}
+	}
+
+	printReport(os.Stdout, run, baseline)
+
+	if *outPath != "" {
+		if err := writeRun(*outPath, run); err != nil {
+			log.Fatalf("Failed to write results: %v", err)
+		}
+	}
+}
+
+// evaluate judges every case, at most concurrency at a time
+func evaluate(ctx context.Context, judge provider, corpus []Case, concurrency int) *Run {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]CaseResult, len(corpus))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, c := range corpus {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, c Case) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = judgeCase(ctx, judge, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return &Run{
+		Provider: judge.Name(),
+		Model:    judge.Model(),
+		RanAt:    time.Now().UTC(),
+		Results:  results,
+	}
+}
+
+func judgeCase(ctx context.Context, judge provider, c Case) CaseResult {
+	result := CaseResult{
+		ID:        c.ID,
+		TopicKey:  c.TopicKey,
+		ProblemID: c.ProblemID,
+		Expected:  c.Expected,
+	}
+
+	problem := findProblem(c.TopicKey, c.ProblemID)
+	if problem == nil {
+		result.Verdict = "ERROR"
+		result.Error = "problem not in catalog"
+		return result
+	}
+
+	verdict, _, err := judge.JudgeAudit(ctx, c.ID, c.Code, c.TopicKey, problem.Title, problem.Invariant)
+	if err != nil {
+		result.Verdict = "ERROR"
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Verdict, _ = verdict["verdict"].(string)
+	result.Feedback, _ = verdict["feedback"].(string)
+	result.PromptVersion, _ = verdict["prompt_version"].(string)
+	return result
+}
+
+func findProblem(topicKey, problemID string) *data.Problem {
+	for _, problem := range data.ProblemsDB[topicKey] {
+		if problem.ID == problemID {
+			return &problem
+		}
+	}
+	return nil
+}
+
+func loadCorpus(path string) ([]Case, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var corpus []Case
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var c Case
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if c.Expected != "ADVANCE" && c.Expected != "REPEAT" {
+			return nil, fmt.Errorf("line %d: expected must be ADVANCE or REPEAT, got %q", line, c.Expected)
+		}
+		corpus = append(corpus, c)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return corpus, nil
+}
+
+func loadRun(path string) (*Run, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var run Run
+	if err := json.Unmarshal(content, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &run, nil
+}
+
+func writeRun(path string, run *Run) error {
+	content, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(content, '\n'), 0o644)
+}
+
+// isAdvance mirrors the service's notion of a passing verdict
+func isAdvance(verdict string) bool {
+	return verdict == "ADVANCE" || verdict == "Optimal"
+}
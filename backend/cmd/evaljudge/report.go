@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// classStats counts outcomes for one verdict class
+type classStats struct {
+	truePositive, falsePositive, falseNegative int
+}
+
+func (s classStats) precision() float64 {
+	return ratio(s.truePositive, s.truePositive+s.falsePositive)
+}
+
+func (s classStats) recall() float64 {
+	return ratio(s.truePositive, s.truePositive+s.falseNegative)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
+
+// normalizeVerdict folds the judge's verdicts onto the corpus labels
+func normalizeVerdict(verdict string) string {
+	switch {
+	case isAdvance(verdict):
+		return "ADVANCE"
+	case strings.EqualFold(verdict, "REPEAT"):
+		return "REPEAT"
+	default:
+		return "ERROR"
+	}
+}
+
+func printReport(w io.Writer, run *Run, baseline *Run) {
+	versions := map[string]bool{}
+	stats := map[string]*classStats{"ADVANCE": {}, "REPEAT": {}}
+	agree, errors := 0, 0
+	var misses []CaseResult
+
+	for _, result := range run.Results {
+		if result.PromptVersion != "" {
+			versions[result.PromptVersion] = true
+		}
+
+		got := normalizeVerdict(result.Verdict)
+		if got == "ERROR" {
+			errors++
+		}
+
+		if got == result.Expected {
+			agree++
+			stats[got].truePositive++
+			continue
+		}
+
+		misses = append(misses, result)
+		stats[result.Expected].falseNegative++
+		if s, ok := stats[got]; ok {
+			s.falsePositive++
+		}
+	}
+
+	fmt.Fprintf(w, "Provider: %s", run.Provider)
+	if run.Model != "" {
+		fmt.Fprintf(w, " (%s)", run.Model)
+	}
+	fmt.Fprintf(w, "\nPrompt:   %s\n", strings.Join(sortedKeys(versions), ", "))
+	fmt.Fprintf(w, "Cases:    %d (%d errors)\n\n", len(run.Results), errors)
+
+	fmt.Fprintf(w, "Agreement with labels: %d/%d (%.1f%%)\n", agree, len(run.Results), 100*ratio(agree, len(run.Results)))
+	for _, class := range []string{"ADVANCE", "REPEAT"} {
+		s := stats[class]
+		fmt.Fprintf(w, "  %-8s precision %.2f  recall %.2f\n", class, s.precision(), s.recall())
+	}
+
+	if len(misses) > 0 {
+		fmt.Fprintln(w, "\nMisjudged:")
+		for _, result := range misses {
+			fmt.Fprintf(w, "  %-28s expected %-7s got %s", result.ID, result.Expected, result.Verdict)
+			if result.Error != "" {
+				fmt.Fprintf(w, " (%s)", result.Error)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+
+	if baseline != nil {
+		printFlips(w, run, baseline)
+	}
+}
+
+// printFlips compares verdicts case by case with a baseline run
+func printFlips(w io.Writer, run *Run, baseline *Run) {
+	before := make(map[string]CaseResult, len(baseline.Results))
+	for _, result := range baseline.Results {
+		before[result.ID] = result
+	}
+
+	shared, same := 0, 0
+	var stricter, looser, other []string
+	for _, result := range run.Results {
+		old, ok := before[result.ID]
+		if !ok {
+			continue
+		}
+		shared++
+
+		from, to := normalizeVerdict(old.Verdict), normalizeVerdict(result.Verdict)
+		switch {
+		case from == to:
+			same++
+		case from == "ADVANCE" && to == "REPEAT":
+			stricter = append(stricter, result.ID)
+		case from == "REPEAT" && to == "ADVANCE":
+			looser = append(looser, result.ID)
+		default:
+			other = append(other, fmt.Sprintf("%s (%s -> %s)", result.ID, from, to))
+		}
+	}
+
+	fmt.Fprintf(w, "\nBaseline: %s, %d shared cases\n", baseline.Provider, shared)
+	fmt.Fprintf(w, "  Agreement with baseline: %d/%d (%.1f%%)\n", same, shared, 100*ratio(same, shared))
+	printIDs(w, "Stricter (ADVANCE -> REPEAT)", stricter)
+	printIDs(w, "Looser (REPEAT -> ADVANCE)", looser)
+	printIDs(w, "Other flips", other)
+}
+
+func printIDs(w io.Writer, label string, ids []string) {
+	fmt.Fprintf(w, "  %s: %d\n", label, len(ids))
+	for _, id := range ids {
+		fmt.Fprintf(w, "    %s\n", id)
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,148 @@
+// Command genproblem asks the LLM to draft practice problems, validates each
+// draft's reference solution against its tests in the sandbox and stores it
+// unpublished until an admin approves it under /api/admin/problems/generated.
+// It reads the same environment as the API.
+//
+//	go run ./cmd/genproblem -topic TWO_POINTERS -pattern "opposite-end pointers"
+//	go run ./cmd/genproblem -thin -difficulty Easy
+//	go run ./cmd/genproblem -topic TRIES -dry-run -json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/database"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/prompts"
+	"github.com/yourusername/skilltree/internal/repository"
+	"github.com/yourusername/skilltree/internal/sandbox"
+	"github.com/yourusername/skilltree/internal/service"
+	"github.com/yourusername/skilltree/pkg/httpfixture"
+)
+
+func main() {
+	topic := flag.String("topic", "", "topic key to draft a problem for")
+	thin := flag.Bool("thin", false, "draft one problem for every topic that has none")
+	pattern := flag.String("pattern", "", "technique the problem exercises (defaults to the topic's name)")
+	difficulty := flag.String("difficulty", "Medium", "Easy, Medium or Hard")
+	language := flag.String("language", sandbox.Python, "reference solution language: python or javascript")
+	dryRun := flag.Bool("dry-run", false, "validate drafts without storing them")
+	printJSON := flag.Bool("json", false, "print each draft as JSON")
+	flag.Parse()
+
+	if (*topic == "") == !*thin {
+		log.Fatal("Pass exactly one of -topic or -thin")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if !cfg.SandboxEnabled {
+		log.Fatal("Drafts are validated in the sandbox; set SANDBOX_ENABLED=true")
+	}
+
+	db, err := database.NewMySQLConnection(cfg.GetDSN(), 2)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	registry, err := prompts.Load(cfg.PromptsDir)
+	if err != nil {
+		log.Fatalf("Failed to load prompts: %v", err)
+	}
+	transport, err := httpfixture.NewTransport(cfg.GeminiFixtureMode, cfg.GeminiFixtureDir, nil)
+	if err != nil {
+		log.Fatalf("Failed to set up Gemini fixtures: %v", err)
+	}
+	gemini := service.NewGeminiService(cfg.GeminiAPIKey, cfg.GeminiAPIURL, registry)
+	gemini.SetTransport(transport)
+
+	runner := sandbox.NewRunner(sandbox.Config{
+		NodePath:      cfg.SandboxNodePath,
+		PythonPath:    cfg.SandboxPythonPath,
+		Timeout:       time.Duration(cfg.SandboxTimeoutSeconds) * time.Second,
+		MemoryMB:      cfg.SandboxMemoryMB,
+		MaxInputSize:  cfg.SandboxMaxInputSize,
+		MaxConcurrent: 1,
+		MaxProcesses:  cfg.SandboxMaxProcesses,
+		Isolation:     cfg.SandboxIsolation,
+		UID:           cfg.SandboxUID,
+		GID:           cfg.SandboxGID,
+		BwrapPath:     cfg.SandboxBwrapPath,
+	})
+	if err := runner.Check(context.Background()); err != nil {
+		log.Fatalf("Drafts are validated in an isolated sandbox: %v", err)
+	}
+	problems := service.NewProblemService(repository.NewProblemRepository(db), runner)
+	problems.SetGeminiService(gemini)
+
+	topics := []string{*topic}
+	if *thin {
+		if topics, err = problems.ThinTopics(); err != nil {
+			log.Fatalf("Failed to find topics without problems: %v", err)
+		}
+		log.Printf("%d topics without problems", len(topics))
+	}
+
+	failed := 0
+	for _, topicKey := range topics {
+		problem, err := problems.Generate(context.Background(), "", &models.GenerateProblemRequest{
+			TopicKey:   topicKey,
+			Pattern:    *pattern,
+			Difficulty: *difficulty,
+			Language:   *language,
+			DryRun:     *dryRun,
+		})
+		if err != nil {
+			failed++
+			report(topicKey, err)
+			continue
+		}
+
+		version := problem.Versions[0]
+		fmt.Printf("%s: %q passed %d tests (%s)", topicKey, version.Title, len(version.Validation.Results), version.PromptVersion)
+		if !*dryRun {
+			fmt.Printf(", stored as %s version %d (id %d), pending approval", problem.ProblemID, version.Version, version.ID)
+		}
+		fmt.Println()
+		if *printJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(problem)
+		}
+	}
+
+	if failed > 0 {
+		log.Fatalf("%d of %d drafts failed", failed, len(topics))
+	}
+}
+
+// report prints why a topic's draft was not stored
+func report(topicKey string, err error) {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", topicKey, err)
+
+	var validationErr *service.ProblemValidationError
+	if !errors.As(err, &validationErr) || validationErr.Message != "" {
+		return
+	}
+	for i, result := range validationErr.Validation.Results {
+		if result.Passed {
+			continue
+		}
+		detail := result.Error
+		if detail == "" {
+			detail = "returned " + string(result.Output)
+		}
+		fmt.Fprintf(os.Stderr, "  test %d: %s\n", i+1, detail)
+	}
+}
@@ -0,0 +1,57 @@
+// Package achievements defines badges as declarative rules over domain
+// events. Adding a badge only needs a new entry in Rules; the engine in the
+// service layer evaluates every rule whose events fire.
+package achievements
+
+import "github.com/yourusername/skilltree/internal/events"
+
+// Facts is what rules may ask about the user beyond the event itself.
+// Implementations load each fact at most once per event.
+type Facts interface {
+	// SolvedProblems returns the problem IDs solved in a topic
+	SolvedProblems(topicKey string) ([]string, error)
+	// TopicProblems returns the problem IDs in a topic's catalog, including
+	// published authored problems
+	TopicProblems(topicKey string) ([]string, error)
+	// TotalSolved counts solved problems across all topics
+	TotalSolved() (int, error)
+	// CurrentStreak is the user's daily streak after the event
+	CurrentStreak() (int, error)
+	// TotalXP is the user's XP after the event
+	TotalXP() (int, error)
+	// PassedCheckpoints counts the tier checkpoints passed
+	PassedCheckpoints() (int, error)
+}
+
+// Condition decides whether an event earns a badge
+type Condition func(event events.Event, facts Facts) (bool, error)
+
+// Rule is one badge and when it is earned
+type Rule struct {
+	Key         string
+	Title       string
+	Description string
+	// On lists the event types that can earn the badge
+	On   []string
+	When Condition
+}
+
+// Triggers reports whether an event type can earn the badge
+func (r Rule) Triggers(eventType string) bool {
+	for _, t := range r.On {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the rule with the key, or nil
+func Find(key string) *Rule {
+	for i := range Rules {
+		if Rules[i].Key == key {
+			return &Rules[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package achievements
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yourusername/skilltree/internal/events"
+)
+
+// fakeFacts answers from fixed values and counts how often it was asked
+type fakeFacts struct {
+	solved  map[string][]string
+	catalog map[string][]string
+	total   int
+	streak  int
+	xp      int
+	passed  int
+	err     error
+	calls   int
+}
+
+func (f *fakeFacts) SolvedProblems(topicKey string) ([]string, error) {
+	f.calls++
+	return f.solved[topicKey], f.err
+}
+
+func (f *fakeFacts) TopicProblems(topicKey string) ([]string, error) {
+	f.calls++
+	return f.catalog[topicKey], f.err
+}
+
+func (f *fakeFacts) TotalSolved() (int, error) {
+	f.calls++
+	return f.total, f.err
+}
+
+func (f *fakeFacts) CurrentStreak() (int, error) {
+	f.calls++
+	return f.streak, f.err
+}
+
+func (f *fakeFacts) TotalXP() (int, error) {
+	f.calls++
+	return f.xp, f.err
+}
+
+func (f *fakeFacts) PassedCheckpoints() (int, error) {
+	f.calls++
+	return f.passed, f.err
+}
+
+func TestConditions(t *testing.T) {
+	facts := &fakeFacts{
+		solved: map[string][]string{
+			"HASHING": {"h1", "h2", "extra"},
+			"SORTING": {"s1"},
+		},
+		catalog: map[string][]string{
+			"HASHING": {"h1", "h2"},
+			"SORTING": {"s1", "s2"},
+		},
+		total:  10,
+		streak: 7,
+		xp:     999,
+		passed: 1,
+	}
+	hashing := events.Event{TopicKey: "HASHING"}
+
+	tests := []struct {
+		name  string
+		when  Condition
+		event events.Event
+		want  bool
+	}{
+		{"tier matches", TierIs(3), events.Event{Tier: 3}, true},
+		{"tier differs", TierIs(3), events.Event{Tier: 2}, false},
+		{"first try", FirstTry(), events.Event{AttemptNumber: 1}, true},
+		{"second try", FirstTry(), events.Event{AttemptNumber: 2}, false},
+		{"difficulty matches", DifficultyIs("Hard"), events.Event{Difficulty: "Hard"}, true},
+		{"difficulty is case sensitive", DifficultyIs("Hard"), events.Event{Difficulty: "hard"}, false},
+		{"topic complete", SolvedAllInTopic("HASHING"), hashing, true},
+		{"topic incomplete", SolvedAllInTopic("SORTING"), events.Event{TopicKey: "SORTING"}, false},
+		{"event from another topic", SolvedAllInTopic("SORTING"), hashing, false},
+		{"topic without problems", SolvedAllInTopic("TRIES"), events.Event{TopicKey: "TRIES"}, false},
+		{"solved at the threshold", SolvedAtLeast(10), events.Event{}, true},
+		{"solved below the threshold", SolvedAtLeast(11), events.Event{}, false},
+		{"streak at the threshold", StreakAtLeast(7), events.Event{}, true},
+		{"streak below the threshold", StreakAtLeast(30), events.Event{}, false},
+		{"xp one short", XPAtLeast(1000), events.Event{}, false},
+		{"checkpoints passed", CheckpointsPassedAtLeast(1), events.Event{}, true},
+		{"all hold", All(TierIs(3), FirstTry()), events.Event{Tier: 3, AttemptNumber: 1}, true},
+		{"one fails", All(TierIs(3), FirstTry()), events.Event{Tier: 3, AttemptNumber: 2}, false},
+		{"nothing to check", All(), events.Event{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.when(tt.event, facts)
+			if err != nil {
+				t.Fatalf("condition: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("condition = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// All stops at the first failing condition, so facts behind it are never loaded
+func TestAllShortCircuits(t *testing.T) {
+	facts := &fakeFacts{total: 100}
+	ok, err := All(TierIs(3), SolvedAtLeast(1))(events.Event{Tier: 1}, facts)
+	if ok || err != nil {
+		t.Fatalf("All() = %v, %v; want false, nil", ok, err)
+	}
+	if facts.calls != 0 {
+		t.Errorf("facts loaded %d times after a failed event check, want 0", facts.calls)
+	}
+}
+
+func TestConditionsReportFactErrors(t *testing.T) {
+	boom := errors.New("database is down")
+	facts := &fakeFacts{err: boom}
+	event := events.Event{TopicKey: "HASHING"}
+
+	conditions := map[string]Condition{
+		"SolvedAllInTopic":         SolvedAllInTopic("HASHING"),
+		"SolvedAtLeast":            SolvedAtLeast(1),
+		"StreakAtLeast":            StreakAtLeast(1),
+		"XPAtLeast":                XPAtLeast(1),
+		"CheckpointsPassedAtLeast": CheckpointsPassedAtLeast(1),
+		"All":                      All(SolvedAtLeast(0)),
+	}
+	for name, when := range conditions {
+		ok, err := when(event, facts)
+		if ok || !errors.Is(err, boom) {
+			t.Errorf("%s = %v, %v; want false, %v", name, ok, err, boom)
+		}
+	}
+}
+
+func TestRulesCatalog(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, rule := range Rules {
+		if rule.Key == "" || rule.Title == "" || rule.Description == "" {
+			t.Errorf("rule %q is missing a key, title or description", rule.Key)
+		}
+		if seen[rule.Key] {
+			t.Errorf("rule key %q is used twice", rule.Key)
+		}
+		seen[rule.Key] = true
+		if len(rule.On) == 0 || rule.When == nil {
+			t.Errorf("rule %q can never be earned", rule.Key)
+		}
+		if found := Find(rule.Key); found == nil || found.Key != rule.Key {
+			t.Errorf("Find(%q) = %v", rule.Key, found)
+		}
+	}
+	if Find("no_such_badge") != nil {
+		t.Error("Find() of an unknown key returned a rule")
+	}
+}
+
+func TestRuleTriggers(t *testing.T) {
+	rule := Rule{On: []string{events.PracticeSolved, events.CheckpointPassed}}
+	tests := []struct {
+		eventType string
+		want      bool
+	}{
+		{events.PracticeSolved, true},
+		{events.CheckpointPassed, true},
+		{events.PracticeSubmitted, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := rule.Triggers(tt.eventType); got != tt.want {
+			t.Errorf("Triggers(%q) = %v, want %v", tt.eventType, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package achievements
+
+import "github.com/yourusername/skilltree/internal/events"
+
+// All holds when every condition holds; later conditions are skipped once
+// one fails, so put cheap event checks first
+func All(conditions ...Condition) Condition {
+	return func(event events.Event, facts Facts) (bool, error) {
+		for _, c := range conditions {
+			ok, err := c(event, facts)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+}
+
+// TierIs holds for checkpoint events of the tier
+func TierIs(tier int) Condition {
+	return func(event events.Event, _ Facts) (bool, error) {
+		return event.Tier == tier, nil
+	}
+}
+
+// FirstTry holds for a checkpoint passed on the first attempt
+func FirstTry() Condition {
+	return func(event events.Event, _ Facts) (bool, error) {
+		return event.AttemptNumber == 1, nil
+	}
+}
+
+// DifficultyIs holds for practice events on problems of the difficulty
+func DifficultyIs(diff string) Condition {
+	return func(event events.Event, _ Facts) (bool, error) {
+		return event.Difficulty == diff, nil
+	}
+}
+
+// SolvedAllInTopic holds once every catalog problem in the topic is solved.
+// A topic without problems never completes.
+func SolvedAllInTopic(topicKey string) Condition {
+	return func(event events.Event, facts Facts) (bool, error) {
+		if event.TopicKey != topicKey {
+			return false, nil
+		}
+		problems, err := facts.TopicProblems(topicKey)
+		if err != nil || len(problems) == 0 {
+			return false, err
+		}
+		solved, err := facts.SolvedProblems(topicKey)
+		if err != nil {
+			return false, err
+		}
+		have := make(map[string]bool, len(solved))
+		for _, id := range solved {
+			have[id] = true
+		}
+		for _, id := range problems {
+			if !have[id] {
+				return false, nil
+			}
+		}
+		return true, nil
+	}
+}
+
+// SolvedAtLeast holds once n problems are solved across all topics
+func SolvedAtLeast(n int) Condition {
+	return func(_ events.Event, facts Facts) (bool, error) {
+		solved, err := facts.TotalSolved()
+		return solved >= n, err
+	}
+}
+
+// StreakAtLeast holds once the daily streak reaches n days
+func StreakAtLeast(n int) Condition {
+	return func(_ events.Event, facts Facts) (bool, error) {
+		streak, err := facts.CurrentStreak()
+		return streak >= n, err
+	}
+}
+
+// XPAtLeast holds once the user has n XP
+func XPAtLeast(n int) Condition {
+	return func(_ events.Event, facts Facts) (bool, error) {
+		xp, err := facts.TotalXP()
+		return xp >= n, err
+	}
+}
+
+// CheckpointsPassedAtLeast holds once n tier checkpoints are passed
+func CheckpointsPassedAtLeast(n int) Condition {
+	return func(_ events.Event, facts Facts) (bool, error) {
+		passed, err := facts.PassedCheckpoints()
+		return passed >= n, err
+	}
+}
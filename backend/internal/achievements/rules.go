@@ -0,0 +1,98 @@
+package achievements
+
+import (
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/events"
+)
+
+// activity is every event that counts towards the daily streak
+var activity = []string{events.PracticeSubmitted, events.CheckpointAttempted}
+
+// Rules is the badge catalog. Keys are stored with awards, so never rename
+// one; retire a badge by removing its rule.
+var Rules = []Rule{
+	{
+		Key:         "first_solve",
+		Title:       "First Steps",
+		Description: "Solve your first practice problem",
+		On:          []string{events.PracticeSolved},
+		When:        SolvedAtLeast(1),
+	},
+	{
+		Key:         "ten_solves",
+		Title:       "Getting Warm",
+		Description: "Solve 10 practice problems",
+		On:          []string{events.PracticeSolved},
+		When:        SolvedAtLeast(10),
+	},
+	{
+		Key:         "first_hard",
+		Title:       "Up for a Challenge",
+		Description: "Solve a Hard practice problem",
+		On:          []string{events.PracticeSolved},
+		When:        DifficultyIs("Hard"),
+	},
+	{
+		Key:         "array_scan_complete",
+		Title:       "Scanner",
+		Description: "Solve every ARRAY_SCAN problem",
+		On:          []string{events.PracticeSolved},
+		When:        SolvedAllInTopic("ARRAY_SCAN"),
+	},
+	{
+		Key:         "sorting_complete",
+		Title:       "In Order",
+		Description: "Solve every SORTING problem",
+		On:          []string{events.PracticeSolved},
+		When:        SolvedAllInTopic("SORTING"),
+	},
+	{
+		Key:         "hashing_complete",
+		Title:       "Hash It Out",
+		Description: "Solve every HASHING problem",
+		On:          []string{events.PracticeSolved},
+		When:        SolvedAllInTopic("HASHING"),
+	},
+	{
+		Key:         "first_checkpoint",
+		Title:       "Gatekeeper",
+		Description: "Pass a tier checkpoint",
+		On:          []string{events.CheckpointPassed},
+		When:        CheckpointsPassedAtLeast(1),
+	},
+	{
+		Key:         "tier3_first_try",
+		Title:       "Clean Sweep",
+		Description: "Pass the tier 3 checkpoint on your first attempt",
+		On:          []string{events.CheckpointPassed},
+		When:        All(TierIs(3), FirstTry()),
+	},
+	{
+		Key:         "all_checkpoints",
+		Title:       "Summit",
+		Description: "Pass every tier checkpoint",
+		On:          []string{events.CheckpointPassed},
+		When:        CheckpointsPassedAtLeast(len(data.CheckpointPools)),
+	},
+	{
+		Key:         "streak_7",
+		Title:       "On a Roll",
+		Description: "Keep a 7-day streak",
+		On:          activity,
+		When:        StreakAtLeast(7),
+	},
+	{
+		Key:         "streak_30",
+		Title:       "Habit Formed",
+		Description: "Keep a 30-day streak",
+		On:          activity,
+		When:        StreakAtLeast(30),
+	},
+	{
+		Key:         "xp_1000",
+		Title:       "Four Digits",
+		Description: "Earn 1,000 XP",
+		On:          []string{events.PracticeSolved, events.CheckpointPassed},
+		When:        XPAtLeast(1000),
+	},
+}
@@ -0,0 +1,106 @@
+package complexity
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	timeLabel  = regexp.MustCompile(`(?i)\btime\b`)
+	spaceLabel = regexp.MustCompile(`(?i)\b(space|memory)\b`)
+)
+
+// Claim is the Big-O an analysis states for time and space; a field is
+// empty when the analysis doesn't state it in terms of a single n
+type Claim struct {
+	Time  string `json:"time,omitempty"`
+	Space string `json:"space,omitempty"`
+}
+
+// ParseClaim reads the time and space complexity out of a free-text
+// analysis such as "**Time Complexity:** O(n log n)". Each label takes the
+// first O(...) that follows it on the same line.
+func ParseClaim(analysis string) Claim {
+	var claim Claim
+	for _, line := range strings.Split(analysis, "\n") {
+		if claim.Time == "" {
+			if loc := timeLabel.FindStringIndex(line); loc != nil {
+				claim.Time = Normalize(firstBigO(line[loc[1]:]))
+			}
+		}
+		if claim.Space == "" {
+			if loc := spaceLabel.FindStringIndex(line); loc != nil {
+				claim.Space = Normalize(firstBigO(line[loc[1]:]))
+			}
+		}
+	}
+	return claim
+}
+
+// firstBigO returns the first O(...) expression in s, balancing nested
+// parentheses such as O(n log(n))
+func firstBigO(s string) string {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] != 'O' || s[i+1] != '(' {
+			continue
+		}
+		if i > 0 && isWordChar(s[i-1]) {
+			continue
+		}
+		depth := 0
+		for j := i + 1; j < len(s); j++ {
+			switch s[j] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+				if depth == 0 {
+					return s[i : j+1]
+				}
+			}
+		}
+		return ""
+	}
+	return ""
+}
+
+func isWordChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// A replacer doesn't rescan its own output, so decoration is stripped
+// before the log spellings are unified
+var (
+	notationStripper = strings.NewReplacer(
+		" ", "", "*", "", "·", "", "\\", "", "{", "", "}", "", "cdot", "",
+		"²", "^2", "³", "^3",
+	)
+	logReplacer = strings.NewReplacer("log(n)", "logn", "log2n", "logn", "lgn", "logn")
+)
+
+// Normalize maps a Big-O expression onto one of the class constants, or
+// returns "" if it isn't a single-variable class this package knows
+func Normalize(bigO string) string {
+	if !strings.HasPrefix(bigO, "O(") || !strings.HasSuffix(bigO, ")") {
+		return ""
+	}
+	expr := logReplacer.Replace(notationStripper.Replace(strings.ToLower(bigO[2 : len(bigO)-1])))
+
+	switch expr {
+	case "1":
+		return Constant
+	case "logn":
+		return Logarithmic
+	case "n":
+		return Linear
+	case "nlogn":
+		return Linearithmic
+	case "n^2", "nn":
+		return Quadratic
+	case "n^3", "nnn":
+		return Cubic
+	case "2^n":
+		return Exponential
+	}
+	return ""
+}
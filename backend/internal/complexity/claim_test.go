@@ -0,0 +1,85 @@
+package complexity
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		bigO string
+		want string
+	}{
+		{"O(1)", Constant},
+		{"O(log n)", Logarithmic},
+		{"O(log(n))", Logarithmic},
+		{"O(lg n)", Logarithmic},
+		{"O(N)", Linear},
+		{"O(n log n)", Linearithmic},
+		{"O(n \\cdot \\log n)", Linearithmic},
+		{"O(n * log2 n)", Linearithmic},
+		{"O(n²)", Quadratic},
+		{"O(n^{2})", Quadratic},
+		{"O(n * n)", Quadratic},
+		{"O(n³)", Cubic},
+		{"O(2^n)", Exponential},
+		{"O(n + m)", ""},
+		{"O(V + E)", ""},
+		{"n log n", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.bigO); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.bigO, got, tt.want)
+		}
+	}
+}
+
+func TestParseClaim(t *testing.T) {
+	tests := []struct {
+		name     string
+		analysis string
+		want     Claim
+	}{
+		{"empty", "", Claim{}},
+		{
+			"markdown labels",
+			"**Time Complexity:** O(n log n)\n**Space Complexity:** O(n)",
+			Claim{Time: Linearithmic, Space: Linear},
+		},
+		{
+			"memory label and nested parentheses",
+			"Time: O(n log(n)) because of the sort\nMemory: O(1) extra",
+			Claim{Time: Linearithmic, Space: Constant},
+		},
+		{
+			"first claim wins",
+			"Time: O(n^2)\nAfter optimizing, time drops to O(n)",
+			Claim{Time: Quadratic},
+		},
+		{
+			"multi-variable claim is left empty",
+			"Time: O(V + E)\nSpace: O(V)",
+			Claim{},
+		},
+		{
+			"O inside a word is not a claim",
+			"Time: FOO(n) then O(n)",
+			Claim{Time: Linear},
+		},
+		{
+			"unbalanced parenthesis",
+			"Time: O(n log n",
+			Claim{},
+		},
+		{
+			"label without a claim",
+			"This runs in linear time.",
+			Claim{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseClaim(tt.analysis); got != tt.want {
+				t.Errorf("ParseClaim() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
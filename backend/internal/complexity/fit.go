@@ -0,0 +1,183 @@
+// Package complexity estimates Big-O classes from measured growth curves and
+// reads Big-O claims out of free-text analyses.
+package complexity
+
+import (
+	"math"
+	"sort"
+)
+
+// Complexity classes in increasing order of growth
+const (
+	Constant     = "O(1)"
+	Logarithmic  = "O(log n)"
+	Linear       = "O(n)"
+	Linearithmic = "O(n log n)"
+	Quadratic    = "O(n^2)"
+	Cubic        = "O(n^3)"
+	Exponential  = "O(2^n)"
+)
+
+// MinPoints is the fewest measurements a fit needs
+const MinPoints = 4
+
+// classes are the growth models fitted, in increasing order of growth.
+// Exponential growth is never fitted: it times out long before enough
+// doubling sizes are measured.
+var classes = []struct {
+	name string
+	f    func(n float64) float64
+}{
+	{Logarithmic, func(n float64) float64 { return math.Log2(n) }},
+	{Linear, func(n float64) float64 { return n }},
+	{Linearithmic, func(n float64) float64 { return n * math.Log2(n) }},
+	{Quadratic, func(n float64) float64 { return n * n }},
+	{Cubic, func(n float64) float64 { return n * n * n }},
+}
+
+// Point is one measurement: cost Y at input size N
+type Point struct {
+	N float64
+	Y float64
+}
+
+// Fit is the best-fitting class for a growth curve
+type Fit struct {
+	Class string `json:"class"`
+	// Alternatives are neighbouring classes that fit almost as well; the
+	// measurements can't tell them apart
+	Alternatives []string `json:"alternatives,omitempty"`
+	// Error is the mean squared relative error of the best fit
+	Error float64 `json:"error"`
+}
+
+// Matches reports whether class is the fit or one of its alternatives
+func (f *Fit) Matches(class string) bool {
+	if f.Class == class {
+		return true
+	}
+	for _, alt := range f.Alternatives {
+		if alt == class {
+			return true
+		}
+	}
+	return false
+}
+
+// Options tune how flat a curve must be to count as constant
+type Options struct {
+	// FlatRatio is the largest last/first cost ratio treated as constant
+	FlatRatio float64
+	// FlatSpread is the largest absolute cost range treated as constant
+	FlatSpread float64
+	// Floor is added to every cost before weighting, so near-zero
+	// measurements don't dominate the relative error
+	Floor float64
+}
+
+// TimeOptions suit runtimes in milliseconds
+var TimeOptions = Options{FlatRatio: 1.5, Floor: 0.001}
+
+// MemoryOptions suit memory in bytes: anything within 32 KiB is noise from
+// the interpreter rather than growth
+var MemoryOptions = Options{FlatRatio: 1.5, FlatSpread: 32 * 1024, Floor: 1024}
+
+// FitCurve picks the complexity class whose curve a·f(n) + b best matches
+// the points by relative error. It returns nil with fewer than MinPoints.
+func FitCurve(points []Point, opts Options) *Fit {
+	if len(points) < MinPoints {
+		return nil
+	}
+
+	sorted := append([]Point(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].N < sorted[j].N })
+
+	minY, maxY := sorted[0].Y, sorted[0].Y
+	for _, p := range sorted {
+		minY = math.Min(minY, p.Y)
+		maxY = math.Max(maxY, p.Y)
+	}
+	first, last := sorted[0].Y+opts.Floor, sorted[len(sorted)-1].Y+opts.Floor
+	if maxY-minY <= opts.FlatSpread || last/first <= opts.FlatRatio {
+		return &Fit{Class: Constant}
+	}
+
+	type candidate struct {
+		rank int
+		err  float64
+	}
+	var candidates []candidate
+	for rank, class := range classes {
+		if err, ok := fitClass(sorted, class.f, opts.Floor); ok {
+			candidates = append(candidates, candidate{rank: rank, err: err})
+		}
+	}
+	if len(candidates) == 0 {
+		return &Fit{Class: Constant}
+	}
+
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.err < best.err {
+			best = c
+		}
+	}
+
+	fit := &Fit{Class: classes[best.rank].name, Error: best.err}
+	for _, c := range candidates {
+		adjacent := c.rank == best.rank-1 || c.rank == best.rank+1
+		if adjacent && c.err <= best.err*1.5+0.005 {
+			fit.Alternatives = append(fit.Alternatives, classes[c.rank].name)
+		}
+	}
+	return fit
+}
+
+// fitClass fits y = a·f(n) + b by least squares weighted for relative
+// error and returns the mean squared relative error. ok is false when the
+// best fit doesn't grow (a <= 0).
+func fitClass(points []Point, f func(float64) float64, floor float64) (float64, bool) {
+	var sw, sx, sy, sxx, sxy float64
+	for _, p := range points {
+		w := 1 / math.Pow(p.Y+floor, 2)
+		x := f(p.N)
+		sw += w
+		sx += w * x
+		sy += w * p.Y
+		sxx += w * x * x
+		sxy += w * x * p.Y
+	}
+
+	den := sw*sxx - sx*sx
+	if den == 0 {
+		return 0, false
+	}
+	a := (sw*sxy - sx*sy) / den
+	b := (sy - a*sx) / sw
+	if a <= 0 {
+		return 0, false
+	}
+
+	var sum float64
+	for _, p := range points {
+		rel := (p.Y - (a*f(p.N) + b)) / (p.Y + floor)
+		sum += rel * rel
+	}
+	return sum / float64(len(points)), true
+}
+
+// Rank orders classes by growth; unknown classes rank -1
+func Rank(class string) int {
+	switch class {
+	case Constant:
+		return 0
+	case Exponential:
+		return len(classes) + 1
+	}
+	for i, c := range classes {
+		if c.name == class {
+			return i + 1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,83 @@
+package complexity
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// curve measures cost(n) at the doubling sizes the sandbox uses
+func curve(cost func(n float64) float64) []Point {
+	var points []Point
+	for n := 256.0; n <= 8192; n *= 2 {
+		points = append(points, Point{N: n, Y: cost(n)})
+	}
+	return points
+}
+
+func TestFitCurve(t *testing.T) {
+	tests := []struct {
+		name string
+		cost func(n float64) float64
+		opts Options
+		want string
+	}{
+		{"flat runtime", func(n float64) float64 { return 0.2 }, TimeOptions, Constant},
+		{"runtime within the flat ratio", func(n float64) float64 { return 1 + n/100000 }, TimeOptions, Constant},
+		{"memory noise below the spread", func(n float64) float64 { return 1e6 + n }, MemoryOptions, Constant},
+		{"logarithmic", func(n float64) float64 { return 0.01 * math.Log2(n) }, Options{Floor: 0.001}, Logarithmic},
+		{"linear with start-up cost", func(n float64) float64 { return 0.5 + 0.001*n }, TimeOptions, Linear},
+		{"linearithmic", func(n float64) float64 { return 0.0001 * n * math.Log2(n) }, TimeOptions, Linearithmic},
+		{"quadratic", func(n float64) float64 { return 1e-6 * n * n }, TimeOptions, Quadratic},
+		{"cubic", func(n float64) float64 { return 1e-9 * n * n * n }, TimeOptions, Cubic},
+		{"linear memory", func(n float64) float64 { return 4e5 + 64*n }, MemoryOptions, Linear},
+		{"shrinking cost", func(n float64) float64 { return 100 / n }, TimeOptions, Constant},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fit := FitCurve(curve(tt.cost), tt.opts)
+			if fit == nil {
+				t.Fatal("FitCurve() = nil")
+			}
+			if !fit.Matches(tt.want) {
+				t.Errorf("FitCurve() = %s (alternatives %v), want %s", fit.Class, fit.Alternatives, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitCurveTooFewPoints(t *testing.T) {
+	points := curve(func(n float64) float64 { return n })[:MinPoints-1]
+	if fit := FitCurve(points, TimeOptions); fit != nil {
+		t.Errorf("FitCurve() with %d points = %+v, want nil", len(points), fit)
+	}
+}
+
+func TestFitCurveIgnoresOrder(t *testing.T) {
+	points := curve(func(n float64) float64 { return 1e-6 * n * n })
+	slices.Reverse(points)
+	if fit := FitCurve(points, TimeOptions); fit == nil || fit.Class != Quadratic {
+		t.Errorf("FitCurve() of reversed points = %+v, want %s", fit, Quadratic)
+	}
+}
+
+func TestFitMatches(t *testing.T) {
+	fit := &Fit{Class: Linear, Alternatives: []string{Linearithmic}}
+	for class, want := range map[string]bool{Linear: true, Linearithmic: true, Quadratic: false, "": false} {
+		if got := fit.Matches(class); got != want {
+			t.Errorf("Matches(%q) = %v, want %v", class, got, want)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	ordered := []string{Constant, Logarithmic, Linear, Linearithmic, Quadratic, Cubic, Exponential}
+	for i, class := range ordered {
+		if got := Rank(class); got != i {
+			t.Errorf("Rank(%s) = %d, want %d", class, got, i)
+		}
+	}
+	if got := Rank("O(n!)"); got != -1 {
+		t.Errorf("Rank(O(n!)) = %d, want -1", got)
+	}
+}
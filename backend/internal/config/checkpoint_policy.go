@@ -0,0 +1,63 @@
+package config
+
+import "time"
+
+// CheckpointPolicy controls how often a learner may attempt a tier checkpoint
+type CheckpointPolicy struct {
+	// FailuresBeforeCooldown is the number of consecutive failed attempts
+	// allowed before a cooldown kicks in
+	FailuresBeforeCooldown int
+	// Cooldown is the wait imposed once FailuresBeforeCooldown is reached
+	Cooldown time.Duration
+	// ExponentialBackoff doubles the cooldown for every further failure
+	ExponentialBackoff bool
+	// MaxCooldown caps the cooldown when ExponentialBackoff is enabled
+	MaxCooldown time.Duration
+	// DailyAttemptCap limits attempts per UTC day (0 means unlimited)
+	DailyAttemptCap int
+	// TimeLimit is how long a checkpoint session stays open for submission
+	TimeLimit time.Duration
+}
+
+// CheckpointSubmitGrace absorbs network latency on submissions sent right at the deadline
+const CheckpointSubmitGrace = 10 * time.Second
+
+// CheckpointPolicies holds the attempt policy for each tier (0-6).
+// Higher tiers are stricter since their problems are more expensive to judge.
+var CheckpointPolicies = map[int]CheckpointPolicy{
+	0: {FailuresBeforeCooldown: 3, Cooldown: 5 * time.Minute, DailyAttemptCap: 10, TimeLimit: 30 * time.Minute},
+	1: {FailuresBeforeCooldown: 3, Cooldown: 10 * time.Minute, DailyAttemptCap: 10, TimeLimit: 45 * time.Minute},
+	2: {FailuresBeforeCooldown: 3, Cooldown: 15 * time.Minute, ExponentialBackoff: true, MaxCooldown: 4 * time.Hour, DailyAttemptCap: 8, TimeLimit: 45 * time.Minute},
+	3: {FailuresBeforeCooldown: 2, Cooldown: 30 * time.Minute, ExponentialBackoff: true, MaxCooldown: 6 * time.Hour, DailyAttemptCap: 6, TimeLimit: 60 * time.Minute},
+	4: {FailuresBeforeCooldown: 2, Cooldown: 30 * time.Minute, ExponentialBackoff: true, MaxCooldown: 12 * time.Hour, DailyAttemptCap: 5, TimeLimit: 60 * time.Minute},
+	5: {FailuresBeforeCooldown: 2, Cooldown: time.Hour, ExponentialBackoff: true, MaxCooldown: 24 * time.Hour, DailyAttemptCap: 4, TimeLimit: 75 * time.Minute},
+	6: {FailuresBeforeCooldown: 2, Cooldown: time.Hour, ExponentialBackoff: true, MaxCooldown: 24 * time.Hour, DailyAttemptCap: 3, TimeLimit: 90 * time.Minute},
+}
+
+// GetCheckpointPolicy returns the policy for a tier, falling back to the strictest one
+func GetCheckpointPolicy(tier int) CheckpointPolicy {
+	if policy, ok := CheckpointPolicies[tier]; ok {
+		return policy
+	}
+	return CheckpointPolicies[6]
+}
+
+// CooldownAfter returns how long a learner must wait after the given number
+// of consecutive failures (zero when no cooldown applies)
+func (p CheckpointPolicy) CooldownAfter(consecutiveFailures int) time.Duration {
+	if p.FailuresBeforeCooldown <= 0 || consecutiveFailures < p.FailuresBeforeCooldown {
+		return 0
+	}
+
+	cooldown := p.Cooldown
+	if p.ExponentialBackoff {
+		for i := p.FailuresBeforeCooldown; i < consecutiveFailures; i++ {
+			cooldown *= 2
+			if p.MaxCooldown > 0 && cooldown >= p.MaxCooldown {
+				return p.MaxCooldown
+			}
+		}
+	}
+
+	return cooldown
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -28,6 +31,75 @@ type Config struct {
 	GeminiAPIKey string
 	GeminiAPIURL string
 
+	// Gemini HTTP fixtures: off, record or replay
+	GeminiFixtureMode string
+	GeminiFixtureDir  string
+
+	// Directory of prompt template overrides (empty = embedded defaults)
+	PromptsDir string
+
+	// Gemini circuit breaker
+	GeminiBreakerFailures        int
+	GeminiBreakerCooldownSeconds int
+
+	// Checkpoint judge consensus
+	JudgeConsensusVotes  int
+	JudgeConsensusMode   string
+	JudgePanelAPIURLs    []string
+	// JudgePanelPromptDirs[i] overrides the prompts of panel member i
+	// (empty entries use PROMPTS_DIR)
+	JudgePanelPromptDirs []string
+
+	// AI rate limiting and quotas
+	RateLimitStore            string
+	AIRateLimitPerMinute      int
+	AIRateLimitBurst          int
+	AIJudgeRateLimitPerMinute int
+	AIQuizRateLimitPerMinute  int
+	LLMDailyTokenBudget       int
+	// AIRequestTimeoutSeconds bounds judge, quiz and sandbox requests
+	AIRequestTimeoutSeconds int
+
+	// AI response cache
+	AICacheStore      string
+	AICacheTTLMinutes int
+	AICacheMaxEntries int
+
+	// Hint ladder: percent of judge credit lost per highest hint level used
+	HintCreditPenalties []int
+
+	// Sandbox for empirical complexity profiling
+	SandboxEnabled        bool
+	SandboxNodePath       string
+	SandboxPythonPath     string
+	SandboxTimeoutSeconds int
+	SandboxMemoryMB       int
+	SandboxMaxInputSize   int
+	SandboxMaxConcurrent  int
+	SandboxMaxProcesses   int
+	SandboxIsolation      string
+	SandboxUID            int
+	SandboxGID            int
+	SandboxBwrapPath      string
+
+	// Checkpoint similarity detection
+	SimilarityThresholdPercent int
+	SimilarityMinFingerprints  int
+
+	// AI-generation risk and verification quizzes
+	AIRiskQuizThreshold int
+	QuizQuestions       int
+	QuizPassPercent     int
+	QuizTTLMinutes      int
+
+	// Explain quizzes after practice passes ("*" for every topic)
+	PracticeQuizTopics          []string
+	QuizUnverifiedCreditPenalty int
+
+	// Daily streak freeze tokens
+	StreakFreezeIntervalDays int
+	StreakFreezeMaxTokens    int
+
 	// CORS
 	CORSAllowedOrigins string
 }
@@ -53,6 +125,60 @@ func Load() (*Config, error) {
 		GeminiAPIKey: getEnv("GEMINI_API_KEY", ""),
 		GeminiAPIURL: getEnv("GEMINI_API_URL", "https://generativelanguage.googleapis.com/v1beta/models/gemini-2.5-flash-preview-09-2025:generateContent"),
 
+		GeminiFixtureMode: getEnv("GEMINI_FIXTURE_MODE", "off"),
+		GeminiFixtureDir:  getEnv("GEMINI_FIXTURE_DIR", "fixtures/gemini"),
+
+		PromptsDir: getEnv("PROMPTS_DIR", ""),
+
+		GeminiBreakerFailures:        getEnvInt("GEMINI_BREAKER_FAILURES", 5),
+		GeminiBreakerCooldownSeconds: getEnvInt("GEMINI_BREAKER_COOLDOWN_SECONDS", 30),
+
+		JudgeConsensusVotes:  getEnvInt("JUDGE_CONSENSUS_VOTES", 1),
+		JudgeConsensusMode:   getEnv("JUDGE_CONSENSUS_MODE", "majority"),
+		JudgePanelAPIURLs:    getEnvList("JUDGE_PANEL_API_URLS"),
+		JudgePanelPromptDirs: getEnvFields("JUDGE_PANEL_PROMPT_DIRS"),
+
+		RateLimitStore:            getEnv("RATE_LIMIT_STORE", "memory"),
+		AIRateLimitPerMinute:      getEnvInt("AI_RATE_LIMIT_PER_MINUTE", 10),
+		AIRateLimitBurst:          getEnvInt("AI_RATE_LIMIT_BURST", 5),
+		AIJudgeRateLimitPerMinute: getEnvInt("AI_JUDGE_RATE_LIMIT_PER_MINUTE", 4),
+		AIQuizRateLimitPerMinute:  getEnvInt("AI_QUIZ_RATE_LIMIT_PER_MINUTE", 4),
+		LLMDailyTokenBudget:       getEnvInt("LLM_DAILY_TOKEN_BUDGET", 200000),
+		AIRequestTimeoutSeconds:   getEnvInt("AI_REQUEST_TIMEOUT_SECONDS", 60),
+
+		AICacheStore:      getEnv("AI_CACHE_STORE", "memory"),
+		AICacheTTLMinutes: getEnvInt("AI_CACHE_TTL_MINUTES", 24*60),
+		AICacheMaxEntries: getEnvInt("AI_CACHE_MAX_ENTRIES", 1000),
+
+		HintCreditPenalties: getEnvIntList("HINT_CREDIT_PENALTIES", []int{10, 25, 50}),
+
+		SandboxEnabled:        getEnv("SANDBOX_ENABLED", "false") == "true",
+		SandboxNodePath:       getEnv("SANDBOX_NODE_PATH", "node"),
+		SandboxPythonPath:     getEnv("SANDBOX_PYTHON_PATH", "python3"),
+		SandboxTimeoutSeconds: getEnvInt("SANDBOX_TIMEOUT_SECONDS", 10),
+		SandboxMemoryMB:       getEnvInt("SANDBOX_MEMORY_MB", 256),
+		SandboxMaxInputSize:   getEnvInt("SANDBOX_MAX_INPUT_SIZE", 65536),
+		SandboxMaxConcurrent:  getEnvInt("SANDBOX_MAX_CONCURRENT", 2),
+		SandboxMaxProcesses:   getEnvInt("SANDBOX_MAX_PROCESSES", 64),
+		SandboxIsolation:      getEnv("SANDBOX_ISOLATION", ""),
+		SandboxUID:            getEnvInt("SANDBOX_UID", 65534),
+		SandboxGID:            getEnvInt("SANDBOX_GID", 65534),
+		SandboxBwrapPath:      getEnv("SANDBOX_BWRAP_PATH", "bwrap"),
+
+		SimilarityThresholdPercent: getEnvInt("SIMILARITY_THRESHOLD_PERCENT", 80),
+		SimilarityMinFingerprints:  getEnvInt("SIMILARITY_MIN_FINGERPRINTS", 15),
+
+		AIRiskQuizThreshold: getEnvInt("AI_RISK_QUIZ_THRESHOLD", 60),
+		QuizQuestions:       getEnvInt("QUIZ_QUESTIONS", 3),
+		QuizPassPercent:     getEnvInt("QUIZ_PASS_PERCENT", 70),
+		QuizTTLMinutes:      getEnvInt("QUIZ_TTL_MINUTES", 30),
+
+		PracticeQuizTopics:          getEnvList("PRACTICE_QUIZ_TOPICS"),
+		QuizUnverifiedCreditPenalty: getEnvInt("QUIZ_UNVERIFIED_CREDIT_PENALTY", 50),
+
+		StreakFreezeIntervalDays: getEnvInt("STREAK_FREEZE_INTERVAL_DAYS", 7),
+		StreakFreezeMaxTokens:    getEnvInt("STREAK_FREEZE_MAX_TOKENS", 2),
+
 		CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173"),
 	}
 
@@ -63,9 +189,29 @@ func Load() (*Config, error) {
 	if cfg.FirebaseProjectID == "" {
 		return nil, fmt.Errorf("FIREBASE_PROJECT_ID is required")
 	}
-	if cfg.GeminiAPIKey == "" {
+	// Replay mode serves recorded responses, so no key is needed
+	if cfg.GeminiAPIKey == "" && cfg.GeminiFixtureMode != "replay" {
 		return nil, fmt.Errorf("GEMINI_API_KEY is required")
 	}
+	if len(cfg.HintCreditPenalties) != 3 {
+		return nil, fmt.Errorf("HINT_CREDIT_PENALTIES must list one percentage per hint level (3)")
+	}
+	for _, penalty := range cfg.HintCreditPenalties {
+		if penalty < 0 || penalty > 100 {
+			return nil, fmt.Errorf("HINT_CREDIT_PENALTIES must be between 0 and 100")
+		}
+	}
+	if cfg.QuizQuestions < 1 {
+		return nil, fmt.Errorf("QUIZ_QUESTIONS must be at least 1")
+	}
+	if cfg.QuizUnverifiedCreditPenalty < 0 || cfg.QuizUnverifiedCreditPenalty > 100 {
+		return nil, fmt.Errorf("QUIZ_UNVERIFIED_CREDIT_PENALTY must be between 0 and 100")
+	}
+	for _, topic := range cfg.PracticeQuizTopics {
+		if topic != "*" && !slices.Contains(AllTopics, topic) {
+			return nil, fmt.Errorf("PRACTICE_QUIZ_TOPICS: unknown topic %q", topic)
+		}
+	}
 
 	return cfg, nil
 }
@@ -77,6 +223,59 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated variable, dropping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvFields splits a comma-separated variable keeping empty entries, for
+// lists whose positions line up with another list
+func getEnvFields(key string) []string {
+	value := os.Getenv(key)
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	fields := strings.Split(value, ",")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
+
+// getEnvIntList parses a comma-separated list of integers, falling back to
+// the default if the variable is unset or malformed
+func getEnvIntList(key string, defaultValue []int) []int {
+	values := getEnvList(key)
+	if len(values) == 0 {
+		return defaultValue
+	}
+
+	parsed := make([]int, 0, len(values))
+	for _, value := range values {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return defaultValue
+		}
+		parsed = append(parsed, n)
+	}
+	return parsed
+}
+
 // GetDSN returns the MySQL Data Source Name
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true&multiStatements=true",
@@ -0,0 +1,165 @@
+package curriculum
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/yourusername/skilltree/internal/data"
+)
+
+// StandardKey is the curriculum of learners without an assigned one
+const StandardKey = "standard"
+
+// Standard is the full skill tree
+var Standard = standard()
+
+// Catalog lists every curriculum. Keys are stored with user and cohort
+// assignments, so never rename one.
+var Catalog = []Curriculum{
+	Standard,
+	Apply(Standard, Overlay{
+		Key:         "beginner",
+		Title:       "Foundations",
+		Description: "A gentler start: the first three tiers with a lower bar for mastery",
+		Threshold:   60,
+		Only: []string{
+			"ARRAY_SCAN", "RECURSION_ROOTS",
+			"SORTING", "HASHING", "STACKS",
+			"PREFIX_SUM", "TWO_POINTERS", "QUEUES", "LINKED_LISTS",
+		},
+		Topics: map[string]TopicOverride{
+			"ARRAY_SCAN": {Problems: []string{"run_sum", "max_subarray"}},
+			"SORTING":    {Problems: []string{"missing_num", "sort_colors"}},
+		},
+	}),
+	Apply(Standard, Overlay{
+		Key:         "interview_prep",
+		Title:       "Interview Prep",
+		Description: "The patterns interviews ask about most, held to a higher bar, with dynamic programming brought forward",
+		Threshold:   80,
+		Only: []string{
+			"ARRAY_SCAN", "RECURSION_ROOTS",
+			"SORTING", "HASHING", "STACKS",
+			"TWO_POINTERS", "QUEUES", "LINKED_LISTS",
+			"SLIDING_WINDOW", "BINARY_SEARCH", "MONOTONIC_STACK",
+			"BINARY_TREES", "INTERVALS", "GREEDY",
+			"DFS_BFS", "BACKTRACKING",
+			"TOPOLOGICAL_SORT",
+			"DYNAMIC_PROGRAMMING",
+		},
+		Topics: map[string]TopicOverride{
+			"ARRAY_SCAN":          {Problems: []string{"prod_except", "max_subarray"}},
+			"DYNAMIC_PROGRAMMING": {Tier: tier(5), Reqs: []string{"RECURSION_ROOTS", "HASHING"}},
+		},
+	}),
+	Apply(Standard, Overlay{
+		Key:         "competitive",
+		Title:       "Competitive Programming",
+		Description: "Every topic, with prefix sums and bit tricks early and dynamic programming as the centrepiece",
+		Topics: map[string]TopicOverride{
+			"PREFIX_SUM":          {Tier: tier(1)},
+			"BIT_MANIPULATION":    {Tier: tier(1), Reqs: []string{"ARRAY_SCAN"}},
+			"UNION_FIND":          {Tier: tier(4), Reqs: []string{"ARRAY_SCAN"}},
+			"DYNAMIC_PROGRAMMING": {Tier: tier(5), Reqs: []string{"RECURSION_ROOTS", "PREFIX_SUM"}, Threshold: 90},
+		},
+	}),
+}
+
+// Find returns the curriculum with the key, or nil
+func Find(key string) *Curriculum {
+	for i := range Catalog {
+		if Catalog[i].Key == key {
+			return &Catalog[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks every curriculum in the catalog: keys are unique, each
+// prerequisite is in the path at the same or an earlier tier, thresholds
+// are between 1 and 100, selected problems exist in the problem catalog,
+// and prerequisites have no cycles. It also checks that every checkpoint
+// pattern belongs to a topic, so curricula can drop the untaught ones.
+func Validate() error {
+	for tier, pool := range data.CheckpointPools {
+		for _, problem := range pool {
+			for _, pattern := range problem.RequiredPatterns {
+				if patternTopic(pattern) == "" {
+					return fmt.Errorf("checkpoint %s (tier %d): pattern %q has no topic", problem.ID, tier, pattern)
+				}
+			}
+		}
+	}
+
+	seen := make(map[string]bool, len(Catalog))
+	for _, c := range Catalog {
+		if c.Key == "" || seen[c.Key] {
+			return fmt.Errorf("curriculum %q: missing or duplicate key", c.Key)
+		}
+		seen[c.Key] = true
+		if len(c.Topics) == 0 {
+			return fmt.Errorf("curriculum %s: no topics", c.Key)
+		}
+
+		for _, t := range c.Topics {
+			if t.Threshold < 1 || t.Threshold > 100 {
+				return fmt.Errorf("curriculum %s: topic %s threshold must be between 1 and 100", c.Key, t.Key)
+			}
+			for _, req := range t.Reqs {
+				reqTopic := c.Topic(req)
+				if reqTopic == nil {
+					return fmt.Errorf("curriculum %s: topic %s requires %s, which is not in the path", c.Key, t.Key, req)
+				}
+				if reqTopic.Tier > t.Tier {
+					return fmt.Errorf("curriculum %s: topic %s requires %s from a later tier", c.Key, t.Key, req)
+				}
+			}
+			if problems, ok := data.ProblemsDB[t.Key]; ok {
+				for _, id := range t.Problems {
+					if !slices.ContainsFunc(problems, func(p data.Problem) bool { return p.ID == id }) {
+						return fmt.Errorf("curriculum %s: topic %s has no problem %s", c.Key, t.Key, id)
+					}
+				}
+			}
+		}
+
+		if cycle := findCycle(&c); cycle != "" {
+			return fmt.Errorf("curriculum %s: prerequisite cycle through %s", c.Key, cycle)
+		}
+	}
+	return nil
+}
+
+// findCycle returns a topic on a prerequisite cycle, or ""
+func findCycle(c *Curriculum) string {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(c.Topics))
+
+	var visit func(key string) string
+	visit = func(key string) string {
+		switch state[key] {
+		case visiting:
+			return key
+		case done:
+			return ""
+		}
+		state[key] = visiting
+		for _, req := range c.Topic(key).Reqs {
+			if cycle := visit(req); cycle != "" {
+				return cycle
+			}
+		}
+		state[key] = done
+		return ""
+	}
+
+	for _, t := range c.Topics {
+		if cycle := visit(t.Key); cycle != "" {
+			return cycle
+		}
+	}
+	return ""
+}
@@ -0,0 +1,197 @@
+// Package curriculum defines learning paths as overlays on the standard skill
+// tree. A curriculum selects topics and problems from the catalog and may
+// move topics between tiers, replace their prerequisites and change the
+// confidence that counts as mastered. Unlocks and checkpoint readiness are
+// evaluated against the learner's active curriculum.
+package curriculum
+
+import (
+	"slices"
+
+	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/data"
+)
+
+// DefaultThreshold is the confidence a topic needs to count as mastered
+// unless a curriculum overrides it
+const DefaultThreshold = 70
+
+// Topic is one topic of a curriculum
+type Topic struct {
+	Key  string   `json:"key"`
+	Tier int      `json:"tier"`
+	Reqs []string `json:"reqs"`
+	// Problems are the catalog problem IDs in the path; empty means every
+	// problem of the topic
+	Problems []string `json:"problems,omitempty"`
+	// Threshold is the confidence at which the topic counts as mastered:
+	// it unlocks dependent topics and counts towards its tier's checkpoint
+	Threshold int `json:"threshold"`
+}
+
+// Curriculum is a learning path
+type Curriculum struct {
+	Key         string  `json:"key"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Topics      []Topic `json:"topics"`
+}
+
+// Topic returns the curriculum's topic with the key, or nil if the path
+// doesn't include it
+func (c *Curriculum) Topic(key string) *Topic {
+	for i := range c.Topics {
+		if c.Topics[i].Key == key {
+			return &c.Topics[i]
+		}
+	}
+	return nil
+}
+
+// TopicsForTier returns the keys of the curriculum's topics in a tier
+func (c *Curriculum) TopicsForTier(tier int) []string {
+	var keys []string
+	for _, t := range c.Topics {
+		if t.Tier == tier {
+			keys = append(keys, t.Key)
+		}
+	}
+	return keys
+}
+
+// CheckpointPatterns returns the patterns a tier's checkpoint requires in
+// the path, given those of its pool variant: patterns of topics the path
+// leaves out are dropped, and topics the path moves into the tier add
+// theirs, so a checkpoint tests what its tier teaches
+func (c *Curriculum) CheckpointPatterns(tier int, required []string) []string {
+	patterns := make([]string, 0, len(required))
+	for _, pattern := range required {
+		if key := patternTopic(pattern); key == "" || c.Topic(key) != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	for _, t := range c.Topics {
+		standardTopic := Standard.Topic(t.Key)
+		if t.Tier != tier || (standardTopic != nil && standardTopic.Tier == tier) {
+			continue
+		}
+		if pattern, ok := data.CheckpointPatterns[t.Key]; ok && !slices.Contains(patterns, pattern) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// patternTopic returns the topic a checkpoint pattern belongs to, or ""
+func patternTopic(pattern string) string {
+	for key, name := range data.CheckpointPatterns {
+		if name == pattern {
+			return key
+		}
+	}
+	return ""
+}
+
+// HasProblem reports whether a problem is part of the path
+func (c *Curriculum) HasProblem(topicKey, problemID string) bool {
+	topic := c.Topic(topicKey)
+	if topic == nil {
+		return false
+	}
+	return len(topic.Problems) == 0 || slices.Contains(topic.Problems, problemID)
+}
+
+// standard is the skill tree everyone follows by default
+func standard() Curriculum {
+	c := Curriculum{
+		Key:         StandardKey,
+		Title:       "Standard",
+		Description: "The full skill tree: all 22 topics in their standard order",
+	}
+	for _, key := range config.AllTopics {
+		node, ok := data.DAGStructure[key]
+		if !ok {
+			continue
+		}
+		c.Topics = append(c.Topics, Topic{
+			Key:       key,
+			Tier:      node.Tier,
+			Reqs:      slices.Clone(node.Reqs),
+			Threshold: DefaultThreshold,
+		})
+	}
+	return c
+}
+
+// Overlay describes a curriculum as changes to the standard one
+type Overlay struct {
+	Key         string
+	Title       string
+	Description string
+	// Threshold replaces DefaultThreshold for every topic; 0 keeps it
+	Threshold int
+	// Only limits the path to these topics; empty keeps them all.
+	// Prerequisites outside the path are dropped.
+	Only []string
+	// Topics changes individual topics
+	Topics map[string]TopicOverride
+}
+
+// TopicOverride changes one topic of an overlay. Zero fields keep the
+// standard value.
+type TopicOverride struct {
+	Tier *int
+	// Reqs replaces the prerequisites when non-nil; an empty slice makes
+	// the topic a root
+	Reqs      []string
+	Problems  []string
+	Threshold int
+}
+
+// Apply builds a curriculum from base and an overlay
+func Apply(base Curriculum, o Overlay) Curriculum {
+	c := Curriculum{Key: o.Key, Title: o.Title, Description: o.Description}
+
+	for _, t := range base.Topics {
+		if len(o.Only) > 0 && !slices.Contains(o.Only, t.Key) {
+			continue
+		}
+		t.Reqs = slices.Clone(t.Reqs)
+		if o.Threshold > 0 {
+			t.Threshold = o.Threshold
+		}
+		if override, ok := o.Topics[t.Key]; ok {
+			if override.Tier != nil {
+				t.Tier = *override.Tier
+			}
+			if override.Reqs != nil {
+				t.Reqs = slices.Clone(override.Reqs)
+			}
+			if override.Problems != nil {
+				t.Problems = slices.Clone(override.Problems)
+			}
+			if override.Threshold > 0 {
+				t.Threshold = override.Threshold
+			}
+		}
+		c.Topics = append(c.Topics, t)
+	}
+
+	// Drop prerequisites the path leaves out
+	for i := range c.Topics {
+		c.Topics[i].Reqs = slices.DeleteFunc(c.Topics[i].Reqs, func(req string) bool {
+			return c.Topic(req) == nil
+		})
+	}
+
+	// Keep topics in tier order; the sort is stable so the standard order
+	// holds within a tier
+	slices.SortStableFunc(c.Topics, func(a, b Topic) int { return a.Tier - b.Tier })
+
+	return c
+}
+
+// tier returns a pointer for TopicOverride.Tier
+func tier(n int) *int {
+	return &n
+}
@@ -0,0 +1,76 @@
+package curriculum
+
+// Topic statuses, as shown on the skill tree
+const (
+	StatusMastered          = "MASTERED"
+	StatusInProgress        = "IN_PROGRESS"
+	StatusUnlocked          = "UNLOCKED"
+	StatusLocked            = "LOCKED"
+	StatusCheckpointBlocked = "CHECKPOINT_BLOCKED"
+)
+
+// Progress is what unlocks are evaluated against
+type Progress struct {
+	// Confidence is the learner's confidence by topic
+	Confidence map[string]int
+	// CheckpointPassed holds whether each tier's checkpoint is passed, for
+	// the tiers the learner has checkpoint records for
+	CheckpointPassed map[int]bool
+}
+
+// Status evaluates a topic for a learner, or returns "" if the path doesn't
+// include it. A topic is blocked while the checkpoint of the tier before it
+// is unpassed. Otherwise a topic with progress stays open, and a new topic
+// unlocks once every prerequisite reaches its threshold.
+func (c *Curriculum) Status(topicKey string, p Progress) string {
+	topic := c.Topic(topicKey)
+	if topic == nil {
+		return ""
+	}
+
+	if topic.Tier > 0 {
+		if passed, ok := p.CheckpointPassed[topic.Tier-1]; ok && !passed {
+			return StatusCheckpointBlocked
+		}
+	}
+
+	confidence := p.Confidence[topicKey]
+	if confidence >= 100 {
+		return StatusMastered
+	}
+	if confidence > 0 {
+		return StatusInProgress
+	}
+
+	for _, req := range topic.Reqs {
+		reqTopic := c.Topic(req)
+		if reqTopic == nil || p.Confidence[req] < reqTopic.Threshold {
+			return StatusLocked
+		}
+	}
+	return StatusUnlocked
+}
+
+// Open reports whether the learner may practise a topic
+func (c *Curriculum) Open(topicKey string, p Progress) bool {
+	switch c.Status(topicKey, p) {
+	case StatusMastered, StatusInProgress, StatusUnlocked:
+		return true
+	}
+	return false
+}
+
+// ReadyForCheckpoint reports whether every topic of a tier has reached its
+// threshold. A tier without topics in the path is never ready.
+func (c *Curriculum) ReadyForCheckpoint(tier int, confidence map[string]int) bool {
+	topics := c.TopicsForTier(tier)
+	if len(topics) == 0 {
+		return false
+	}
+	for _, key := range topics {
+		if confidence[key] < c.Topic(key).Threshold {
+			return false
+		}
+	}
+	return true
+}
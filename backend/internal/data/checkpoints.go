@@ -0,0 +1,203 @@
+package data
+
+// CheckpointProblem is one variant in a tier checkpoint pool
+type CheckpointProblem struct {
+	ID               string   `json:"id"`
+	Tier             int      `json:"tier"`
+	Title            string   `json:"title"`
+	Description      string   `json:"description"`
+	RequiredPatterns []string `json:"required_patterns"`
+	Invariant        string   `json:"invariant"`
+	Hint             string   `json:"hint,omitempty"`
+}
+
+// CheckpointPatterns names the pattern a checkpoint requires for each topic.
+// Pool variants list these names in RequiredPatterns, and curricula use the
+// map to add or drop a topic's pattern from their tiers' checkpoints.
+var CheckpointPatterns = map[string]string{
+	"ARRAY_SCAN":          "Array Iteration",
+	"RECURSION_ROOTS":     "Recursive Backtracking",
+	"SORTING":             "Sorting",
+	"HASHING":             "Hashing",
+	"STACKS":              "Stack",
+	"PREFIX_SUM":          "Prefix Sum",
+	"TWO_POINTERS":        "Two Pointers",
+	"QUEUES":              "Queue",
+	"LINKED_LISTS":        "Linked List",
+	"SLIDING_WINDOW":      "Sliding Window",
+	"BINARY_SEARCH":       "Binary Search",
+	"MONOTONIC_STACK":     "Monotonic Stack",
+	"BINARY_TREES":        "Tree Traversal",
+	"INTERVALS":           "Interval Merging",
+	"GREEDY":              "Greedy",
+	"DFS_BFS":             "DFS",
+	"BACKTRACKING":        "Backtracking",
+	"TRIES":               "Trie",
+	"TOPOLOGICAL_SORT":    "Topological Sort",
+	"UNION_FIND":          "Union-Find",
+	"BIT_MANIPULATION":    "Bit Manipulation",
+	"DYNAMIC_PROGRAMMING": "Dynamic Programming",
+}
+
+// CheckpointPools maps each tier (0-6) to its pool of checkpoint variants.
+// Variants in a pool must exercise the same required patterns so that any
+// assignment is an equally fair gate for the tier.
+var CheckpointPools = map[int][]CheckpointProblem{
+	0: {
+		{
+			ID:               "checkpoint_tier_0",
+			Tier:             0,
+			Title:            "Subsets Generator",
+			Description:      "Generate all subsets (power set) using recursion and array iteration",
+			RequiredPatterns: []string{"Array Iteration", "Recursive Backtracking"},
+			Invariant:        "Recursive exploration + Array state management",
+			Hint:             "Build subsets recursively by deciding to include or exclude each element",
+		},
+		{
+			ID:               "checkpoint_tier_0_b",
+			Tier:             0,
+			Title:            "Permutations Builder",
+			Description:      "Generate all permutations of an array of unique integers using recursion and in-place array swaps",
+			RequiredPatterns: []string{"Array Iteration", "Recursive Backtracking"},
+			Invariant:        "Prefix [0..i) fixed, recurse on the suffix, undo the swap",
+			Hint:             "Swap each candidate into position i, recurse, then swap back",
+		},
+		{
+			ID:               "checkpoint_tier_0_c",
+			Tier:             0,
+			Title:            "Combination Sum Explorer",
+			Description:      "Find all unique combinations of candidates that sum to a target using recursion over the array",
+			RequiredPatterns: []string{"Array Iteration", "Recursive Backtracking"},
+			Invariant:        "Remaining target shrinks; start index prevents duplicate combinations",
+			Hint:             "Pass the start index down so each combination is built in non-decreasing order",
+		},
+	},
+	1: {
+		{
+			ID:               "checkpoint_tier_1",
+			Tier:             1,
+			Title:            "Interval Merger with Validation",
+			Description:      "Merge overlapping intervals with hash deduplication and stack validation",
+			RequiredPatterns: []string{"Sorting", "Hashing", "Stack"},
+			Invariant:        "Sorted by start; stack top is the last merged interval",
+		},
+		{
+			ID:               "checkpoint_tier_1_b",
+			Tier:             1,
+			Title:            "Meeting Room Log Auditor",
+			Description:      "Sort meeting logs by start time, drop duplicate entries with a hash set, and use a stack to collapse overlapping meetings into busy blocks",
+			RequiredPatterns: []string{"Sorting", "Hashing", "Stack"},
+			Invariant:        "Each log is seen once; stack holds non-overlapping busy blocks in order",
+		},
+		{
+			ID:               "checkpoint_tier_1_c",
+			Tier:             1,
+			Title:            "Anagram Bracket Groups",
+			Description:      "Group words into anagram buckets via sorted-key hashing, then validate each bucket's bracket annotations with a stack",
+			RequiredPatterns: []string{"Sorting", "Hashing", "Stack"},
+			Invariant:        "Sorted characters form the hash key; stack depth never goes negative",
+		},
+	},
+	2: {
+		{
+			ID:               "checkpoint_tier_2",
+			Tier:             2,
+			Title:            "Windowed Maximum Sum",
+			Description:      "Maximum sum subarray with prefix sum optimization and sliding window",
+			RequiredPatterns: []string{"Prefix Sum", "Sliding Window", "Queue"},
+			Invariant:        "prefix[j] - prefix[i] gives window sums in O(1)",
+		},
+		{
+			ID:               "checkpoint_tier_2_b",
+			Tier:             2,
+			Title:            "Bounded Subarray Sum",
+			Description:      "Find the shortest subarray with sum at least K using prefix sums and a monotonic deque over a sliding window",
+			RequiredPatterns: []string{"Prefix Sum", "Sliding Window", "Queue"},
+			Invariant:        "Deque holds increasing prefix sums; front pops once the window qualifies",
+		},
+	},
+	3: {
+		{
+			ID:               "checkpoint_tier_3",
+			Tier:             3,
+			Title:            "Histogram Rectangle",
+			Description:      "Largest rectangle in histogram using binary search and monotonic stack",
+			RequiredPatterns: []string{"Binary Search", "Monotonic Stack", "Sliding Window"},
+			Invariant:        "Stack bars are increasing; popped bar's width spans to the new smaller bar",
+		},
+		{
+			ID:               "checkpoint_tier_3_b",
+			Tier:             3,
+			Title:            "Stock Span Window Search",
+			Description:      "Compute stock spans with a monotonic stack, then binary search the smallest window size whose maximum span exceeds a threshold",
+			RequiredPatterns: []string{"Binary Search", "Monotonic Stack", "Sliding Window"},
+			Invariant:        "Predicate on window size is monotonic, so the answer is binary-searchable",
+		},
+	},
+	4: {
+		{
+			ID:               "checkpoint_tier_4",
+			Tier:             4,
+			Title:            "Tree Interval Scheduler",
+			Description:      "Maximum non-overlapping intervals in binary tree with greedy selection",
+			RequiredPatterns: []string{"Tree Traversal", "Interval Merging", "Greedy"},
+			Invariant:        "Collect intervals in traversal order, sort by end, greedily keep the earliest finish",
+		},
+		{
+			ID:               "checkpoint_tier_4_b",
+			Tier:             4,
+			Title:            "Subtree Coverage Planner",
+			Description:      "Each tree node owns a time interval; merge intervals per subtree and greedily choose the fewest points that stab every merged interval",
+			RequiredPatterns: []string{"Tree Traversal", "Interval Merging", "Greedy"},
+			Invariant:        "Post-order merge of child intervals; pick the end of the earliest-finishing interval",
+		},
+	},
+	5: {
+		{
+			ID:               "checkpoint_tier_5",
+			Tier:             5,
+			Title:            "Word Search II",
+			Description:      "Word Search II using Trie construction, DFS traversal, and backtracking",
+			RequiredPatterns: []string{"Trie", "DFS", "Backtracking"},
+			Invariant:        "Trie prunes dead prefixes; visited cells are restored on backtrack",
+		},
+		{
+			ID:               "checkpoint_tier_5_b",
+			Tier:             5,
+			Title:            "Boggle Dictionary Paths",
+			Description:      "Count distinct dictionary words reachable on a Boggle board including diagonal moves, using a Trie, DFS and backtracking",
+			RequiredPatterns: []string{"Trie", "DFS", "Backtracking"},
+			Invariant:        "Each DFS frame walks one Trie edge; found words are removed to avoid recounting",
+		},
+	},
+	6: {
+		{
+			ID:               "checkpoint_tier_6",
+			Tier:             6,
+			Title:            "Course Scheduler",
+			Description:      "Course scheduling with topological sort, union-find, and bitmask states",
+			RequiredPatterns: []string{"Topological Sort", "Union-Find", "Bit Manipulation"},
+			Invariant:        "Kahn's order over components; bitmask tracks completed prerequisites",
+		},
+		{
+			ID:               "checkpoint_tier_6_b",
+			Tier:             6,
+			Title:            "Build Pipeline Planner",
+			Description:      "Order build targets with topological sort, group independent targets with union-find, and track feature flags per target as bitmasks",
+			RequiredPatterns: []string{"Topological Sort", "Union-Find", "Bit Manipulation"},
+			Invariant:        "In-degree zero targets are ready; flags of a target are the OR of its dependencies",
+		},
+	},
+}
+
+// FindCheckpointProblem looks up a checkpoint variant by ID across all tiers
+func FindCheckpointProblem(id string) *CheckpointProblem {
+	for _, pool := range CheckpointPools {
+		for i := range pool {
+			if pool[i].ID == id {
+				return &pool[i]
+			}
+		}
+	}
+	return nil
+}
@@ -54,6 +54,146 @@ var DAGStructure = map[string]DAGNode{
 		Theory:  "Last-In, First-Out (LIFO). Think of a stack of plates.",
 		YouTube: "KInG04mAjO0",
 	},
-	// Add remaining topics...
-	// (truncated for brevity - in production, include all 22 topics)
+	// Tier 2: Patterns
+	"PREFIX_SUM": {
+		Label:   "Prefix Sums",
+		Tier:    2,
+		Reqs:    []string{"ARRAY_SCAN"},
+		Desc:    "Pre-computation for O(1) range queries.",
+		Theory:  "If you need to calculate the sum of a subarray multiple times, don't iterate.",
+		YouTube: "pVS3yhlzrlQ",
+	},
+	"TWO_POINTERS": {
+		Label:   "Two Pointers",
+		Tier:    2,
+		Reqs:    []string{"SORTING"},
+		Desc:    "Converging on solutions in linear time.",
+		Theory:  "On a sorted array, use two pointers (usually Left and Right) to process the data.",
+		YouTube: "-GJ1GV4khSc",
+	},
+	"QUEUES": {
+		Label:   "Queues (FIFO)",
+		Tier:    2,
+		Reqs:    []string{"STACKS"},
+		Desc:    "Processing streams and breadth-first flows.",
+		Theory:  "First-In, First-Out (FIFO).",
+		YouTube: "DkK8g6RBJs8",
+	},
+	"LINKED_LISTS": {
+		Label:   "Linked Lists",
+		Tier:    2,
+		Reqs:    []string{"ARRAY_SCAN", "RECURSION_ROOTS"},
+		Desc:    "Dynamic non-contiguous memory.",
+		Theory:  "Nodes scattered in memory, connected by pointers.",
+		YouTube: "WwfhLC16bis",
+	},
+	// Tier 3: Advanced Linear
+	"SLIDING_WINDOW": {
+		Label:   "Sliding Window",
+		Tier:    3,
+		Reqs:    []string{"TWO_POINTERS", "HASHING"},
+		Desc:    "Dynamic range optimization.",
+		Theory:  "Convert O(N^2) nested loops into O(N) by maintaining a 'window' of state.",
+		YouTube: "GCm7m5671Ps",
+	},
+	"BINARY_SEARCH": {
+		Label:   "Binary Search",
+		Tier:    3,
+		Reqs:    []string{"SORTING"},
+		Desc:    "Logarithmic space reduction.",
+		Theory:  "If the search space is sorted (or monotonic), check the middle.",
+		YouTube: "s4DPM8ct1pI",
+	},
+	"MONOTONIC_STACK": {
+		Label:   "Monotonic Stack",
+		Tier:    3,
+		Reqs:    []string{"STACKS"},
+		Desc:    "Finding next greater/smaller elements in O(N).",
+		Theory:  "A stack where elements are always sorted (increasing or decreasing).",
+		YouTube: "Dq_ObZwTY_Q",
+	},
+	// Tier 4: Hierarchical
+	"BINARY_TREES": {
+		Label:   "Binary Trees",
+		Tier:    4,
+		Reqs:    []string{"RECURSION_ROOTS", "QUEUES"},
+		Desc:    "Hierarchical data storage and traversal.",
+		Theory:  "Data organized hierarchically.",
+		YouTube: "OnSn2XEQ4MY",
+	},
+	"INTERVALS": {
+		Label:   "Intervals",
+		Tier:    4,
+		Reqs:    []string{"SORTING", "GREEDY"},
+		Desc:    "Managing overlapping timelines.",
+		Theory:  "Problems involving start and end times.",
+		YouTube: "44H3cEC2fFM",
+	},
+	"GREEDY": {
+		Label:   "Greedy",
+		Tier:    4,
+		Reqs:    []string{"SORTING"},
+		Desc:    "Local optimization for global solutions.",
+		Theory:  "Making the locally optimal choice at each step with the hope of finding a global optimum.",
+		YouTube: "bC7o8P_Ste4",
+	},
+	// Tier 5: Graph & Search
+	"DFS_BFS": {
+		Label:   "Graph Search",
+		Tier:    5,
+		Reqs:    []string{"BINARY_TREES", "HASHING", "STACKS", "QUEUES"},
+		Desc:    "Navigating arbitrary networks.",
+		Theory:  "DFS dives deep (using a Stack/Recursion), useful for pathfinding and exhausting possibilities.",
+		YouTube: "PMMc4VsIacU",
+	},
+	"BACKTRACKING": {
+		Label:   "Backtracking",
+		Tier:    5,
+		Reqs:    []string{"RECURSION_ROOTS"},
+		Desc:    "Exhaustive search that undoes choices. Permutations, subsets, N-Queens.",
+		Theory:  "Backtracking builds a solution one choice at a time and undoes the last choice when it leads nowhere.",
+		YouTube: "",
+	},
+	"TRIES": {
+		Label:   "Tries",
+		Tier:    5,
+		Reqs:    []string{"BINARY_TREES", "HASHING"},
+		Desc:    "Prefix trees for word search and autocomplete.",
+		Theory:  "A trie stores strings character by character along shared prefixes, so lookups cost O(L) in the key length.",
+		YouTube: "",
+	},
+	// Tier 6: Complex Graph & Specialist
+	"TOPOLOGICAL_SORT": {
+		Label:   "Topo Sort",
+		Tier:    6,
+		Reqs:    []string{"DFS_BFS"},
+		Desc:    "Dependency resolution in DAGs.",
+		Theory:  "Linear ordering of vertices where for every edge U->V, U comes before V.",
+		YouTube: "eL-KzMXSXXI",
+	},
+	"UNION_FIND": {
+		Label:   "Union Find",
+		Tier:    6,
+		Reqs:    []string{"DFS_BFS", "ARRAY_SCAN"},
+		Desc:    "Disjoint set management and cycle detection.",
+		Theory:  "A data structure to track elements partitioned into disjoint sets.",
+		YouTube: "ayW5B2WdBhE",
+	},
+	"BIT_MANIPULATION": {
+		Label:   "Bitwise Logic",
+		Tier:    6,
+		Reqs:    []string{"ARRAY_SCAN"},
+		Desc:    "Hardware-level boolean algebra. XOR, AND, shifting.",
+		Theory:  "Manipulating raw bits.",
+		YouTube: "NLKQEOgBzpA",
+	},
+	// Tier 7: Endgame
+	"DYNAMIC_PROGRAMMING": {
+		Label:   "Dynamic Prog",
+		Tier:    7,
+		Reqs:    []string{"RECURSION_ROOTS"},
+		Desc:    "Memoization and Tabulation. Essential for interviews.",
+		Theory:  "Optimization of plain recursion.",
+		YouTube: "Hdr64lNM3Vk",
+	},
 }
@@ -0,0 +1,90 @@
+// Package events carries domain events from the judge, checkpoint and review
+// flows to the features that react to them (XP, streaks, achievements)
+// without those flows knowing about each of them.
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types
+const (
+	// PracticeSubmitted is any judged practice submission
+	PracticeSubmitted = "practice_submitted"
+	// PracticeSolved is the first passing submission for a practice problem
+	PracticeSolved = "practice_solved"
+	// CheckpointAttempted is any judged checkpoint attempt
+	CheckpointAttempted = "checkpoint_attempted"
+	// CheckpointPassed is an attempt that passed its tier checkpoint
+	CheckpointPassed = "checkpoint_passed"
+	// ReviewResolved is a mentor resolving a review; UserUID is the mentor
+	ReviewResolved = "review_resolved"
+)
+
+// Event is something a user did. Fields that don't apply to the type are
+// left empty.
+type Event struct {
+	Type    string
+	UserUID string
+	// SubjectType and SubjectID identify the submission, attempt or review
+	// the event is about; awards keyed on them are idempotent
+	SubjectType string
+	SubjectID   int64
+	TopicKey    string
+	ProblemID   string
+	// Difficulty is the practice problem's Diff ("Easy", "Medium", "Hard")
+	Difficulty    string
+	Tier          int
+	AttemptNumber int
+	At            time.Time
+}
+
+// Handler reacts to an event
+type Handler func(Event) error
+
+type subscriber struct {
+	name    string
+	handler Handler
+}
+
+// Bus delivers events to subscribers synchronously, in subscription order.
+// A nil *Bus drops every event, so services work without one.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler for every published event
+func (b *Bus) Subscribe(name string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, subscriber{name: name, handler: handler})
+}
+
+// Publish delivers an event to every subscriber. Subscriber errors are
+// logged, not returned: a failed award must never fail the submission that
+// earned it.
+func (b *Bus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	if event.At.IsZero() {
+		event.At = time.Now()
+	}
+
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+
+	for _, s := range subscribers {
+		if err := s.handler(event); err != nil {
+			log.Printf("Event %s for user %s: %s failed: %v", event.Type, event.UserUID, s.name, err)
+		}
+	}
+}
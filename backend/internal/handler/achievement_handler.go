@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type AchievementHandler struct {
+	achievementService *service.AchievementService
+}
+
+func NewAchievementHandler(achievementService *service.AchievementService) *AchievementHandler {
+	return &AchievementHandler{achievementService: achievementService}
+}
+
+// ListAchievements returns every badge with the caller's earned ones marked
+// GET /api/profile/achievements
+func (h *AchievementHandler) ListAchievements(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	list, err := h.achievementService.List(firebaseUID)
+	if err != nil {
+		log.Printf("Failed to list achievements: %v", err)
+		http.Error(w, `{"error":"Failed to list achievements"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"achievements": list})
+}
@@ -2,24 +2,36 @@ package handler
 
 import (
 	"encoding/json"
-	"fmt"
+	"errors"
+	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/yourusername/skilltree/internal/data"
 	"github.com/yourusername/skilltree/internal/middleware"
 	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/sandbox"
 	"github.com/yourusername/skilltree/internal/service"
 )
 
 type AIHandler struct {
-	geminiService  *service.GeminiService
-	masteryService *service.MasteryService
+	geminiService     *service.GeminiService
+	complexityService *service.ComplexityService
+	judgeService      *service.JudgeService
+	hintService       *service.HintService
+	reviewService     *service.CodeReviewService
+	quotaService      *service.QuotaService
 }
 
-func NewAIHandler(geminiService *service.GeminiService, masteryService *service.MasteryService) *AIHandler {
+func NewAIHandler(geminiService *service.GeminiService, complexityService *service.ComplexityService, judgeService *service.JudgeService, hintService *service.HintService, reviewService *service.CodeReviewService, quotaService *service.QuotaService) *AIHandler {
 	return &AIHandler{
-		geminiService:  geminiService,
-		masteryService: masteryService,
+		geminiService:     geminiService,
+		complexityService: complexityService,
+		judgeService:      judgeService,
+		hintService:       hintService,
+		reviewService:     reviewService,
+		quotaService:      quotaService,
 	}
 }
 
@@ -28,19 +40,15 @@ type ChatRequest struct {
 	Message  string `json:"message"`
 }
 
-type ComplexityRequest struct {
-	Code string `json:"code"`
-}
-
-type JudgeRequest struct {
-	TopicKey  string `json:"topic_key"`
-	ProblemID string `json:"problem_id"`
-	Code      string `json:"code"`
-}
-
 // Chat handles AI chat requests
 // POST /api/ai/chat
 func (h *AIHandler) Chat(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
 	var req ChatRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
@@ -54,13 +62,11 @@ func (h *AIHandler) Chat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	systemPrompt := fmt.Sprintf(`You are the %s Architect.
-Your goal is to help the user understand the CONCEPT of %s using analogies and Socratic questioning.
-DO NOT write code. Focus on the intuition, the "why", and the trade-offs.
-Be concise, wise, and slightly cryptic but helpful. Keep responses under 50 words unless asked for detail.`,
-		topicInfo.Label, topicInfo.Label)
-
-	response, err := h.geminiService.ChatCompletion(req.Message, systemPrompt)
+	response, err := h.geminiService.ArchitectChat(r.Context(), firebaseUID, topicInfo.Label, req.Message)
+	if errors.Is(err, service.ErrAIUnavailable) {
+		writeAIUnavailable(w, h.geminiService.RetryAfter())
+		return
+	}
 	if err != nil {
 		http.Error(w, `{"error":"Failed to get AI response"}`, http.StatusInternalServerError)
 		return
@@ -70,10 +76,16 @@ Be concise, wise, and slightly cryptic but helpful. Keep responses under 50 word
 	json.NewEncoder(w).Encode(map[string]string{"response": response})
 }
 
-// Complexity analyzes code complexity
+// Complexity analyzes code complexity, optionally measuring it in the sandbox
 // POST /api/ai/complexity
 func (h *AIHandler) Complexity(w http.ResponseWriter, r *http.Request) {
-	var req ComplexityRequest
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ComplexityRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
 		return
@@ -84,14 +96,31 @@ func (h *AIHandler) Complexity(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	analysis, err := h.geminiService.ComplexityAnalysis(req.Code)
+	result, err := h.complexityService.Analyze(r.Context(), firebaseUID, &req)
 	if err != nil {
-		http.Error(w, `{"error":"Failed to analyze complexity"}`, http.StatusInternalServerError)
+		switch {
+		case errors.Is(err, service.ErrAIUnavailable):
+			writeAIUnavailable(w, h.geminiService.RetryAfter())
+		case errors.Is(err, service.ErrSandboxDisabled), errors.Is(err, sandbox.ErrNotIsolated):
+			http.Error(w, `{"error":"Empirical profiling is disabled"}`, http.StatusNotImplemented)
+		case errors.Is(err, sandbox.ErrUnsupportedLanguage):
+			http.Error(w, `{"error":"language must be javascript or python"}`, http.StatusBadRequest)
+		case errors.Is(err, sandbox.ErrInvalidEntry):
+			http.Error(w, `{"error":"entry must be a function name"}`, http.StatusBadRequest)
+		case errors.Is(err, sandbox.ErrInvalidInput):
+			http.Error(w, `{"error":"input must be array, sorted_array, string or int"}`, http.StatusBadRequest)
+		case errors.Is(err, sandbox.ErrBusy):
+			w.Header().Set("Retry-After", "5")
+			http.Error(w, `{"error":"Sandbox is busy, try again shortly"}`, http.StatusServiceUnavailable)
+		default:
+			log.Printf("Failed to analyze complexity: %v", err)
+			http.Error(w, `{"error":"Failed to analyze complexity"}`, http.StatusInternalServerError)
+		}
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"analysis": analysis})
+	json.NewEncoder(w).Encode(result)
 }
 
 // Judge validates code and updates mastery
@@ -103,86 +132,218 @@ func (h *AIHandler) Judge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var req JudgeRequest
+	var req models.JudgeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
 		return
 	}
 
-	// Get problem info
-	problems, ok := data.ProblemsDB[req.TopicKey]
+	// Judge, store the submission and update mastery on ADVANCE
+	result, err := h.judgeService.Judge(r.Context(), firebaseUID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTopic):
+			http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidProblem):
+			http.Error(w, `{"error":"Invalid problem"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrTopicNotInCurriculum):
+			http.Error(w, `{"error":"Problem is not in your curriculum"}`, http.StatusForbidden)
+		case errors.Is(err, service.ErrTopicLocked):
+			http.Error(w, `{"error":"Topic is locked: finish its prerequisites and the previous checkpoint first"}`, http.StatusForbidden)
+		default:
+			log.Printf("Failed to judge code: %v", err)
+			http.Error(w, `{"error":"Failed to judge code"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetJudgeQuiz returns the explain quiz for a passing practice submission
+// GET /api/ai/judge/{submissionID}/quiz
+func (h *AIHandler) GetJudgeQuiz(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
 	if !ok {
-		http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
 		return
 	}
 
-	var problem *data.Problem
-	for _, p := range problems {
-		if p.ID == req.ProblemID {
-			problem = &p
-			break
-		}
+	submissionID, err := strconv.ParseInt(chi.URLParam(r, "submissionID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid submission id"}`, http.StatusBadRequest)
+		return
+	}
+
+	quiz, err := h.judgeService.GetQuiz(firebaseUID, submissionID)
+	if err != nil {
+		writeQuizError(w, err, "Failed to get quiz")
+		return
 	}
 
-	if problem == nil {
-		http.Error(w, `{"error":"Invalid problem"}`, http.StatusBadRequest)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quiz)
+}
+
+// SubmitJudgeQuiz grades the answers to a practice submission's explain quiz
+// and grants full confidence credit on a pass
+// POST /api/ai/judge/{submissionID}/quiz
+func (h *AIHandler) SubmitJudgeQuiz(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
 		return
 	}
 
-	// Call Gemini judge
-	result, err := h.geminiService.JudgeAudit(req.Code, req.TopicKey, problem.Title, problem.Invariant)
+	submissionID, err := strconv.ParseInt(chi.URLParam(r, "submissionID"), 10, 64)
 	if err != nil {
-		http.Error(w, `{"error":"Failed to judge code"}`, http.StatusInternalServerError)
+		http.Error(w, `{"error":"Invalid submission id"}`, http.StatusBadRequest)
 		return
 	}
 
-	// If verdict is ADVANCE, update mastery
-	verdict, _ := result["verdict"].(string)
-	if verdict == "ADVANCE" || verdict == "Optimal" {
-		// Get current mastery
-		masteryResp, err := h.masteryService.GetMasteryByFirebaseUID(firebaseUID)
-		if err != nil {
-			http.Error(w, `{"error":"Failed to get mastery"}`, http.StatusInternalServerError)
-			return
-		}
+	var req models.QuizAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
 
-		currentMastery, ok := masteryResp.Mastery[req.TopicKey]
-		if !ok {
-			currentMastery = models.MasteryData{Confidence: 0, Solved: []string{}}
-		}
+	result, err := h.judgeService.SubmitQuiz(r.Context(), firebaseUID, submissionID, req.Answers)
+	if errors.Is(err, service.ErrAIUnavailable) {
+		writeAIUnavailable(w, h.geminiService.RetryAfter())
+		return
+	}
+	if err != nil {
+		writeQuizError(w, err, "Failed to grade quiz")
+		return
+	}
 
-		// Add problem to solved list if not already there
-		solved := currentMastery.Solved
-		alreadySolved := false
-		for _, id := range solved {
-			if id == req.ProblemID {
-				alreadySolved = true
-				break
-			}
-		}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
 
-		if !alreadySolved {
-			solved = append(solved, req.ProblemID)
-		}
+// Review returns review findings anchored to line ranges of the code
+// POST /api/ai/review
+func (h *AIHandler) Review(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
 
-		// Calculate new confidence (33% per problem, max 100%)
-		newConfidence := (len(solved) * 100) / 3
-		if newConfidence > 100 {
-			newConfidence = 100
-		}
+	var req models.CodeReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	if req.Code == "" {
+		http.Error(w, `{"error":"code is required"}`, http.StatusBadRequest)
+		return
+	}
 
-		// Update mastery
-		updateReq := &models.UpdateMasteryRequest{
-			Confidence:     newConfidence,
-			SolvedProblems: solved,
+	review, err := h.reviewService.Review(r.Context(), firebaseUID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAIUnavailable):
+			writeAIUnavailable(w, h.geminiService.RetryAfter())
+		case errors.Is(err, service.ErrInvalidTopic):
+			http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidProblem):
+			http.Error(w, `{"error":"Invalid problem"}`, http.StatusBadRequest)
+		default:
+			log.Printf("Failed to review code: %v", err)
+			http.Error(w, `{"error":"Failed to review code"}`, http.StatusInternalServerError)
 		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
 
-		if err := h.masteryService.UpdateMastery(firebaseUID, req.TopicKey, updateReq); err != nil {
-			http.Error(w, `{"error":"Failed to update mastery"}`, http.StatusInternalServerError)
-			return
+// Hint returns the next hint on the nudge → approach → pseudocode ladder
+// POST /api/ai/hint
+func (h *AIHandler) Hint(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.HintRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.hintService.Hint(r.Context(), firebaseUID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrAIUnavailable):
+			writeAIUnavailable(w, h.geminiService.RetryAfter())
+		case errors.Is(err, service.ErrInvalidTopic):
+			http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidProblem):
+			http.Error(w, `{"error":"Invalid problem"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidHintLevel):
+			http.Error(w, `{"error":"level must be nudge, approach or pseudocode"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrHintLevelLocked):
+			http.Error(w, `{"error":"Earlier hint levels must be used first"}`, http.StatusConflict)
+		default:
+			log.Printf("Failed to get hint: %v", err)
+			http.Error(w, `{"error":"Failed to get hint"}`, http.StatusInternalServerError)
 		}
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
+
+// GetHintUsage returns how far up the hint ladder the user went on a problem
+// GET /api/ai/hints?topic_key=...&problem_id=...
+func (h *AIHandler) GetHintUsage(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	usage, err := h.hintService.GetUsage(firebaseUID, r.URL.Query().Get("topic_key"), r.URL.Query().Get("problem_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTopic):
+			http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidProblem):
+			http.Error(w, `{"error":"Invalid problem"}`, http.StatusBadRequest)
+		default:
+			log.Printf("Failed to get hint usage: %v", err)
+			http.Error(w, `{"error":"Failed to get hint usage"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
+
+// GetUsage returns the user's AI token usage for today
+// GET /api/ai/usage
+func (h *AIHandler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	usage, err := h.quotaService.GetUsage(firebaseUID)
+	if err != nil {
+		log.Printf("Failed to get AI usage: %v", err)
+		http.Error(w, `{"error":"Failed to get usage"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(usage)
+}
@@ -2,9 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/skilltree/internal/middleware"
 	"github.com/yourusername/skilltree/internal/models"
 	"github.com/yourusername/skilltree/internal/service"
 )
@@ -21,7 +26,7 @@ func NewCheckpointHandler(checkpointService *service.CheckpointService) *Checkpo
 // GET /api/checkpoints
 func (h *CheckpointHandler) GetCheckpoints(w http.ResponseWriter, r *http.Request) {
 	// Get Firebase UID from context (set by auth middleware)
-	firebaseUID, ok := r.Context().Value("firebase_uid").(string)
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
 	if !ok {
 		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
 		return
@@ -38,11 +43,86 @@ func (h *CheckpointHandler) GetCheckpoints(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(checkpointStatus)
 }
 
-// AttemptCheckpoint handles checkpoint problem submission
+// StartSession opens (or resumes) a timed session for a tier checkpoint
+// POST /api/checkpoints/{tier}/start
+func (h *CheckpointHandler) StartSession(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	// Validate tier number
+	tier, err := strconv.Atoi(chi.URLParam(r, "tier"))
+	if err != nil || tier < 0 || tier > 6 {
+		http.Error(w, `{"error":"Invalid tier number (must be 0-6)"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.checkpointService.StartSession(firebaseUID, tier)
+	if err != nil {
+		log.Printf("Failed to start checkpoint session: %v", err)
+		if errors.Is(err, service.ErrCheckpointCooldown) {
+			http.Error(w, `{"error":"Checkpoint is cooling down after repeated failures. Try again later."}`, http.StatusTooManyRequests)
+			return
+		}
+		if errors.Is(err, service.ErrDailyAttemptCapReached) {
+			http.Error(w, `{"error":"Daily attempt limit reached for this checkpoint. Try again tomorrow."}`, http.StatusTooManyRequests)
+			return
+		}
+		if errors.Is(err, service.ErrCheckpointAlreadyPassed) {
+			http.Error(w, `{"error":"You have already passed this checkpoint."}`, http.StatusConflict)
+			return
+		}
+		// Check if it's a validation error (can't attempt yet)
+		if strings.HasPrefix(err.Error(), "cannot attempt checkpoint") {
+			http.Error(w, `{"error":"Cannot attempt checkpoint yet. Complete all tier topics first."}`, http.StatusForbidden)
+			return
+		}
+		http.Error(w, `{"error":"Failed to start checkpoint session"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// SaveDraft autosaves code for an open checkpoint session
+// PUT /api/checkpoints/sessions/{sessionID}/draft
+func (h *CheckpointHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(chi.URLParam(r, "sessionID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid session id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.SaveDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	savedAt, err := h.checkpointService.SaveDraft(firebaseUID, sessionID, req.Code)
+	if err != nil {
+		writeSessionError(w, err, "Failed to save draft")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"saved_at": savedAt})
+}
+
+// AttemptCheckpoint handles checkpoint problem submission for an open session
 // POST /api/checkpoints/attempt
 func (h *CheckpointHandler) AttemptCheckpoint(w http.ResponseWriter, r *http.Request) {
 	// Get Firebase UID from context (set by auth middleware)
-	firebaseUID, ok := r.Context().Value("firebase_uid").(string)
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
 	if !ok {
 		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
 		return
@@ -54,9 +134,9 @@ func (h *CheckpointHandler) AttemptCheckpoint(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	// Validate tier number
-	if req.TierNumber < 0 || req.TierNumber > 6 {
-		http.Error(w, `{"error":"Invalid tier number (must be 0-6)"}`, http.StatusBadRequest)
+	// Submissions are only accepted inside a session started via /start
+	if req.SessionID <= 0 {
+		http.Error(w, `{"error":"session_id is required"}`, http.StatusBadRequest)
 		return
 	}
 
@@ -67,19 +147,112 @@ func (h *CheckpointHandler) AttemptCheckpoint(w http.ResponseWriter, r *http.Req
 	}
 
 	// Attempt checkpoint
-	result, err := h.checkpointService.AttemptCheckpoint(firebaseUID, &req)
+	result, err := h.checkpointService.AttemptCheckpoint(r.Context(), firebaseUID, &req)
 	if err != nil {
-		log.Printf("Failed to attempt checkpoint: %v", err)
-		// Check if it's a validation error (can't attempt yet)
-		if err.Error() == "cannot attempt checkpoint: complete all tier topics first" ||
-		   err.Error()[:len("cannot attempt checkpoint")] == "cannot attempt checkpoint" {
-			http.Error(w, `{"error":"Cannot attempt checkpoint yet. Complete all tier topics first."}`, http.StatusForbidden)
-			return
-		}
-		http.Error(w, `{"error":"Failed to judge checkpoint"}`, http.StatusInternalServerError)
+		writeSessionError(w, err, "Failed to judge checkpoint")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
+
+// GetQuiz returns the verification quiz for an attempt
+// GET /api/checkpoints/attempts/{attemptID}/quiz
+func (h *CheckpointHandler) GetQuiz(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	attemptID, err := strconv.ParseInt(chi.URLParam(r, "attemptID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid attempt id"}`, http.StatusBadRequest)
+		return
+	}
+
+	quiz, err := h.checkpointService.GetQuiz(firebaseUID, attemptID)
+	if err != nil {
+		writeQuizError(w, err, "Failed to get quiz")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quiz)
+}
+
+// SubmitQuiz grades the learner's answers to an attempt's verification quiz
+// POST /api/checkpoints/attempts/{attemptID}/quiz
+func (h *CheckpointHandler) SubmitQuiz(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	attemptID, err := strconv.ParseInt(chi.URLParam(r, "attemptID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid attempt id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.QuizAnswerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.checkpointService.SubmitQuiz(r.Context(), firebaseUID, attemptID, req.Answers)
+	if err != nil {
+		writeQuizError(w, err, "Failed to grade quiz")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeQuizError maps verification quiz errors to HTTP statuses
+func writeQuizError(w http.ResponseWriter, err error, fallback string) {
+	log.Printf("%s: %v", fallback, err)
+	switch {
+	case errors.Is(err, service.ErrAttemptNotFound), errors.Is(err, service.ErrQuizNotFound):
+		http.Error(w, `{"error":"Quiz not found"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrQuizNotPending):
+		http.Error(w, `{"error":"Quiz was already answered or has expired"}`, http.StatusConflict)
+	case errors.Is(err, service.ErrQuizIncomplete):
+		http.Error(w, `{"error":"Every question needs an answer of at most 2000 characters"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrAIUnavailable):
+		http.Error(w, `{"error":"Quiz grading is temporarily unavailable. Try again shortly."}`, http.StatusServiceUnavailable)
+	default:
+		http.Error(w, jsonError(fallback), http.StatusInternalServerError)
+	}
+}
+
+// writeSessionError maps session lifecycle errors to HTTP statuses
+func writeSessionError(w http.ResponseWriter, err error, fallback string) {
+	log.Printf("%s: %v", fallback, err)
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		http.Error(w, `{"error":"Checkpoint session not found"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrSessionClosed):
+		http.Error(w, `{"error":"Checkpoint session is closed or has expired"}`, http.StatusConflict)
+	default:
+		http.Error(w, jsonError(fallback), http.StatusInternalServerError)
+	}
+}
+
+// GetVariantStats returns pass/fail statistics per checkpoint pool variant
+// GET /api/admin/checkpoints/variants
+func (h *CheckpointHandler) GetVariantStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.checkpointService.GetVariantStats()
+	if err != nil {
+		log.Printf("Failed to get variant stats: %v", err)
+		http.Error(w, `{"error":"Failed to get variant stats"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"variants": stats})
+}
@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type CohortHandler struct {
+	cohortService *service.CohortService
+}
+
+func NewCohortHandler(cohortService *service.CohortService) *CohortHandler {
+	return &CohortHandler{cohortService: cohortService}
+}
+
+// CreateCohort starts a cohort with the caller as instructor
+// POST /api/cohorts
+func (h *CohortHandler) CreateCohort(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateCohortRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	cohort, err := h.cohortService.Create(firebaseUID, &req)
+	if err != nil {
+		writeCohortError(w, err, "Failed to create cohort")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(cohort)
+}
+
+// JoinCohort adds the caller to the cohort of an invite code
+// POST /api/cohorts/join
+func (h *CohortHandler) JoinCohort(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.JoinCohortRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	cohort, err := h.cohortService.Join(firebaseUID, req.InviteCode)
+	if err != nil {
+		writeCohortError(w, err, "Failed to join cohort")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cohort)
+}
+
+// ListCohorts returns the caller's cohorts
+// GET /api/cohorts
+func (h *CohortHandler) ListCohorts(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cohorts, err := h.cohortService.List(firebaseUID)
+	if err != nil {
+		writeCohortError(w, err, "Failed to list cohorts")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"cohorts": cohorts})
+}
+
+// GetCohort returns a cohort the caller belongs to
+// GET /api/cohorts/{cohortID}
+func (h *CohortHandler) GetCohort(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "Failed to get cohort", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		return h.cohortService.Get(firebaseUID, cohortID)
+	})
+}
+
+// ListMembers returns a cohort's instructors and learners
+// GET /api/cohorts/{cohortID}/members
+func (h *CohortHandler) ListMembers(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "Failed to list cohort members", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		members, err := h.cohortService.Members(firebaseUID, cohortID)
+		return map[string]interface{}{"members": members}, err
+	})
+}
+
+// GetMastery returns the cohort's topics × learners mastery matrix
+// GET /api/cohorts/{cohortID}/mastery
+func (h *CohortHandler) GetMastery(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "Failed to get cohort mastery", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		return h.cohortService.MasteryMatrix(firebaseUID, cohortID)
+	})
+}
+
+// GetCheckpoints returns every learner's checkpoint status
+// GET /api/cohorts/{cohortID}/checkpoints
+func (h *CohortHandler) GetCheckpoints(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "Failed to get cohort checkpoints", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		learners, err := h.cohortService.Checkpoints(firebaseUID, cohortID)
+		return map[string]interface{}{"learners": learners}, err
+	})
+}
+
+// ListSubmissions returns the learners' recent judge submissions
+// GET /api/cohorts/{cohortID}/submissions
+func (h *CohortHandler) ListSubmissions(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parsePagination(r, 20, 100)
+	h.serve(w, r, "Failed to list cohort submissions", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		submissions, err := h.cohortService.Submissions(firebaseUID, cohortID, limit, offset)
+		return map[string]interface{}{"submissions": submissions, "limit": limit, "offset": offset}, err
+	})
+}
+
+// ListAssignments returns the cohort's assignments with completion status
+// GET /api/cohorts/{cohortID}/assignments
+func (h *CohortHandler) ListAssignments(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, "Failed to list assignments", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		assignments, err := h.cohortService.Assignments(firebaseUID, cohortID)
+		return map[string]interface{}{"assignments": assignments}, err
+	})
+}
+
+// CreateAssignment sets a due date for a problem or topic
+// POST /api/cohorts/{cohortID}/assignments
+func (h *CohortHandler) CreateAssignment(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cohortID, err := strconv.ParseInt(chi.URLParam(r, "cohortID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid cohort id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateAssignmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	assignment, err := h.cohortService.CreateAssignment(firebaseUID, cohortID, &req)
+	if err != nil {
+		writeCohortError(w, err, "Failed to create assignment")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(assignment)
+}
+
+// DeleteAssignment removes an assignment
+// DELETE /api/cohorts/{cohortID}/assignments/{assignmentID}
+func (h *CohortHandler) DeleteAssignment(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cohortID, err := strconv.ParseInt(chi.URLParam(r, "cohortID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid cohort id"}`, http.StatusBadRequest)
+		return
+	}
+	assignmentID, err := strconv.ParseInt(chi.URLParam(r, "assignmentID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid assignment id"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.cohortService.DeleteAssignment(firebaseUID, cohortID, assignmentID); err != nil {
+		writeCohortError(w, err, "Failed to delete assignment")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetCurriculum assigns a curriculum to the cohort's learners
+// PUT /api/cohorts/{cohortID}/curriculum
+func (h *CohortHandler) SetCurriculum(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cohortID, err := strconv.ParseInt(chi.URLParam(r, "cohortID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid cohort id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.SetCurriculumRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	cohort, err := h.cohortService.SetCurriculum(firebaseUID, cohortID, req.Curriculum)
+	if err != nil {
+		writeCohortError(w, err, "Failed to set curriculum")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cohort)
+}
+
+// GetLeaderboard ranks the cohort's learners by XP (?week=2026-W07 for a
+// weekly ranking)
+// GET /api/cohorts/{cohortID}/leaderboard
+func (h *CohortHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	week := r.URL.Query().Get("week")
+	limit, offset := parsePagination(r, 50, 100)
+	h.serve(w, r, "Failed to get leaderboard", func(firebaseUID string, cohortID int64) (interface{}, error) {
+		return h.cohortService.Leaderboard(firebaseUID, cohortID, week, limit, offset)
+	})
+}
+
+// serve handles the read endpoints of a cohort
+func (h *CohortHandler) serve(w http.ResponseWriter, r *http.Request, fallback string, get func(firebaseUID string, cohortID int64) (interface{}, error)) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	cohortID, err := strconv.ParseInt(chi.URLParam(r, "cohortID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid cohort id"}`, http.StatusBadRequest)
+		return
+	}
+
+	result, err := get(firebaseUID, cohortID)
+	if err != nil {
+		writeCohortError(w, err, fallback)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// writeCohortError maps cohort service errors to responses
+func writeCohortError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrCohortNotFound):
+		http.Error(w, `{"error":"Cohort not found"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrNotCohortInstructor):
+		http.Error(w, `{"error":"Only cohort instructors can do this"}`, http.StatusForbidden)
+	case errors.Is(err, service.ErrInvalidInviteCode):
+		http.Error(w, `{"error":"Invalid invite code"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidCohortName):
+		http.Error(w, `{"error":"Cohort name must be 1-100 characters"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidTopic):
+		http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidProblem):
+		http.Error(w, `{"error":"Invalid problem"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidDueDate):
+		http.Error(w, `{"error":"due_at must be a future time"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrAssignmentNotFound):
+		http.Error(w, `{"error":"Assignment not found"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrUnknownCurriculum):
+		http.Error(w, `{"error":"Unknown curriculum"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrTopicNotInCurriculum):
+		http.Error(w, `{"error":"Topic or problem is not in the cohort's curriculum"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidWeek):
+		http.Error(w, `{"error":"Invalid week (use an ISO week such as 2026-W07)"}`, http.StatusBadRequest)
+	default:
+		log.Printf("%s: %v", fallback, err)
+		http.Error(w, jsonError(fallback), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type CurriculumHandler struct {
+	curriculumService *service.CurriculumService
+}
+
+func NewCurriculumHandler(curriculumService *service.CurriculumService) *CurriculumHandler {
+	return &CurriculumHandler{curriculumService: curriculumService}
+}
+
+// ListCurricula returns every curriculum that can be chosen or assigned
+// GET /api/curricula
+func (h *CurriculumHandler) ListCurricula(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"curricula": h.curriculumService.List()})
+}
+
+// GetActive returns the caller's active curriculum with each topic's status
+// GET /api/curriculum
+func (h *CurriculumHandler) GetActive(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	active, err := h.curriculumService.Overview(firebaseUID)
+	if err != nil {
+		log.Printf("Failed to get curriculum: %v", err)
+		http.Error(w, `{"error":"Failed to get curriculum"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(active)
+}
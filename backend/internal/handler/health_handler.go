@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+// HealthHandler reports the state of upstream dependencies
+type HealthHandler struct {
+	aiProviders []*service.GeminiService
+}
+
+// NewHealthHandler takes the primary Gemini service first, then any judge panel members
+func NewHealthHandler(aiProviders ...*service.GeminiService) *HealthHandler {
+	return &HealthHandler{aiProviders: aiProviders}
+}
+
+type aiProviderHealth struct {
+	Model   string                `json:"model"`
+	Breaker service.BreakerStatus `json:"breaker"`
+}
+
+// AI reports circuit breaker state for each AI provider. Status is "ok" when
+// every breaker is closed, "down" when the primary is open and "degraded"
+// otherwise. Always 200 so an AI outage doesn't pull the API out of rotation.
+// GET /health/ai
+func (h *HealthHandler) AI(w http.ResponseWriter, r *http.Request) {
+	status := "ok"
+	providers := make([]aiProviderHealth, 0, len(h.aiProviders))
+	for i, provider := range h.aiProviders {
+		breaker := provider.BreakerStatus()
+		if breaker.State != service.BreakerClosed {
+			if i == 0 && breaker.State == service.BreakerOpen {
+				status = "down"
+			} else if status == "ok" {
+				status = "degraded"
+			}
+		}
+		providers = append(providers, aiProviderHealth{
+			Model:   provider.Model(),
+			Breaker: breaker,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":    status,
+		"providers": providers,
+	})
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// jsonError renders an error message as the {"error": "..."} body used by all handlers
+func jsonError(message string) string {
+	body, _ := json.Marshal(map[string]string{"error": message})
+	return string(body)
+}
+
+// parsePagination reads limit/offset query parameters, clamping limit to max
+func parsePagination(r *http.Request, defaultLimit, maxLimit int) (int, int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+// writeAIUnavailable writes a 503 telling the client when to retry an AI call
+func writeAIUnavailable(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 5
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, `{"error":"AI is temporarily unavailable"}`, http.StatusServiceUnavailable)
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type LeaderboardHandler struct {
+	leaderboardService *service.LeaderboardService
+}
+
+func NewLeaderboardHandler(leaderboardService *service.LeaderboardService) *LeaderboardHandler {
+	return &LeaderboardHandler{leaderboardService: leaderboardService}
+}
+
+// GetGlobal returns the all-time XP leaderboard
+// GET /api/leaderboards/global
+func (h *LeaderboardHandler) GetGlobal(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, func(firebaseUID string, limit, offset int) (*models.Leaderboard, error) {
+		return h.leaderboardService.Global(firebaseUID, limit, offset)
+	})
+}
+
+// GetWeekly returns the XP leaderboard of an ISO week (?week=2026-W07,
+// default the current week)
+// GET /api/leaderboards/weekly
+func (h *LeaderboardHandler) GetWeekly(w http.ResponseWriter, r *http.Request) {
+	week := r.URL.Query().Get("week")
+	h.serve(w, r, func(firebaseUID string, limit, offset int) (*models.Leaderboard, error) {
+		return h.leaderboardService.Weekly(firebaseUID, week, limit, offset)
+	})
+}
+
+// GetTopic returns the practice XP leaderboard of one topic
+// GET /api/leaderboards/topics/{topicKey}
+func (h *LeaderboardHandler) GetTopic(w http.ResponseWriter, r *http.Request) {
+	topicKey := chi.URLParam(r, "topicKey")
+	h.serve(w, r, func(firebaseUID string, limit, offset int) (*models.Leaderboard, error) {
+		return h.leaderboardService.Topic(firebaseUID, topicKey, limit, offset)
+	})
+}
+
+func (h *LeaderboardHandler) serve(w http.ResponseWriter, r *http.Request, get func(firebaseUID string, limit, offset int) (*models.Leaderboard, error)) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset := parsePagination(r, 50, 100)
+	board, err := get(firebaseUID, limit, offset)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTopic):
+			http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrInvalidWeek):
+			http.Error(w, `{"error":"Invalid week (use an ISO week such as 2026-W07)"}`, http.StatusBadRequest)
+		default:
+			log.Printf("Failed to get leaderboard: %v", err)
+			http.Error(w, `{"error":"Failed to get leaderboard"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
@@ -4,9 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"github.com/go-chi/chi/v5"
 	"github.com/yourusername/skilltree/internal/middleware"
-	"github.com/yourusername/skilltree/internal/models"
 	"github.com/yourusername/skilltree/internal/service"
 )
 
@@ -36,33 +34,3 @@ func (h *MasteryHandler) GetMastery(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(mastery)
 }
-
-// UpdateMastery updates mastery for a specific topic
-// PUT /api/mastery/:topicKey
-func (h *MasteryHandler) UpdateMastery(w http.ResponseWriter, r *http.Request) {
-	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
-	if !ok {
-		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
-		return
-	}
-
-	topicKey := chi.URLParam(r, "topicKey")
-	if topicKey == "" {
-		http.Error(w, `{"error":"topic_key is required"}`, http.StatusBadRequest)
-		return
-	}
-
-	var req models.UpdateMasteryRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
-		return
-	}
-
-	if err := h.masteryService.UpdateMastery(firebaseUID, topicKey, &req); err != nil {
-		http.Error(w, `{"error":"Failed to update mastery"}`, http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]bool{"success": true})
-}
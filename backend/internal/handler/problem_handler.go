@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/sandbox"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type ProblemHandler struct {
+	problemService *service.ProblemService
+}
+
+func NewProblemHandler(problemService *service.ProblemService) *ProblemHandler {
+	return &ProblemHandler{problemService: problemService}
+}
+
+// ListProblems returns a topic's built-in and published authored problems
+// GET /api/problems/topics/{topicKey}
+func (h *ProblemHandler) ListProblems(w http.ResponseWriter, r *http.Request) {
+	problems, err := h.problemService.List(chi.URLParam(r, "topicKey"))
+	if err != nil {
+		writeProblemError(w, err, "Failed to list problems")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"problems": problems})
+}
+
+// SubmitProblem validates a new problem and queues it for moderation
+// POST /api/problems/authored
+func (h *ProblemHandler) SubmitProblem(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.SubmitProblemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	problem, err := h.problemService.Submit(r.Context(), firebaseUID, &req)
+	if err != nil {
+		writeProblemError(w, err, "Failed to submit problem")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(problem)
+}
+
+// ListMine returns the caller's problems with all their versions
+// GET /api/problems/authored
+func (h *ProblemHandler) ListMine(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	problems, err := h.problemService.Mine(firebaseUID)
+	if err != nil {
+		writeProblemError(w, err, "Failed to list problems")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"problems": problems})
+}
+
+// GetMine returns one of the caller's problems
+// GET /api/problems/authored/{problemID}
+func (h *ProblemHandler) GetMine(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "problemID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid problem id"}`, http.StatusBadRequest)
+		return
+	}
+
+	problem, err := h.problemService.Get(firebaseUID, id)
+	if err != nil {
+		writeProblemError(w, err, "Failed to get problem")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(problem)
+}
+
+// ReviseProblem validates a new version of one of the caller's problems
+// and queues it for moderation
+// POST /api/problems/authored/{problemID}/versions
+func (h *ProblemHandler) ReviseProblem(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	id, err := strconv.ParseInt(chi.URLParam(r, "problemID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid problem id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var draft models.ProblemDraft
+	if err := json.NewDecoder(r.Body).Decode(&draft); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.problemService.Revise(r.Context(), firebaseUID, id, &draft)
+	if err != nil {
+		writeProblemError(w, err, "Failed to revise problem")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(version)
+}
+
+// ListQueue lists users' problem versions for mentors (pending by default)
+// GET /api/mentor/problems?status=pending&limit=20&offset=0
+func (h *ProblemHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	h.listQueue(w, r, models.ProblemSourceUser)
+}
+
+// GetVersion returns one version of a user's problem with its tests,
+// reference solution and validation run
+// GET /api/mentor/problems/{versionID}
+func (h *ProblemHandler) GetVersion(w http.ResponseWriter, r *http.Request) {
+	h.getVersion(w, r, models.ProblemSourceUser)
+}
+
+// Moderate approves (publishes) or rejects a pending version of a user's
+// problem
+// POST /api/mentor/problems/{versionID}/moderate
+func (h *ProblemHandler) Moderate(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, models.ProblemSourceUser)
+}
+
+// GenerateProblem has the LLM draft a problem and stores it unpublished
+// POST /api/admin/problems/generate
+func (h *ProblemHandler) GenerateProblem(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.GenerateProblemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	problem, err := h.problemService.Generate(r.Context(), firebaseUID, &req)
+	if err != nil {
+		writeProblemError(w, err, "Failed to generate problem")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if !req.DryRun {
+		w.WriteHeader(http.StatusCreated)
+	}
+	json.NewEncoder(w).Encode(problem)
+}
+
+// ListGenerated lists LLM-drafted problem versions for admins (pending by
+// default)
+// GET /api/admin/problems/generated?status=pending&limit=20&offset=0
+func (h *ProblemHandler) ListGenerated(w http.ResponseWriter, r *http.Request) {
+	h.listQueue(w, r, models.ProblemSourceGenerated)
+}
+
+// GetGenerated returns one LLM-drafted version
+// GET /api/admin/problems/generated/{versionID}
+func (h *ProblemHandler) GetGenerated(w http.ResponseWriter, r *http.Request) {
+	h.getVersion(w, r, models.ProblemSourceGenerated)
+}
+
+// ModerateGenerated approves (publishes) or rejects an LLM draft
+// POST /api/admin/problems/generated/{versionID}/moderate
+func (h *ProblemHandler) ModerateGenerated(w http.ResponseWriter, r *http.Request) {
+	h.moderate(w, r, models.ProblemSourceGenerated)
+}
+
+// listQueue lists versions of problems from source by moderation status
+func (h *ProblemHandler) listQueue(w http.ResponseWriter, r *http.Request, source string) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = models.ProblemVersionPending
+	}
+	switch status {
+	case models.ProblemVersionPending, models.ProblemVersionApproved, models.ProblemVersionRejected:
+	default:
+		http.Error(w, `{"error":"status must be pending, approved or rejected"}`, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := parsePagination(r, 20, 100)
+
+	versions, err := h.problemService.Queue(source, status, limit, offset)
+	if err != nil {
+		writeProblemError(w, err, "Failed to list problems")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"versions": versions})
+}
+
+// getVersion returns one version of a problem from source
+func (h *ProblemHandler) getVersion(w http.ResponseWriter, r *http.Request, source string) {
+	versionID, err := strconv.ParseInt(chi.URLParam(r, "versionID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid version id"}`, http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.problemService.GetVersion(versionID, source)
+	if err != nil {
+		writeProblemError(w, err, "Failed to get problem")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version)
+}
+
+// moderate records a decision on a version of a problem from source
+func (h *ProblemHandler) moderate(w http.ResponseWriter, r *http.Request, source string) {
+	moderatorUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	versionID, err := strconv.ParseInt(chi.URLParam(r, "versionID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid version id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.ModerateProblemRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	version, err := h.problemService.Moderate(moderatorUID, versionID, source, &req)
+	if err != nil {
+		writeProblemError(w, err, "Failed to moderate problem")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(version)
+}
+
+// writeProblemError maps problem errors to HTTP statuses. A reference
+// solution that fails its tests is a 422 carrying the validation run.
+func writeProblemError(w http.ResponseWriter, err error, fallback string) {
+	var validationErr *service.ProblemValidationError
+	if errors.As(err, &validationErr) {
+		body := map[string]interface{}{"error": validationErr.Error()}
+		if validationErr.Message == "" {
+			body["validation"] = validationErr.Validation
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(body)
+		return
+	}
+
+	switch {
+	case errors.Is(err, service.ErrProblemNotFound):
+		http.Error(w, `{"error":"Problem not found"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrInvalidTopic):
+		http.Error(w, `{"error":"Invalid topic"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrInvalidProblemDraft), errors.Is(err, service.ErrInvalidDecision),
+		errors.Is(err, service.ErrInvalidPattern), errors.Is(err, sandbox.ErrInvalidTestCase), errors.Is(err, sandbox.ErrInvalidEntry):
+		http.Error(w, jsonError(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, sandbox.ErrUnsupportedLanguage):
+		http.Error(w, `{"error":"solution language must be javascript or python"}`, http.StatusBadRequest)
+	case errors.Is(err, service.ErrSelfModeration):
+		http.Error(w, jsonError(err.Error()), http.StatusForbidden)
+	case errors.Is(err, service.ErrRevisionPending), errors.Is(err, service.ErrProblemAlreadyReviewed):
+		http.Error(w, jsonError(err.Error()), http.StatusConflict)
+	case errors.Is(err, service.ErrSandboxDisabled), errors.Is(err, sandbox.ErrNotIsolated):
+		http.Error(w, `{"error":"Problem submissions need the sandbox, which is disabled"}`, http.StatusNotImplemented)
+	case errors.Is(err, service.ErrAIUnavailable):
+		writeAIUnavailable(w, 0)
+	case errors.Is(err, sandbox.ErrBusy):
+		w.Header().Set("Retry-After", "5")
+		http.Error(w, `{"error":"Sandbox is busy, try again shortly"}`, http.StatusServiceUnavailable)
+	default:
+		log.Printf("%s: %v", fallback, err)
+		http.Error(w, jsonError(fallback), http.StatusInternalServerError)
+	}
+}
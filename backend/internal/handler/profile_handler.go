@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type ProfileHandler struct {
+	progressService *service.ProgressService
+}
+
+func NewProfileHandler(progressService *service.ProgressService) *ProfileHandler {
+	return &ProfileHandler{progressService: progressService}
+}
+
+// GetStats returns the user's XP, level and streak
+// GET /api/profile/stats
+func (h *ProfileHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	stats, err := h.progressService.Stats(firebaseUID)
+	if err != nil {
+		log.Printf("Failed to get profile stats: %v", err)
+		http.Error(w, `{"error":"Failed to get profile stats"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetSettings returns the user's settings
+// GET /api/profile/settings
+func (h *ProfileHandler) GetSettings(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	settings, err := h.progressService.GetSettings(firebaseUID)
+	if err != nil {
+		log.Printf("Failed to get settings: %v", err)
+		http.Error(w, `{"error":"Failed to get settings"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// UpdateSettings changes the user's settings
+// PUT /api/profile/settings
+func (h *ProfileHandler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.UpdateSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.progressService.UpdateSettings(firebaseUID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTimezone):
+			http.Error(w, `{"error":"Invalid timezone (use an IANA name such as Europe/Berlin)"}`, http.StatusBadRequest)
+		case errors.Is(err, service.ErrUnknownCurriculum):
+			http.Error(w, `{"error":"Unknown curriculum"}`, http.StatusBadRequest)
+		default:
+			log.Printf("Failed to update settings: %v", err)
+			http.Error(w, `{"error":"Failed to update settings"}`, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
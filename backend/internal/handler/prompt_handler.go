@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/prompts"
+)
+
+type PromptHandler struct {
+	registry *prompts.Registry
+}
+
+func NewPromptHandler(registry *prompts.Registry) *PromptHandler {
+	return &PromptHandler{registry: registry}
+}
+
+type promptResponse struct {
+	ID string `json:"id"`
+	prompts.Prompt
+}
+
+// ListPrompts returns the active prompt templates with their versions
+// GET /api/admin/prompts
+func (h *PromptHandler) ListPrompts(w http.ResponseWriter, r *http.Request) {
+	active := h.registry.List()
+	response := make([]promptResponse, 0, len(active))
+	for _, prompt := range active {
+		response = append(response, promptResponse{ID: prompt.ID(), Prompt: prompt})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"prompts": response})
+}
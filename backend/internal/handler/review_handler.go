@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type ReviewHandler struct {
+	reviewService *service.ReviewService
+}
+
+func NewReviewHandler(reviewService *service.ReviewService) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService}
+}
+
+// Appeal flags a rejected submission for mentor review
+// POST /api/reviews/appeals
+func (h *ReviewHandler) Appeal(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.AppealRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	review, err := h.reviewService.Appeal(firebaseUID, &req)
+	if err != nil {
+		writeReviewError(w, err, "Failed to submit appeal")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(review)
+}
+
+// ListQueue lists reviews for mentors (pending by default)
+// GET /api/mentor/reviews?status=pending&limit=20&offset=0
+func (h *ReviewHandler) ListQueue(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status == "" {
+		status = models.ReviewPending
+	}
+	if status != models.ReviewPending && status != models.ReviewResolved {
+		http.Error(w, `{"error":"status must be pending or resolved"}`, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := parsePagination(r, 20, 100)
+
+	items, err := h.reviewService.ListQueue(status, limit, offset)
+	if err != nil {
+		log.Printf("Failed to list review queue: %v", err)
+		http.Error(w, `{"error":"Failed to list reviews"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"reviews": items})
+}
+
+// GetReview returns one review with its submission and audit trail
+// GET /api/mentor/reviews/{reviewID}
+func (h *ReviewHandler) GetReview(w http.ResponseWriter, r *http.Request) {
+	reviewID, err := strconv.ParseInt(chi.URLParam(r, "reviewID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid review id"}`, http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.reviewService.GetReview(reviewID)
+	if err != nil {
+		writeReviewError(w, err, "Failed to get review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// Resolve records a mentor's verdict, overriding the AI judge
+// POST /api/mentor/reviews/{reviewID}/resolve
+func (h *ReviewHandler) Resolve(w http.ResponseWriter, r *http.Request) {
+	mentorUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	reviewID, err := strconv.ParseInt(chi.URLParam(r, "reviewID"), 10, 64)
+	if err != nil {
+		http.Error(w, `{"error":"Invalid review id"}`, http.StatusBadRequest)
+		return
+	}
+
+	var req models.ResolveReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	item, err := h.reviewService.Resolve(mentorUID, reviewID, &req)
+	if err != nil {
+		writeReviewError(w, err, "Failed to resolve review")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(item)
+}
+
+// writeReviewError maps review errors to HTTP statuses
+func writeReviewError(w http.ResponseWriter, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrReviewNotFound):
+		http.Error(w, `{"error":"Review or submission not found"}`, http.StatusNotFound)
+	case errors.Is(err, service.ErrNotAppealable), errors.Is(err, service.ErrInvalidReviewVerdict):
+		http.Error(w, jsonError(err.Error()), http.StatusBadRequest)
+	case errors.Is(err, service.ErrSelfReview):
+		http.Error(w, jsonError(err.Error()), http.StatusForbidden)
+	case errors.Is(err, service.ErrAlreadyInReview), errors.Is(err, service.ErrReviewAlreadyResolved):
+		http.Error(w, jsonError(err.Error()), http.StatusConflict)
+	default:
+		log.Printf("%s: %v", fallback, err)
+		http.Error(w, jsonError(fallback), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type SecurityHandler struct {
+	guard *service.SubmissionGuard
+}
+
+func NewSecurityHandler(guard *service.SubmissionGuard) *SecurityHandler {
+	return &SecurityHandler{guard: guard}
+}
+
+// ListEvents returns recent security events, newest first
+// GET /api/admin/security-events?type=prompt_injection_suspected&limit=50&offset=0
+func (h *SecurityHandler) ListEvents(w http.ResponseWriter, r *http.Request) {
+	limit, offset := parsePagination(r, 50, 200)
+
+	events, err := h.guard.ListEvents(r.URL.Query().Get("type"), limit, offset)
+	if err != nil {
+		log.Printf("Failed to list security events: %v", err)
+		http.Error(w, `{"error":"Failed to list security events"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"events": events})
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/service"
+)
+
+type SimilarityHandler struct {
+	similarityService *service.SimilarityService
+}
+
+func NewSimilarityHandler(similarityService *service.SimilarityService) *SimilarityHandler {
+	return &SimilarityHandler{similarityService: similarityService}
+}
+
+// CreateReference adds a known public solution to compare submissions with
+// POST /api/admin/similarity/references
+func (h *SimilarityHandler) CreateReference(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, ok := middleware.GetFirebaseUID(r.Context())
+	if !ok {
+		http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateReferenceSolutionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error":"Invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+	if req.Code == "" || req.Source == "" {
+		http.Error(w, `{"error":"code and source are required"}`, http.StatusBadRequest)
+		return
+	}
+
+	ref, err := h.similarityService.AddReference(firebaseUID, &req)
+	if errors.Is(err, service.ErrInvalidProblem) {
+		http.Error(w, `{"error":"Invalid checkpoint problem"}`, http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to add reference solution: %v", err)
+		http.Error(w, `{"error":"Failed to add reference solution"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(ref)
+}
+
+// ListReferences returns reference solutions
+// GET /api/admin/similarity/references?problem_id=...
+func (h *SimilarityHandler) ListReferences(w http.ResponseWriter, r *http.Request) {
+	refs, err := h.similarityService.ListReferences(r.URL.Query().Get("problem_id"))
+	if err != nil {
+		log.Printf("Failed to list reference solutions: %v", err)
+		http.Error(w, `{"error":"Failed to list reference solutions"}`, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"references": refs})
+}
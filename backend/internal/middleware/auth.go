@@ -41,8 +41,11 @@ func AuthMiddleware(authClient *auth.Client) func(http.Handler) http.Handler {
 				return
 			}
 
-			// Add Firebase UID to request context
+			// Add Firebase UID (and role claim, if any) to request context
 			ctx := context.WithValue(r.Context(), UserIDKey, token.UID)
+			if role, ok := token.Claims["role"].(string); ok {
+				ctx = context.WithValue(ctx, UserRoleKey, role)
+			}
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+)
+
+// responseGrace is how long a handler has to store and write its result
+// after its budget runs out
+const responseGrace = 10 * time.Second
+
+// RequestBudget gives slow routes (judge panels, sandbox runs) their own
+// time limit instead of the server's write timeout. The request context is
+// cancelled after budget so the handler can give up and answer, and the
+// write deadline is pushed past it so that answer reaches the client.
+func RequestBudget(budget time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(budget + responseGrace)); err != nil {
+				log.Printf("Failed to extend write deadline for %s: %v", r.URL.Path, err)
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), budget)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// QuotaChecker decides whether a user still has LLM budget left
+type QuotaChecker interface {
+	CheckQuota(firebaseUID string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// QuotaMiddleware rejects requests from users who exhausted their daily LLM
+// budget. Must run after AuthMiddleware.
+func QuotaMiddleware(checker QuotaChecker) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			firebaseUID, ok := GetFirebaseUID(r.Context())
+			if !ok {
+				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			allowed, retryAfter, err := checker.CheckQuota(firebaseUID)
+			if err != nil {
+				// Fail open: accounting problems shouldn't block learners
+				log.Printf("Quota check failed for %s: %v", firebaseUID, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				WriteTooManyRequests(w, retryAfter, "Daily AI usage budget exhausted. Try again tomorrow.")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
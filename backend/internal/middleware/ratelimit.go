@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/ratelimit"
+)
+
+// RateLimitMiddleware limits each user per route. Must run after AuthMiddleware.
+func RateLimitMiddleware(store ratelimit.Store, route string, rule ratelimit.Rule) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			firebaseUID, ok := GetFirebaseUID(r.Context())
+			if !ok {
+				http.Error(w, `{"error":"Unauthorized"}`, http.StatusUnauthorized)
+				return
+			}
+
+			allowed, retryAfter, err := store.Take(firebaseUID+":"+route, rule)
+			if err != nil {
+				// Fail open: a broken limiter shouldn't take the API down
+				log.Printf("Rate limiter error for %s: %v", route, err)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !allowed {
+				WriteTooManyRequests(w, retryAfter, "Rate limit exceeded. Slow down.")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// WriteTooManyRequests writes a 429 with a Retry-After header in whole seconds
+func WriteTooManyRequests(w http.ResponseWriter, retryAfter time.Duration, message string) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, fmt.Sprintf(`{"error":%q,"retry_after":%d}`, message, seconds), http.StatusTooManyRequests)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// UserRoleKey is the context key for the role custom claim
+	UserRoleKey contextKey = "userRole"
+)
+
+// Roles granted through the Firebase "role" custom claim
+const (
+	RoleAdmin  = "admin"
+	RoleAuthor = "author"
+	RoleMentor = "mentor"
+	// RoleInstructor may create cohorts
+	RoleInstructor = "instructor"
+)
+
+// GetUserRole retrieves the role custom claim from the request context
+func GetUserRole(ctx context.Context) (string, bool) {
+	role, ok := ctx.Value(UserRoleKey).(string)
+	return role, ok && role != ""
+}
+
+// RequireRole rejects requests whose token doesn't carry one of the given roles.
+// Admins are always allowed. Must run after AuthMiddleware.
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			role, ok := GetUserRole(r.Context())
+			if !ok {
+				http.Error(w, `{"error":"Forbidden"}`, http.StatusForbidden)
+				return
+			}
+
+			if role == RoleAdmin {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			for _, allowed := range roles {
+				if role == allowed {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			http.Error(w, `{"error":"Forbidden"}`, http.StatusForbidden)
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// UserAchievement is a badge a user earned and the event that earned it
+type UserAchievement struct {
+	UserUID        string    `json:"-"`
+	AchievementKey string    `json:"achievement_key"`
+	SubjectType    string    `json:"subject_type,omitempty"`
+	SubjectID      int64     `json:"subject_id,omitempty"`
+	AwardedAt      time.Time `json:"awarded_at"`
+}
+
+// Achievement is a badge in the catalog and whether the user has earned it
+type Achievement struct {
+	Key         string     `json:"key"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Earned      bool       `json:"earned"`
+	AwardedAt   *time.Time `json:"awarded_at,omitempty"`
+}
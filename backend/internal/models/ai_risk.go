@@ -0,0 +1,29 @@
+package models
+
+// EditorTelemetry is what the client IDE observed while the learner wrote a
+// checkpoint submission. Every field is optional; older clients send none.
+type EditorTelemetry struct {
+	// Keystrokes counts key presses that changed the editor contents
+	Keystrokes int `json:"keystrokes"`
+	// PasteEvents and PastedChars count every paste into the editor
+	PasteEvents int `json:"paste_events"`
+	PastedChars int `json:"pasted_chars"`
+	// LargestPasteChars is the size of the biggest single paste
+	LargestPasteChars int `json:"largest_paste_chars"`
+	// ActiveTypingMs is time spent typing, excluding idle gaps
+	ActiveTypingMs int64 `json:"active_typing_ms"`
+}
+
+// AIRiskSignal is one heuristic's contribution to an AI-generation risk score
+type AIRiskSignal struct {
+	Name   string `json:"name"`
+	Points int    `json:"points"`
+	Detail string `json:"detail"`
+}
+
+// AIRiskAssessment estimates how likely a submission was pasted from an AI
+// assistant, from 0 (no signal) to 100
+type AIRiskAssessment struct {
+	Score   int            `json:"score"`
+	Signals []AIRiskSignal `json:"signals"`
+}
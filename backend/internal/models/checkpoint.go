@@ -2,33 +2,114 @@ package models
 
 import (
 	"database/sql"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/data"
 )
 
 type TierCheckpoint struct {
+	ID                  int64        `json:"id"`
+	UserID              int64        `json:"user_id"`
+	TierNumber          int          `json:"tier_number"`
+	IsPassed            bool         `json:"is_passed"`
+	Attempts            int          `json:"attempts"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastAttemptAt       sql.NullTime `json:"last_attempt_at,omitempty"`
+	NextAttemptAt       sql.NullTime `json:"next_attempt_at,omitempty"`
+	PassedAt            sql.NullTime `json:"passed_at,omitempty"`
+	SubmittedCode       string       `json:"submitted_code,omitempty"`
+	CreatedAt           sql.NullTime `json:"created_at"`
+	UpdatedAt           sql.NullTime `json:"updated_at"`
+}
+
+// CheckpointAttempt is a single recorded submission for a tier checkpoint
+type CheckpointAttempt struct {
 	ID            int64        `json:"id"`
-	UserID        int64        `json:"user_id"`
+	UserUID       string       `json:"user_uid"`
+	SessionID     int64        `json:"session_id,omitempty"`
 	TierNumber    int          `json:"tier_number"`
-	IsPassed      bool         `json:"is_passed"`
-	Attempts      int          `json:"attempts"`
-	LastAttemptAt sql.NullTime `json:"last_attempt_at,omitempty"`
-	PassedAt      sql.NullTime `json:"passed_at,omitempty"`
+	AttemptNumber int          `json:"attempt_number"`
+	ProblemID     string       `json:"problem_id"`
 	SubmittedCode string       `json:"submitted_code,omitempty"`
-	CreatedAt     sql.NullTime `json:"created_at"`
-	UpdatedAt     sql.NullTime `json:"updated_at"`
+	Verdict       string       `json:"verdict,omitempty"`
+	Feedback      string       `json:"feedback,omitempty"`
+	JudgeVotes    []JudgeVote  `json:"judge_votes,omitempty"`
+	PromptVersion string       `json:"prompt_version,omitempty"`
+	TestResults   []TestResult `json:"test_results,omitempty"`
+	// Telemetry and AIRisk are nil for attempts recorded before they existed
+	Telemetry *EditorTelemetry  `json:"telemetry,omitempty"`
+	AIRisk    *AIRiskAssessment `json:"ai_risk,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	JudgedAt  sql.NullTime      `json:"judged_at,omitempty"`
+}
+
+// Checkpoint session statuses
+const (
+	SessionActive    = "active"
+	SessionSubmitted = "submitted"
+	SessionExpired   = "expired"
+)
+
+// VerdictExpired is recorded on attempts whose session ran out of time
+const VerdictExpired = "EXPIRED"
+
+// CheckpointSession is a timed exam window for one checkpoint attempt
+type CheckpointSession struct {
+	ID            int64        `json:"id"`
+	UserUID       string       `json:"-"`
+	TierNumber    int          `json:"tier_number"`
+	AttemptNumber int          `json:"attempt_number"`
+	ProblemID     string       `json:"problem_id"`
+	Status        string       `json:"status"`
+	StartedAt     time.Time    `json:"started_at"`
+	DeadlineAt    time.Time    `json:"deadline_at"`
+	DraftCode     string       `json:"draft_code,omitempty"`
+	DraftSavedAt  sql.NullTime `json:"draft_saved_at,omitempty"`
+	AttemptID     int64        `json:"attempt_id,omitempty"`
+	ClosedAt      sql.NullTime `json:"closed_at,omitempty"`
+}
+
+// StartSessionResponse is returned when a checkpoint session is started or resumed
+type StartSessionResponse struct {
+	Session    *CheckpointSession      `json:"session"`
+	Problem    *data.CheckpointProblem `json:"problem"`
+	ServerTime time.Time               `json:"server_time"`
+	Resumed    bool                    `json:"resumed"`
+}
+
+type SaveDraftRequest struct {
+	Code string `json:"code"`
 }
 
 type CheckpointAttemptRequest struct {
-	TierNumber int    `json:"tier_number"`
-	Code       string `json:"code"`
+	SessionID   int64        `json:"session_id"`
+	TierNumber  int          `json:"tier_number"`
+	Code        string       `json:"code"`
+	TestResults []TestResult `json:"test_results,omitempty"`
+	// Telemetry is optional editor metadata used for AI-generation screening
+	Telemetry *EditorTelemetry `json:"telemetry,omitempty"`
 }
 
 type CheckpointJudgeResponse struct {
+	AttemptID       int64    `json:"attempt_id"`
+	ProblemID       string   `json:"problem_id"`
 	Verdict         string   `json:"verdict"`
 	Feedback        string   `json:"feedback"`
 	PatternsFound   []string `json:"patterns_found"`
 	MissingPatterns []string `json:"missing_patterns"`
 	IsPassed        bool     `json:"is_passed"`
 	Attempts        int      `json:"attempts"`
+	// NeedsReview is set when a mentor will decide (judges split or judge unavailable)
+	NeedsReview bool        `json:"needs_review"`
+	Agreement   float64     `json:"agreement"`
+	Votes       []JudgeVote `json:"votes,omitempty"`
+	// RemainingAttempts is nil when the tier has no daily cap
+	RemainingAttempts *int       `json:"remaining_attempts,omitempty"`
+	NextAllowedAt     *time.Time `json:"next_allowed_at,omitempty"`
+	// Degraded is set when the AI judge was down and the attempt went to review
+	Degraded bool `json:"degraded,omitempty"`
+	// Quiz must be answered before a QUIZ_REQUIRED pass counts
+	Quiz *VerificationQuiz `json:"quiz,omitempty"`
 }
 
 type CheckpointStatus struct {
@@ -36,8 +117,35 @@ type CheckpointStatus struct {
 	IsPassed   bool `json:"is_passed"`
 	Attempts   int  `json:"attempts"`
 	CanAttempt bool `json:"can_attempt"`
+	// RemainingAttempts is nil when the tier has no daily cap
+	RemainingAttempts *int       `json:"remaining_attempts,omitempty"`
+	NextAllowedAt     *time.Time `json:"next_allowed_at,omitempty"`
+	// ActiveSession is the open exam window for this tier, if any
+	ActiveSession *CheckpointSession `json:"active_session,omitempty"`
 }
 
 type CheckpointResponse struct {
 	Checkpoints map[int]CheckpointStatus `json:"checkpoints"`
 }
+
+// CheckpointVariantStats aggregates outcomes for one checkpoint pool variant
+type CheckpointVariantStats struct {
+	TierNumber  int     `json:"tier_number"`
+	ProblemID   string  `json:"problem_id"`
+	Attempts    int     `json:"attempts"`
+	Passes      int     `json:"passes"`
+	Failures    int     `json:"failures"`
+	UniqueUsers int     `json:"unique_users"`
+	PassRate    float64 `json:"pass_rate"`
+}
+
+// JudgeVote is one judge's verdict in a consensus decision
+type JudgeVote struct {
+	Judge    string `json:"judge"`
+	Verdict  string `json:"verdict"`
+	Feedback string `json:"feedback,omitempty"`
+	Error    string `json:"error,omitempty"`
+	// PromptVersion is the prompt this judge voted with; panel members can
+	// use different prompts
+	PromptVersion string `json:"prompt_version,omitempty"`
+}
@@ -0,0 +1,42 @@
+package models
+
+// Code review finding severities
+const (
+	FindingError   = "error"
+	FindingWarning = "warning"
+	FindingInfo    = "info"
+)
+
+// Code review finding categories
+const (
+	FindingCorrectness = "correctness"
+	FindingComplexity  = "complexity"
+	FindingInvariant   = "invariant"
+	FindingStyle       = "style"
+)
+
+type CodeReviewRequest struct {
+	Code string `json:"code"`
+	// TopicKey and ProblemID are optional; with them the review also checks
+	// the problem's invariant
+	TopicKey  string `json:"topic_key,omitempty"`
+	ProblemID string `json:"problem_id,omitempty"`
+}
+
+// CodeReviewFinding is one review comment anchored to a 1-based, inclusive
+// line range of the submitted code
+type CodeReviewFinding struct {
+	StartLine  int    `json:"start_line"`
+	EndLine    int    `json:"end_line"`
+	Severity   string `json:"severity"`
+	Category   string `json:"category"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+type CodeReviewResponse struct {
+	Summary       string              `json:"summary"`
+	Findings      []CodeReviewFinding `json:"findings"`
+	PromptVersion string              `json:"prompt_version"`
+	Cached        bool                `json:"cached"`
+}
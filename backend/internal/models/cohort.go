@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// Cohort member roles
+const (
+	CohortRoleInstructor = "instructor"
+	CohortRoleLearner    = "learner"
+)
+
+// Cohort is a class of learners run by one or more instructors
+type Cohort struct {
+	ID         int64  `json:"id"`
+	Name       string `json:"name"`
+	InviteCode string `json:"invite_code"`
+	// InstructorCode is only shown to instructors
+	InstructorCode string `json:"instructor_code,omitempty"`
+	// Curriculum is the key of the curriculum assigned to the cohort's
+	// learners, "" for none
+	Curriculum string    `json:"curriculum"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Role is the caller's role in the cohort
+	Role         string `json:"role"`
+	LearnerCount int    `json:"learner_count"`
+}
+
+type CreateCohortRequest struct {
+	Name string `json:"name"`
+}
+
+type SetCurriculumRequest struct {
+	Curriculum string `json:"curriculum"`
+}
+
+type JoinCohortRequest struct {
+	InviteCode string `json:"invite_code"`
+}
+
+// CohortMember is a user in a cohort, as shown to its instructors
+type CohortMember struct {
+	UserUID     string    `json:"user_uid"`
+	DisplayName string    `json:"display_name"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at"`
+}
+
+// CohortMasteryMatrix is the topics × learners confidence grid of a cohort
+type CohortMasteryMatrix struct {
+	Topics   []string            `json:"topics"`
+	Learners []LearnerMasteryRow `json:"learners"`
+}
+
+// LearnerMasteryRow holds one learner's confidence and solve count per
+// topic, in the order of CohortMasteryMatrix.Topics
+type LearnerMasteryRow struct {
+	UserUID     string `json:"user_uid"`
+	DisplayName string `json:"display_name"`
+	Confidence  []int  `json:"confidence"`
+	Solved      []int  `json:"solved"`
+}
+
+// LearnerCheckpointRow is one learner's checkpoint status across tiers
+type LearnerCheckpointRow struct {
+	UserUID     string              `json:"user_uid"`
+	DisplayName string              `json:"display_name"`
+	Tiers       []LearnerTierStatus `json:"tiers"`
+}
+
+type LearnerTierStatus struct {
+	TierNumber int        `json:"tier_number"`
+	IsPassed   bool       `json:"is_passed"`
+	Attempts   int        `json:"attempts"`
+	PassedAt   *time.Time `json:"passed_at,omitempty"`
+}
+
+// CohortSubmission is a learner's judge submission, without the code
+type CohortSubmission struct {
+	ID          int64     `json:"id"`
+	UserUID     string    `json:"user_uid"`
+	DisplayName string    `json:"display_name"`
+	TopicKey    string    `json:"topic_key"`
+	ProblemID   string    `json:"problem_id"`
+	Verdict     string    `json:"verdict"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CohortAssignment sets a due date for a problem, or for a whole topic if
+// ProblemID is empty
+type CohortAssignment struct {
+	ID        int64     `json:"id"`
+	CohortID  int64     `json:"cohort_id"`
+	TopicKey  string    `json:"topic_key"`
+	ProblemID string    `json:"problem_id,omitempty"`
+	DueAt     time.Time `json:"due_at"`
+	CreatedBy string    `json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	// Completed and Overdue describe the caller's own progress (learners)
+	Completed *bool `json:"completed,omitempty"`
+	Overdue   bool  `json:"overdue,omitempty"`
+	// CompletedCount is how many learners have completed it (instructors)
+	CompletedCount *int `json:"completed_count,omitempty"`
+}
+
+type CreateAssignmentRequest struct {
+	TopicKey  string    `json:"topic_key"`
+	ProblemID string    `json:"problem_id"`
+	DueAt     time.Time `json:"due_at"`
+}
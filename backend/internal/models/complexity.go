@@ -0,0 +1,45 @@
+package models
+
+type ComplexityRequest struct {
+	Code string `json:"code"`
+	// Empirical also runs the code in the sandbox and fits its growth
+	Empirical bool `json:"empirical,omitempty"`
+	// Language, Entry and Input describe how to run the code empirically:
+	// "javascript" or "python", the function to call (default "solution")
+	// and the generated argument ("array", "sorted_array", "string", "int")
+	Language string `json:"language,omitempty"`
+	Entry    string `json:"entry,omitempty"`
+	Input    string `json:"input,omitempty"`
+}
+
+type ComplexityResponse struct {
+	Analysis  string             `json:"analysis"`
+	Cached    bool               `json:"cached"`
+	Empirical *ComplexityProfile `json:"empirical,omitempty"`
+}
+
+// ComplexitySample is the measured cost of one call at input size N
+type ComplexitySample struct {
+	N           int     `json:"n"`
+	TimeMs      float64 `json:"time_ms"`
+	MemoryBytes int64   `json:"memory_bytes"`
+}
+
+// ComplexityProfile is the growth measured in the sandbox next to what the
+// LLM analysis claims
+type ComplexityProfile struct {
+	Samples []ComplexitySample `json:"samples"`
+	// TimedOut means the run hit its time limit; the fit uses what was
+	// measured before that
+	TimedOut bool `json:"timed_out"`
+	// TimeClass and SpaceClass are empty when too few sizes were measured
+	TimeClass        string   `json:"time_class,omitempty"`
+	TimeAlternatives []string `json:"time_alternatives,omitempty"`
+	SpaceClass       string   `json:"space_class,omitempty"`
+	ClaimedTime      string   `json:"claimed_time,omitempty"`
+	ClaimedSpace     string   `json:"claimed_space,omitempty"`
+	// Disagreements describe where the measurements contradict the claim
+	Disagreements []string `json:"disagreements"`
+	// Error is set when the program could not be profiled
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "github.com/yourusername/skilltree/internal/curriculum"
+
+// Curriculum sources: why a curriculum is the learner's active one
+const (
+	CurriculumSourceUser    = "user"
+	CurriculumSourceCohort  = "cohort"
+	CurriculumSourceDefault = "default"
+)
+
+// ActiveCurriculum is the learner's curriculum with each topic evaluated
+type ActiveCurriculum struct {
+	Curriculum *curriculum.Curriculum `json:"curriculum"`
+	Source     string                 `json:"source"`
+	// CohortID is the cohort that assigned the curriculum
+	CohortID int64           `json:"cohort_id,omitempty"`
+	Topics   []TopicProgress `json:"topics"`
+}
+
+// TopicProgress is a learner's standing in one topic of their curriculum
+type TopicProgress struct {
+	Key        string `json:"key"`
+	Tier       int    `json:"tier"`
+	Status     string `json:"status"`
+	Confidence int    `json:"confidence"`
+	Threshold  int    `json:"threshold"`
+}
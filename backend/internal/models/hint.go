@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Hint levels, weakest first
+const (
+	HintNudge      = "nudge"
+	HintApproach   = "approach"
+	HintPseudocode = "pseudocode"
+)
+
+// HintLevels is the hint ladder in order; a level's rank is its index + 1
+var HintLevels = []string{HintNudge, HintApproach, HintPseudocode}
+
+type HintRequest struct {
+	TopicKey  string `json:"topic_key"`
+	ProblemID string `json:"problem_id"`
+	Code      string `json:"code"`
+	// Level is optional; defaults to the next rung of the ladder
+	Level string `json:"level,omitempty"`
+}
+
+type HintResponse struct {
+	Level string `json:"level"`
+	Hint  string `json:"hint"`
+	// NextLevel is nil once the ladder is exhausted
+	NextLevel *string `json:"next_level"`
+	// CreditPenalty is the percentage of judge credit lost for this problem
+	CreditPenalty int `json:"credit_penalty"`
+}
+
+// HintUsage is how far up the hint ladder a user has gone on a problem
+type HintUsage struct {
+	TopicKey      string     `json:"topic_key"`
+	ProblemID     string     `json:"problem_id"`
+	MaxLevel      string     `json:"max_level,omitempty"`
+	HintCount     int        `json:"hint_count"`
+	NextLevel     *string    `json:"next_level"`
+	CreditPenalty int        `json:"credit_penalty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+}
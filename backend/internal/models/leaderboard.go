@@ -0,0 +1,32 @@
+package models
+
+// Leaderboard boards
+const (
+	LeaderboardGlobal = "global"
+	LeaderboardWeekly = "weekly"
+	// LeaderboardTopicPrefix precedes the topic key of a topic board
+	LeaderboardTopicPrefix = "topic:"
+	// LeaderboardAllTime is the period of boards that never reset
+	LeaderboardAllTime = "all"
+)
+
+// LeaderboardEntry is one ranked user. Only the display name is shown.
+type LeaderboardEntry struct {
+	Rank        int    `json:"rank"`
+	DisplayName string `json:"display_name"`
+	Score       int    `json:"score"`
+	// IsCaller marks the requesting user's own entry
+	IsCaller bool `json:"is_caller,omitempty"`
+}
+
+// Leaderboard is one page of a board plus the caller's own standing
+type Leaderboard struct {
+	Board   string             `json:"board"`
+	Period  string             `json:"period"`
+	Entries []LeaderboardEntry `json:"entries"`
+	Total   int                `json:"total"`
+	Limit   int                `json:"limit"`
+	Offset  int                `json:"offset"`
+	// Caller is nil if the caller has no score or opted out
+	Caller *LeaderboardEntry `json:"caller"`
+}
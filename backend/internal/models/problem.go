@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/data"
+)
+
+// Authored problem version statuses
+const (
+	ProblemVersionPending  = "pending"
+	ProblemVersionApproved = "approved"
+	ProblemVersionRejected = "rejected"
+)
+
+// Where authored problems come from. Users' problems are moderated by
+// mentors, LLM drafts by admins.
+const (
+	ProblemSourceUser      = "user"
+	ProblemSourceGenerated = "generated"
+)
+
+// Moderation decisions
+const (
+	ProblemDecisionApprove = "approve"
+	ProblemDecisionReject  = "reject"
+)
+
+// ProblemTestCase is one call of the entry function: Args is a JSON array
+// of its arguments and Expected the JSON value it must return
+type ProblemTestCase struct {
+	Args     json.RawMessage `json:"args"`
+	Expected json.RawMessage `json:"expected"`
+}
+
+// ProblemTestResult is how the reference solution did on one test case
+type ProblemTestResult struct {
+	Passed bool            `json:"passed"`
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	TimeMs float64         `json:"time_ms"`
+}
+
+// ProblemValidation is the sandbox run of the reference solution against
+// the tests, one result per test in order
+type ProblemValidation struct {
+	Passed   bool                `json:"passed"`
+	TimedOut bool                `json:"timed_out"`
+	Results  []ProblemTestResult `json:"results"`
+}
+
+// ProblemSolution is the reference solution: code in "javascript" or
+// "python" whose Entry function is called with each test's arguments
+type ProblemSolution struct {
+	Language string `json:"language"`
+	Entry    string `json:"entry"`
+	Code     string `json:"code"`
+}
+
+// ProblemDraft is the content of a new problem or of a revision
+type ProblemDraft struct {
+	Title       string                `json:"title"`
+	Difficulty  string                `json:"difficulty"`
+	Statement   string                `json:"statement"`
+	Invariant   string                `json:"invariant"`
+	Examples    []data.ProblemExample `json:"examples"`
+	Constraints []string              `json:"constraints"`
+	Tests       []ProblemTestCase     `json:"tests"`
+	Solution    ProblemSolution       `json:"solution"`
+}
+
+type SubmitProblemRequest struct {
+	TopicKey string `json:"topic_key"`
+	ProblemDraft
+}
+
+// GenerateProblemRequest asks the LLM to draft a problem
+type GenerateProblemRequest struct {
+	TopicKey string `json:"topic_key"`
+	// Pattern is the technique the problem should exercise; defaults to the
+	// topic's name
+	Pattern string `json:"pattern,omitempty"`
+	// Difficulty defaults to Medium and Language to python
+	Difficulty string `json:"difficulty,omitempty"`
+	Language   string `json:"language,omitempty"`
+	// DryRun validates the draft without storing it
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+type ModerateProblemRequest struct {
+	// Decision is "approve" or "reject"
+	Decision string `json:"decision"`
+	Note     string `json:"note,omitempty"`
+}
+
+// AuthoredProblem is a user-written problem, served from the catalog as
+// TopicKey/ProblemID once a version is approved
+type AuthoredProblem struct {
+	ID        int64  `json:"id"`
+	TopicKey  string `json:"topic_key"`
+	ProblemID string `json:"problem_id"`
+	AuthorUID string `json:"author_uid,omitempty"`
+	Source    string `json:"source"`
+	// PublishedVersion is the version learners see, nil until one is approved
+	PublishedVersion *int             `json:"published_version"`
+	CreatedAt        time.Time        `json:"created_at"`
+	Versions         []ProblemVersion `json:"versions,omitempty"`
+}
+
+// ProblemVersion is one revision of an authored problem and its moderation
+type ProblemVersion struct {
+	ID                int64  `json:"id"`
+	AuthoredProblemID int64  `json:"authored_problem_id"`
+	TopicKey          string `json:"topic_key"`
+	ProblemID         string `json:"problem_id"`
+	AuthorUID         string `json:"author_uid,omitempty"`
+	Source            string `json:"source"`
+	Version           int    `json:"version"`
+	ProblemDraft
+	Validation ProblemValidation `json:"validation"`
+	// PromptVersion is the generation prompt of an LLM draft
+	PromptVersion string     `json:"prompt_version,omitempty"`
+	Status        string     `json:"status"`
+	ReviewedBy    string     `json:"reviewed_by,omitempty"`
+	ReviewNote    string     `json:"review_note,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// CatalogProblem is a problem as listed to learners. Version is set for
+// authored problems and is the one submissions are judged against.
+type CatalogProblem struct {
+	data.Problem
+	Authored bool `json:"authored"`
+	Version  int  `json:"version,omitempty"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// XPSourceReview is the source type of mentor review awards; other awards
+// use the review subject type of the submission that earned them
+const XPSourceReview = "review_request"
+
+// XP award reasons
+const (
+	XPReasonPracticeSolve  = "practice_solve"
+	XPReasonCheckpointPass = "checkpoint_pass"
+	XPReasonReview         = "mentor_review"
+)
+
+// XPAward is one entry in a user's XP ledger
+type XPAward struct {
+	ID         int64  `json:"id"`
+	UserUID    string `json:"-"`
+	SourceType string `json:"source_type"`
+	SourceID   int64  `json:"source_id"`
+	Reason     string `json:"reason"`
+	// TopicKey is set for practice solves and feeds the topic leaderboard
+	TopicKey  string    `json:"topic_key,omitempty"`
+	Amount    int       `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserProgress is a user's XP total and streak state
+type UserProgress struct {
+	TotalXP       int
+	CurrentStreak int
+	LongestStreak int
+	// LastActiveDay is the last active date in the user's timezone ("" if never)
+	LastActiveDay string
+	FreezeTokens  int
+	FreezesUsed   int
+}
+
+// StreakStats is the streak part of the profile stats
+type StreakStats struct {
+	Current       int    `json:"current"`
+	Longest       int    `json:"longest"`
+	LastActiveDay string `json:"last_active_day,omitempty"`
+	ActiveToday   bool   `json:"active_today"`
+	FreezeTokens  int    `json:"freeze_tokens"`
+	FreezesUsed   int    `json:"freezes_used"`
+}
+
+// ProfileStats is returned by GET /api/profile/stats
+type ProfileStats struct {
+	TotalXP        int         `json:"total_xp"`
+	Level          int         `json:"level"`
+	XPIntoLevel    int         `json:"xp_into_level"`
+	XPForNextLevel int         `json:"xp_for_next_level"`
+	Streak         StreakStats `json:"streak"`
+	Timezone       string      `json:"timezone"`
+	RecentXP       []XPAward   `json:"recent_xp"`
+}
+
+// UserSettings are a user's preferences
+type UserSettings struct {
+	// Timezone is an IANA name; streak days start at its midnight
+	Timezone string `json:"timezone"`
+	// LeaderboardOptOut hides the user from every leaderboard
+	LeaderboardOptOut bool `json:"leaderboard_opt_out"`
+	// Curriculum is the key of the learner's chosen curriculum; "" follows
+	// their cohort's, then the standard one
+	Curriculum string `json:"curriculum"`
+}
+
+type UpdateSettingsRequest struct {
+	Timezone          *string `json:"timezone"`
+	LeaderboardOptOut *bool   `json:"leaderboard_opt_out"`
+	Curriculum        *string `json:"curriculum"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Verification quiz statuses
+const (
+	QuizPending = "pending"
+	QuizPassed  = "passed"
+	QuizFailed  = "failed"
+	QuizExpired = "expired"
+)
+
+// Reasons a verification quiz was required
+const (
+	// QuizReasonAIRisk follows a checkpoint pass with a high AI-generation risk
+	QuizReasonAIRisk = "ai_risk"
+	// QuizReasonPractice follows a practice ADVANCE on a topic that asks
+	// learners to explain their solutions
+	QuizReasonPractice = "practice"
+)
+
+// QuizQuestion is one generated question about the learner's code
+type QuizQuestion struct {
+	ID int `json:"id"`
+	// Focus is what the question probes: "invariant", "complexity" or "code"
+	Focus    string `json:"focus"`
+	Question string `json:"question"`
+}
+
+// QuizAnswer is the learner's free-text answer to one question
+type QuizAnswer struct {
+	QuestionID int    `json:"question_id"`
+	Answer     string `json:"answer"`
+}
+
+// QuizGrade is the grader's score for one answer
+type QuizGrade struct {
+	QuestionID int    `json:"question_id"`
+	Score      int    `json:"score"`
+	Feedback   string `json:"feedback"`
+}
+
+// VerificationQuiz asks a learner to explain code they submitted before a
+// passing verdict is applied
+type VerificationQuiz struct {
+	ID            int64          `json:"id"`
+	UserUID       string         `json:"-"`
+	SubjectType   string         `json:"subject_type"`
+	SubjectID     int64          `json:"subject_id"`
+	Reason        string         `json:"reason"`
+	Questions     []QuizQuestion `json:"questions"`
+	Answers       []QuizAnswer   `json:"answers,omitempty"`
+	Grades        []QuizGrade    `json:"grades,omitempty"`
+	Status        string         `json:"status"`
+	Score         *int           `json:"score,omitempty"`
+	PromptVersion string         `json:"prompt_version,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+	AnsweredAt    sql.NullTime   `json:"answered_at,omitempty"`
+}
+
+type QuizAnswerRequest struct {
+	Answers []QuizAnswer `json:"answers"`
+}
+
+// QuizResult is returned after a quiz is graded
+type QuizResult struct {
+	Quiz     *VerificationQuiz `json:"quiz"`
+	Passed   bool              `json:"passed"`
+	Feedback string            `json:"feedback"`
+	// NeedsReview is set when a failed quiz sent the submission to a mentor
+	NeedsReview bool `json:"needs_review"`
+}
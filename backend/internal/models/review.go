@@ -0,0 +1,93 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Review subjects, sources and statuses
+const (
+	ReviewSubjectCheckpointAttempt = "checkpoint_attempt"
+	ReviewSubjectJudgeSubmission   = "judge_submission"
+
+	ReviewSourceJudgeSplit = "judge_split"
+	ReviewSourceAppeal     = "appeal"
+	// ReviewSourceJudgeUnavailable queues attempts submitted during an AI outage
+	ReviewSourceJudgeUnavailable = "judge_unavailable"
+	// ReviewSourceSecurityFlag holds passes that failed the submission guard
+	ReviewSourceSecurityFlag = "security_flag"
+	// ReviewSourceSimilarity holds passes that closely match other code
+	ReviewSourceSimilarity = "similarity"
+	// ReviewSourceAIRisk holds likely AI-written passes whose verification
+	// quiz failed or could not be given
+	ReviewSourceAIRisk = "ai_risk"
+
+	ReviewPending  = "pending"
+	ReviewResolved = "resolved"
+)
+
+// SystemActor is the audit actor for automated actions
+const SystemActor = "system"
+
+// Review audit actions
+const (
+	ReviewActionQueued   = "queued"
+	ReviewActionAppealed = "appealed"
+	ReviewActionResolved = "resolved"
+)
+
+// ReviewRequest queues a submission for a mentor to look at
+type ReviewRequest struct {
+	ID                int64        `json:"id"`
+	UserUID           string       `json:"user_uid"`
+	SubjectType       string       `json:"subject_type"`
+	SubjectID         int64        `json:"subject_id"`
+	Source            string       `json:"source"`
+	Status            string       `json:"status"`
+	ReopenCount       int          `json:"reopen_count"`
+	Details           string       `json:"details,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+	ResolvedAt        sql.NullTime `json:"resolved_at,omitempty"`
+	ResolvedBy        string       `json:"resolved_by,omitempty"`
+	ResolutionVerdict string       `json:"resolution_verdict,omitempty"`
+	ResolutionNote    string       `json:"resolution_note,omitempty"`
+}
+
+// ReviewAuditEntry records every change made to a review and its verdict
+type ReviewAuditEntry struct {
+	ID              int64     `json:"id"`
+	ReviewID        int64     `json:"review_id"`
+	ActorUID        string    `json:"actor_uid"`
+	Action          string    `json:"action"`
+	PreviousVerdict string    `json:"previous_verdict,omitempty"`
+	NewVerdict      string    `json:"new_verdict,omitempty"`
+	Note            string    `json:"note,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// ReviewItem is a queued review together with the submission under review
+type ReviewItem struct {
+	Review      ReviewRequest      `json:"review"`
+	TopicKey    string             `json:"topic_key,omitempty"`
+	ProblemID   string             `json:"problem_id"`
+	TierNumber  *int               `json:"tier_number,omitempty"`
+	Code        string             `json:"code"`
+	Verdict     string             `json:"verdict"`
+	Feedback    string             `json:"feedback"`
+	TestResults []TestResult       `json:"test_results,omitempty"`
+	JudgeVotes  []JudgeVote        `json:"judge_votes,omitempty"`
+	AuditTrail  []ReviewAuditEntry `json:"audit_trail,omitempty"`
+	// PromptVersion is the judge prompt behind the original verdict
+	PromptVersion string `json:"prompt_version,omitempty"`
+}
+
+type AppealRequest struct {
+	SubjectType string `json:"subject_type"`
+	SubjectID   int64  `json:"subject_id"`
+	Reason      string `json:"reason"`
+}
+
+type ResolveReviewRequest struct {
+	Verdict string `json:"verdict"`
+	Note    string `json:"note"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SecurityEvent records suspicious activity around a submission
+type SecurityEvent struct {
+	ID          int64           `json:"id"`
+	UserUID     string          `json:"user_uid"`
+	EventType   string          `json:"event_type"`
+	SubjectType string          `json:"subject_type"`
+	SubjectID   int64           `json:"subject_id"`
+	Details     json.RawMessage `json:"details,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// SimilaritySubjectReference marks fingerprints of a reference solution;
+// checkpoint attempts use ReviewSubjectCheckpointAttempt
+const SimilaritySubjectReference = "reference_solution"
+
+// SimilarityMatch is an earlier submission or reference solution that
+// shares fingerprints with a new submission
+type SimilarityMatch struct {
+	SubjectType string `json:"subject_type"`
+	SubjectID   int64  `json:"subject_id"`
+	// UserUID is empty for reference solutions
+	UserUID string `json:"user_uid,omitempty"`
+	// Score is the share of the smaller fingerprint set found in the other
+	Score  float64 `json:"score"`
+	Shared int     `json:"shared_fingerprints"`
+}
+
+// SimilarityReport is the result of comparing a submission to the corpus
+type SimilarityReport struct {
+	Fingerprints int `json:"fingerprints"`
+	// Matches are the matches at or above the threshold, best first
+	Matches []SimilarityMatch `json:"matches"`
+}
+
+// Flagged reports whether any match reached the threshold
+func (r *SimilarityReport) Flagged() bool {
+	return len(r.Matches) > 0
+}
+
+// ReferenceSolution is a known public solution copied submissions are
+// compared against
+type ReferenceSolution struct {
+	ID        int64     `json:"id"`
+	ProblemID string    `json:"problem_id"`
+	Source    string    `json:"source"`
+	Code      string    `json:"code"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateReferenceSolutionRequest struct {
+	ProblemID string `json:"problem_id"`
+	// Source says where the solution was found, e.g. a URL
+	Source string `json:"source"`
+	Code   string `json:"code"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// TestResult is the outcome of one test case run by the client IDE
+type TestResult struct {
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Passed   bool   `json:"passed"`
+}
+
+// JudgeSubmission is a stored /api/ai/judge submission and its verdict
+type JudgeSubmission struct {
+	ID        int64  `json:"id"`
+	UserUID   string `json:"user_uid"`
+	TopicKey  string `json:"topic_key"`
+	ProblemID string `json:"problem_id"`
+	// ProblemVersion is the authored problem version the code was judged
+	// against; nil for built-in problems
+	ProblemVersion *int         `json:"problem_version,omitempty"`
+	Code           string       `json:"code"`
+	Verdict        string       `json:"verdict"`
+	Feedback       string       `json:"feedback"`
+	TestResults    []TestResult `json:"test_results,omitempty"`
+	// PromptVersion is the judge prompt that produced the verdict
+	PromptVersion string    `json:"prompt_version,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type JudgeRequest struct {
+	TopicKey    string       `json:"topic_key"`
+	ProblemID   string       `json:"problem_id"`
+	Code        string       `json:"code"`
+	TestResults []TestResult `json:"test_results,omitempty"`
+}
+
+type JudgeResponse struct {
+	SubmissionID int64  `json:"submission_id,omitempty"`
+	Verdict      string `json:"verdict"`
+	Feedback     string `json:"feedback"`
+	// Cached is true when the verdict was served from the AI response cache
+	Cached bool `json:"cached"`
+	// NeedsReview is set when a passing verdict was held for a mentor
+	NeedsReview bool `json:"needs_review,omitempty"`
+	// Quiz is set when the topic asks learners to explain passing solutions;
+	// full confidence credit is granted only once it is passed
+	Quiz *VerificationQuiz `json:"quiz,omitempty"`
+	// Degraded is true when the AI judge was unavailable; nothing was stored
+	// and the learner should resubmit after RetryAfterSeconds
+	Degraded          bool `json:"degraded,omitempty"`
+	RetryAfterSeconds int  `json:"retry_after_seconds,omitempty"`
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// LLMUsage is a user's token consumption for one UTC day
+type LLMUsage struct {
+	UsageDate        time.Time `json:"usage_date"`
+	Requests         int       `json:"requests"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+	TotalTokens      int64     `json:"total_tokens"`
+}
+
+// UsageResponse reports today's usage against the daily budget
+type UsageResponse struct {
+	Usage LLMUsage `json:"usage"`
+	// DailyBudget is 0 when usage is unlimited
+	DailyBudget     int64     `json:"daily_budget"`
+	RemainingTokens *int64    `json:"remaining_tokens,omitempty"`
+	ResetsAt        time.Time `json:"resets_at"`
+}
@@ -0,0 +1,106 @@
+// Package progression holds the rules for XP levels and daily streaks.
+package progression
+
+import "time"
+
+// DayLayout is how streak days are stored: the calendar date in the user's
+// timezone
+const DayLayout = "2006-01-02"
+
+// levelStep is the XP needed to go from level 1 to 2; each level after
+// needs levelStep more than the one before
+const levelStep = 100
+
+// Level describes where a total XP falls in the level curve
+type Level struct {
+	Level int `json:"level"`
+	// XPIntoLevel is the XP earned since reaching the current level
+	XPIntoLevel int `json:"xp_into_level"`
+	// XPForNextLevel is the XP the current level spans
+	XPForNextLevel int `json:"xp_for_next_level"`
+}
+
+// LevelFor returns the level reached with xp. Level n starts at
+// levelStep*n*(n-1)/2 XP: 0, 100, 300, 600, ...
+func LevelFor(xp int) Level {
+	level, start := 1, 0
+	for xp >= start+levelStep*level {
+		start += levelStep * level
+		level++
+	}
+	return Level{
+		Level:          level,
+		XPIntoLevel:    xp - start,
+		XPForNextLevel: levelStep * level,
+	}
+}
+
+// Streak is a user's run of consecutive active days
+type Streak struct {
+	Current int
+	Longest int
+	// LastActiveDay is empty before the first activity
+	LastActiveDay string
+	// FreezeTokens each cover one missed day
+	FreezeTokens int
+}
+
+// StreakRules controls how freeze tokens are earned
+type StreakRules struct {
+	// FreezeEvery grants a token every this many streak days (0 disables)
+	FreezeEvery int
+	// MaxFreezeTokens caps how many tokens are banked
+	MaxFreezeTokens int
+}
+
+// RecordActivity advances the streak for activity on day. Missed days are
+// covered by freeze tokens if there are enough for all of them; otherwise
+// the streak restarts. It returns how many tokens were spent.
+func (r StreakRules) RecordActivity(s Streak, day time.Time) (Streak, int) {
+	gap, ok := dayGap(s.LastActiveDay, day)
+	if ok && gap <= 0 {
+		// Already counted (or the user moved west across a date line)
+		return s, 0
+	}
+
+	spent := 0
+	switch {
+	case !ok:
+		s.Current = 1
+	case gap == 1:
+		s.Current++
+	case gap-1 <= s.FreezeTokens:
+		spent = gap - 1
+		s.FreezeTokens -= spent
+		s.Current++
+	default:
+		s.Current = 1
+	}
+	s.LastActiveDay = day.Format(DayLayout)
+
+	if r.FreezeEvery > 0 && s.Current%r.FreezeEvery == 0 && s.FreezeTokens < r.MaxFreezeTokens {
+		s.FreezeTokens++
+	}
+	if s.Current > s.Longest {
+		s.Longest = s.Current
+	}
+	return s, spent
+}
+
+// Alive reports whether the streak can still continue on day: the last
+// activity was today or yesterday, or the tokens cover the gap
+func (s Streak) Alive(day time.Time) bool {
+	gap, ok := dayGap(s.LastActiveDay, day)
+	return ok && gap-1 <= s.FreezeTokens
+}
+
+// dayGap returns how many calendar days day is after lastDay; ok is false
+// if lastDay is empty or unparseable
+func dayGap(lastDay string, day time.Time) (int, bool) {
+	last, err := time.Parse(DayLayout, lastDay)
+	if err != nil {
+		return 0, false
+	}
+	current, _ := time.Parse(DayLayout, day.Format(DayLayout))
+	return int(current.Sub(last).Hours() / 24), true
+}
@@ -0,0 +1,154 @@
+package progression
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLevelFor(t *testing.T) {
+	tests := []struct {
+		xp   int
+		want Level
+	}{
+		{0, Level{Level: 1, XPIntoLevel: 0, XPForNextLevel: 100}},
+		{99, Level{Level: 1, XPIntoLevel: 99, XPForNextLevel: 100}},
+		{100, Level{Level: 2, XPIntoLevel: 0, XPForNextLevel: 200}},
+		{299, Level{Level: 2, XPIntoLevel: 199, XPForNextLevel: 200}},
+		{300, Level{Level: 3, XPIntoLevel: 0, XPForNextLevel: 300}},
+		{600, Level{Level: 4, XPIntoLevel: 0, XPForNextLevel: 400}},
+		{1000, Level{Level: 5, XPIntoLevel: 0, XPForNextLevel: 500}},
+		{4949, Level{Level: 10, XPIntoLevel: 449, XPForNextLevel: 1000}},
+	}
+	for _, tt := range tests {
+		if got := LevelFor(tt.xp); got != tt.want {
+			t.Errorf("LevelFor(%d) = %+v, want %+v", tt.xp, got, tt.want)
+		}
+	}
+}
+
+func day(s string) time.Time {
+	d, err := time.Parse(DayLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestRecordActivity(t *testing.T) {
+	rules := StreakRules{FreezeEvery: 7, MaxFreezeTokens: 2}
+
+	tests := []struct {
+		name      string
+		streak    Streak
+		day       string
+		want      Streak
+		wantSpent int
+	}{
+		{
+			"first activity",
+			Streak{},
+			"2026-03-01",
+			Streak{Current: 1, Longest: 1, LastActiveDay: "2026-03-01"},
+			0,
+		},
+		{
+			"same day again",
+			Streak{Current: 3, Longest: 3, LastActiveDay: "2026-03-01"},
+			"2026-03-01",
+			Streak{Current: 3, Longest: 3, LastActiveDay: "2026-03-01"},
+			0,
+		},
+		{
+			"earlier day after crossing a date line",
+			Streak{Current: 3, Longest: 3, LastActiveDay: "2026-03-02"},
+			"2026-03-01",
+			Streak{Current: 3, Longest: 3, LastActiveDay: "2026-03-02"},
+			0,
+		},
+		{
+			"next day",
+			Streak{Current: 3, Longest: 5, LastActiveDay: "2026-03-01"},
+			"2026-03-02",
+			Streak{Current: 4, Longest: 5, LastActiveDay: "2026-03-02"},
+			0,
+		},
+		{
+			"across a month end",
+			Streak{Current: 1, Longest: 1, LastActiveDay: "2026-02-28"},
+			"2026-03-01",
+			Streak{Current: 2, Longest: 2, LastActiveDay: "2026-03-01"},
+			0,
+		},
+		{
+			"seventh day earns a freeze token",
+			Streak{Current: 6, Longest: 6, LastActiveDay: "2026-03-06"},
+			"2026-03-07",
+			Streak{Current: 7, Longest: 7, LastActiveDay: "2026-03-07", FreezeTokens: 1},
+			0,
+		},
+		{
+			"token bank is capped",
+			Streak{Current: 13, Longest: 13, LastActiveDay: "2026-03-13", FreezeTokens: 2},
+			"2026-03-14",
+			Streak{Current: 14, Longest: 14, LastActiveDay: "2026-03-14", FreezeTokens: 2},
+			0,
+		},
+		{
+			"tokens cover the missed days",
+			Streak{Current: 4, Longest: 4, LastActiveDay: "2026-03-01", FreezeTokens: 2},
+			"2026-03-04",
+			Streak{Current: 5, Longest: 5, LastActiveDay: "2026-03-04"},
+			2,
+		},
+		{
+			"too few tokens restarts and keeps them",
+			Streak{Current: 4, Longest: 4, LastActiveDay: "2026-03-01", FreezeTokens: 1},
+			"2026-03-04",
+			Streak{Current: 1, Longest: 4, LastActiveDay: "2026-03-04", FreezeTokens: 1},
+			0,
+		},
+		{
+			"corrupt last day restarts",
+			Streak{Current: 4, Longest: 4, LastActiveDay: "yesterday"},
+			"2026-03-04",
+			Streak{Current: 1, Longest: 4, LastActiveDay: "2026-03-04"},
+			0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, spent := rules.RecordActivity(tt.streak, day(tt.day))
+			if got != tt.want || spent != tt.wantSpent {
+				t.Errorf("RecordActivity() = %+v, %d; want %+v, %d", got, spent, tt.want, tt.wantSpent)
+			}
+		})
+	}
+}
+
+func TestRecordActivityWithoutFreezes(t *testing.T) {
+	got, _ := StreakRules{}.RecordActivity(Streak{Current: 6, LastActiveDay: "2026-03-06"}, day("2026-03-07"))
+	if got.FreezeTokens != 0 {
+		t.Errorf("FreezeTokens = %d with freezes disabled, want 0", got.FreezeTokens)
+	}
+}
+
+func TestStreakAlive(t *testing.T) {
+	tests := []struct {
+		name   string
+		streak Streak
+		day    string
+		want   bool
+	}{
+		{"never active", Streak{}, "2026-03-02", false},
+		{"active today", Streak{LastActiveDay: "2026-03-02"}, "2026-03-02", true},
+		{"active yesterday", Streak{LastActiveDay: "2026-03-01"}, "2026-03-02", true},
+		{"missed a day", Streak{LastActiveDay: "2026-02-28"}, "2026-03-02", false},
+		{"missed a day with a token", Streak{LastActiveDay: "2026-02-28", FreezeTokens: 1}, "2026-03-02", true},
+		{"missed two days with one token", Streak{LastActiveDay: "2026-02-27", FreezeTokens: 1}, "2026-03-02", false},
+	}
+	for _, tt := range tests {
+		if got := tt.streak.Alive(day(tt.day)); got != tt.want {
+			t.Errorf("%s: Alive() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
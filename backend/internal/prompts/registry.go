@@ -0,0 +1,191 @@
+// Package prompts holds the versioned LLM prompt templates. Defaults are
+// embedded in the binary; a directory of .tmpl files can override them.
+//
+// Each template file is named <name>.tmpl, starts with a version header
+//
+//	{{/* version: v2 */}}
+//
+// and defines a "system" and a "user" block rendered with text/template.
+// Bump the version whenever a prompt changes so verdicts stay traceable.
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Prompt names
+const (
+	ArchitectChat      = "architect_chat"
+	ComplexityAnalysis = "complexity_analysis"
+	JudgeAudit         = "judge_audit"
+	JudgeCheckpoint    = "judge_checkpoint"
+	Hint               = "hint"
+	CodeReview         = "code_review"
+	ExplainQuiz        = "explain_quiz"
+	ExplainQuizGrade   = "explain_quiz_grade"
+	ProblemGeneration  = "problem_generation"
+)
+
+// required prompts must be present after loading
+var required = []string{ArchitectChat, ComplexityAnalysis, JudgeAudit, JudgeCheckpoint, Hint, CodeReview, ExplainQuiz, ExplainQuizGrade, ProblemGeneration}
+
+//go:embed templates/*.tmpl
+var embedded embed.FS
+
+var versionHeader = regexp.MustCompile(`^\s*\{\{/\*\s*version:\s*(\S+)\s*\*/\}\}`)
+
+// Prompt is one loaded template
+type Prompt struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Hash identifies the exact template text, catching edits without a version bump
+	Hash   string `json:"hash"`
+	Source string `json:"source"`
+	Text   string `json:"text"`
+
+	tmpl *template.Template
+}
+
+// ID is the version identifier recorded with every verdict (e.g. "judge_audit@v1")
+func (p *Prompt) ID() string {
+	return p.Name + "@" + p.Version
+}
+
+// Rendered is a prompt filled in for one call
+type Rendered struct {
+	System  string
+	User    string
+	Version string
+	// Fingerprint changes whenever the template text changes (for cache keys)
+	Fingerprint string
+}
+
+// Registry holds the active prompt for each name
+type Registry struct {
+	prompts map[string]*Prompt
+}
+
+// Load reads the embedded defaults, then overrides them with any .tmpl files
+// in dir (dir may be empty)
+func Load(dir string) (*Registry, error) {
+	r := &Registry{prompts: make(map[string]*Prompt)}
+
+	templates, err := fs.Sub(embedded, "templates")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedded prompts: %w", err)
+	}
+	if err := r.loadFS(templates, "embedded"); err != nil {
+		return nil, err
+	}
+
+	if dir != "" {
+		if err := r.loadFS(os.DirFS(dir), dir); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range required {
+		if _, ok := r.prompts[name]; !ok {
+			return nil, fmt.Errorf("prompt %s is missing", name)
+		}
+	}
+
+	return r, nil
+}
+
+// loadFS parses every .tmpl file in fsys, replacing prompts of the same name
+func (r *Registry) loadFS(fsys fs.FS, source string) error {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return fmt.Errorf("failed to list prompts in %s: %w", source, err)
+	}
+
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("failed to read prompt %s: %w", file, err)
+		}
+
+		prompt, err := parsePrompt(strings.TrimSuffix(file, ".tmpl"), string(content))
+		if err != nil {
+			return fmt.Errorf("invalid prompt %s in %s: %w", file, source, err)
+		}
+		prompt.Source = source
+		if source != "embedded" {
+			prompt.Source = filepath.Join(source, file)
+		}
+		r.prompts[prompt.Name] = prompt
+	}
+
+	return nil
+}
+
+func parsePrompt(name, text string) (*Prompt, error) {
+	match := versionHeader.FindStringSubmatch(text)
+	if match == nil {
+		return nil, fmt.Errorf("missing {{/* version: ... */}} header")
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, block := range []string{"system", "user"} {
+		if tmpl.Lookup(block) == nil {
+			return nil, fmt.Errorf("missing %q block", block)
+		}
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	return &Prompt{
+		Name:    name,
+		Version: match[1],
+		Hash:    hex.EncodeToString(sum[:])[:12],
+		Text:    text,
+		tmpl:    tmpl,
+	}, nil
+}
+
+// Render fills in the named prompt's system and user blocks
+func (r *Registry) Render(name string, data interface{}) (*Rendered, error) {
+	prompt, ok := r.prompts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown prompt %s", name)
+	}
+
+	var system, user bytes.Buffer
+	if err := prompt.tmpl.ExecuteTemplate(&system, "system", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s system prompt: %w", name, err)
+	}
+	if err := prompt.tmpl.ExecuteTemplate(&user, "user", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s user prompt: %w", name, err)
+	}
+
+	return &Rendered{
+		System:      strings.TrimSpace(system.String()),
+		User:        strings.TrimSpace(user.String()),
+		Version:     prompt.ID(),
+		Fingerprint: prompt.ID() + ":" + prompt.Hash,
+	}, nil
+}
+
+// List returns the active prompts sorted by name
+func (r *Registry) List() []Prompt {
+	list := make([]Prompt, 0, len(r.prompts))
+	for _, prompt := range r.prompts {
+		list = append(list, *prompt)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
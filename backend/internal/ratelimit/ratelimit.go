@@ -0,0 +1,87 @@
+// Package ratelimit implements the per-user token buckets that throttle the
+// AI routes. The HTTP middleware and the MySQL store both build on it.
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Rule is a token bucket: Burst requests at once, refilled at PerMinute
+type Rule struct {
+	PerMinute float64
+	Burst     int
+}
+
+// Store takes one token from the bucket identified by key
+type Store interface {
+	Take(key string, rule Rule) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// refillBucket returns the bucket's tokens after refilling for the elapsed time.
+// Instances sharing the MySQL store can disagree about the time, so a bucket
+// last touched "in the future" just doesn't refill.
+func refillBucket(tokens float64, elapsed time.Duration, rule Rule) float64 {
+	tokens += max(elapsed, 0).Minutes() * rule.PerMinute
+	return math.Min(tokens, float64(rule.Burst))
+}
+
+// TakeToken applies one request to a bucket and returns the new token count,
+// whether the request is allowed, and how long until a token is available
+func TakeToken(tokens float64, elapsed time.Duration, rule Rule) (float64, bool, time.Duration) {
+	tokens = refillBucket(tokens, elapsed, rule)
+	if tokens >= 1 {
+		return tokens - 1, true, 0
+	}
+	if rule.PerMinute <= 0 {
+		return tokens, false, time.Hour
+	}
+	wait := time.Duration((1 - tokens) / rule.PerMinute * float64(time.Minute))
+	return tokens, false, wait
+}
+
+type memoryBucket struct {
+	tokens   float64
+	lastSeen time.Time
+}
+
+// MemoryStore keeps buckets in process memory (single instance only)
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	takes   int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket)}
+}
+
+// Take implements Store
+func (s *MemoryStore) Take(key string, rule Rule) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(rule.Burst), lastSeen: now}
+		s.buckets[key] = bucket
+	}
+
+	tokens, allowed, retryAfter := TakeToken(bucket.tokens, now.Sub(bucket.lastSeen), rule)
+	bucket.tokens = tokens
+	bucket.lastSeen = now
+
+	// Periodically drop idle buckets so memory doesn't grow with every user seen
+	s.takes++
+	if s.takes%1000 == 0 {
+		for k, b := range s.buckets {
+			if now.Sub(b.lastSeen) > 30*time.Minute {
+				delete(s.buckets, k)
+			}
+		}
+	}
+
+	return allowed, retryAfter, nil
+}
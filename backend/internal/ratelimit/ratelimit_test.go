@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTakeToken(t *testing.T) {
+	rule := Rule{PerMinute: 6, Burst: 3}
+
+	tests := []struct {
+		name       string
+		tokens     float64
+		elapsed    time.Duration
+		rule       Rule
+		wantTokens float64
+		wantOK     bool
+		wantWait   time.Duration
+	}{
+		{"full bucket", 3, 0, rule, 2, true, 0},
+		{"refill at capacity stays at burst", 3, time.Hour, rule, 2, true, 0},
+		{"refill tops up to burst", 0, 10 * time.Minute, rule, 2, true, 0},
+		{"last token", 1, 0, rule, 0, true, 0},
+		{"empty bucket waits for one token", 0, 0, rule, 0, false, 10 * time.Second},
+		{"partial token waits for the rest", 0.5, 0, rule, 0.5, false, 5 * time.Second},
+		{"refill earns exactly one token", 0, 10 * time.Second, rule, 0, true, 0},
+		{"clock going backwards doesn't drain", 2, -time.Minute, rule, 1, true, 0},
+		{"no refill rule", 0, time.Hour, Rule{PerMinute: 0, Burst: 1}, 0, false, time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, ok, wait := TakeToken(tt.tokens, tt.elapsed, tt.rule)
+			if ok != tt.wantOK {
+				t.Errorf("allowed = %v, want %v", ok, tt.wantOK)
+			}
+			if diff := tokens - tt.wantTokens; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("tokens = %v, want %v", tokens, tt.wantTokens)
+			}
+			if diff := wait - tt.wantWait; diff > time.Millisecond || diff < -time.Millisecond {
+				t.Errorf("retry after = %v, want %v", wait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreBurstThenThrottle(t *testing.T) {
+	store := NewMemoryStore()
+	rule := Rule{PerMinute: 1, Burst: 2}
+
+	for i := 0; i < rule.Burst; i++ {
+		if ok, _, err := store.Take("user:ai_chat", rule); err != nil || !ok {
+			t.Fatalf("request %d: allowed = %v, err = %v", i+1, ok, err)
+		}
+	}
+	ok, wait, err := store.Take("user:ai_chat", rule)
+	if err != nil || ok {
+		t.Fatalf("request past burst: allowed = %v, err = %v", ok, err)
+	}
+	if wait <= 0 || wait > time.Minute {
+		t.Errorf("retry after = %v, want within a minute", wait)
+	}
+
+	// Buckets are per key
+	if ok, _, _ := store.Take("user:ai_judge", rule); !ok {
+		t.Error("another route's bucket was throttled")
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+type AchievementRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementRepository(db *sql.DB) *AchievementRepository {
+	return &AchievementRepository{db: db}
+}
+
+// Award records a badge. It reports false if the user already has it.
+func (r *AchievementRepository) Award(achievement *models.UserAchievement) (bool, error) {
+	query := `
+		INSERT IGNORE INTO user_achievements (user_uid, achievement_key, subject_type, subject_id, awarded_at)
+		VALUES (?, ?, NULLIF(?, ''), NULLIF(?, 0), ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, achievement.UserUID, achievement.AchievementKey, achievement.SubjectType, achievement.SubjectID, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to award achievement: %w", err)
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to award achievement: %w", err)
+	}
+	achievement.AwardedAt = now
+
+	return inserted > 0, nil
+}
+
+// ListByUser returns the user's badges, oldest first
+func (r *AchievementRepository) ListByUser(firebaseUID string) ([]models.UserAchievement, error) {
+	query := `
+		SELECT user_uid, achievement_key, COALESCE(subject_type, ''), COALESCE(subject_id, 0), awarded_at
+		FROM user_achievements
+		WHERE user_uid = ?
+		ORDER BY awarded_at, achievement_key
+	`
+
+	rows, err := r.db.Query(query, firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+	defer rows.Close()
+
+	achievements := []models.UserAchievement{}
+	for rows.Next() {
+		var a models.UserAchievement
+		if err := rows.Scan(&a.UserUID, &a.AchievementKey, &a.SubjectType, &a.SubjectID, &a.AwardedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement: %w", err)
+		}
+		achievements = append(achievements, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+
+	return achievements, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AICacheRepository persists cached AI responses so they survive restarts
+// and are shared across instances
+type AICacheRepository struct {
+	db *sql.DB
+}
+
+func NewAICacheRepository(db *sql.DB) *AICacheRepository {
+	return &AICacheRepository{db: db}
+}
+
+// Get returns an unexpired cached response
+func (r *AICacheRepository) Get(key string) (string, time.Time, bool, error) {
+	query := `
+		SELECT response, expires_at
+		FROM ai_response_cache
+		WHERE cache_key = ? AND expires_at > ?
+	`
+
+	var response string
+	var expiresAt time.Time
+	err := r.db.QueryRow(query, key, time.Now()).Scan(&response, &expiresAt)
+	if err == sql.ErrNoRows {
+		return "", time.Time{}, false, nil
+	}
+	if err != nil {
+		return "", time.Time{}, false, fmt.Errorf("failed to get cached response: %w", err)
+	}
+
+	return response, expiresAt, true, nil
+}
+
+// Set stores or refreshes a cached response
+func (r *AICacheRepository) Set(key, response string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO ai_response_cache (cache_key, response, expires_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE response = VALUES(response), expires_at = VALUES(expires_at)
+	`
+
+	if _, err := r.db.Exec(query, key, response, expiresAt); err != nil {
+		return fmt.Errorf("failed to cache response: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes expired entries
+func (r *AICacheRepository) DeleteExpired() error {
+	if _, err := r.db.Exec(`DELETE FROM ai_response_cache WHERE expires_at <= ?`, time.Now()); err != nil {
+		return fmt.Errorf("failed to delete expired cache entries: %w", err)
+	}
+	return nil
+}
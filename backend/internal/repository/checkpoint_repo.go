@@ -2,12 +2,25 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/yourusername/skilltree/internal/models"
 )
 
+var (
+	// ErrCheckpointCooldown is returned when an attempt is made before next_attempt_at
+	ErrCheckpointCooldown = errors.New("checkpoint is cooling down")
+	// ErrDailyAttemptCapReached is returned when the tier's daily attempt cap is used up
+	ErrDailyAttemptCapReached = errors.New("daily checkpoint attempt cap reached")
+	// ErrSessionNotFound is returned when a session doesn't exist or belongs to another user
+	ErrSessionNotFound = errors.New("checkpoint session not found")
+	// ErrSessionClosed is returned when a session was already submitted or expired
+	ErrSessionClosed = errors.New("checkpoint session is closed")
+)
+
 type CheckpointRepository struct {
 	db *sql.DB
 }
@@ -19,8 +32,8 @@ func NewCheckpointRepository(db *sql.DB) *CheckpointRepository {
 // GetAllByFirebaseUID retrieves all checkpoint records for a user
 func (r *CheckpointRepository) GetAllByFirebaseUID(firebaseUID string) ([]models.TierCheckpoint, error) {
 	query := `
-		SELECT tc.id, tc.user_uid, tc.tier_number, tc.is_passed, tc.attempts,
-		       tc.last_attempt_at, tc.passed_at, tc.submitted_code, tc.created_at, tc.updated_at
+		SELECT tc.id, tc.user_uid, tc.tier_number, tc.is_passed, tc.attempts, tc.consecutive_failures,
+		       tc.last_attempt_at, tc.next_attempt_at, tc.passed_at, COALESCE(tc.submitted_code, ''), tc.created_at, tc.updated_at
 		FROM tier_checkpoints tc
 		WHERE tc.user_uid = ?
 		ORDER BY tc.tier_number ASC
@@ -41,7 +54,9 @@ func (r *CheckpointRepository) GetAllByFirebaseUID(firebaseUID string) ([]models
 			&checkpoint.TierNumber,
 			&checkpoint.IsPassed,
 			&checkpoint.Attempts,
+			&checkpoint.ConsecutiveFailures,
 			&checkpoint.LastAttemptAt,
+			&checkpoint.NextAttemptAt,
 			&checkpoint.PassedAt,
 			&checkpoint.SubmittedCode,
 			&checkpoint.CreatedAt,
@@ -59,8 +74,8 @@ func (r *CheckpointRepository) GetAllByFirebaseUID(firebaseUID string) ([]models
 // GetByFirebaseUIDAndTier retrieves a specific tier checkpoint for a user
 func (r *CheckpointRepository) GetByFirebaseUIDAndTier(firebaseUID string, tier int) (*models.TierCheckpoint, error) {
 	query := `
-		SELECT tc.id, tc.user_uid, tc.tier_number, tc.is_passed, tc.attempts,
-		       tc.last_attempt_at, tc.passed_at, tc.submitted_code, tc.created_at, tc.updated_at
+		SELECT tc.id, tc.user_uid, tc.tier_number, tc.is_passed, tc.attempts, tc.consecutive_failures,
+		       tc.last_attempt_at, tc.next_attempt_at, tc.passed_at, COALESCE(tc.submitted_code, ''), tc.created_at, tc.updated_at
 		FROM tier_checkpoints tc
 		WHERE tc.user_uid = ? AND tc.tier_number = ?
 	`
@@ -72,7 +87,9 @@ func (r *CheckpointRepository) GetByFirebaseUIDAndTier(firebaseUID string, tier
 		&checkpoint.TierNumber,
 		&checkpoint.IsPassed,
 		&checkpoint.Attempts,
+		&checkpoint.ConsecutiveFailures,
 		&checkpoint.LastAttemptAt,
+		&checkpoint.NextAttemptAt,
 		&checkpoint.PassedAt,
 		&checkpoint.SubmittedCode,
 		&checkpoint.CreatedAt,
@@ -89,31 +106,372 @@ func (r *CheckpointRepository) GetByFirebaseUIDAndTier(firebaseUID string, tier
 	return &checkpoint, nil
 }
 
-// RecordAttempt increments attempt counter and updates timestamp
-func (r *CheckpointRepository) RecordAttempt(firebaseUID string, tier int, code string) error {
-	query := `
+// RecordAttempt increments the attempt counter, stores the submission and
+// closes its session with the given status. The session must still be
+// active, so a session can only ever produce one attempt. The attempt
+// number is taken from the counter with the checkpoint row locked, so
+// concurrent submissions never share one.
+func (r *CheckpointRepository) RecordAttempt(attempt *models.CheckpointAttempt, sessionStatus string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE checkpoint_sessions
+		SET status = ?, closed_at = ?
+		WHERE id = ? AND user_uid = ? AND status = ?
+	`, sessionStatus, now, attempt.SessionID, attempt.UserUID, models.SessionActive)
+	if err != nil {
+		return fmt.Errorf("failed to close session: %w", err)
+	}
+	if closed, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to close session: %w", err)
+	} else if closed == 0 {
+		return ErrSessionClosed
+	}
+
+	var attempts int
+	err = tx.QueryRow(`
+		SELECT attempts
+		FROM tier_checkpoints
+		WHERE user_uid = ? AND tier_number = ?
+		FOR UPDATE
+	`, attempt.UserUID, attempt.TierNumber).Scan(&attempts)
+	if err != nil {
+		return fmt.Errorf("failed to lock checkpoint: %w", err)
+	}
+	attempt.AttemptNumber = attempts + 1
+
+	_, err = tx.Exec(`
 		UPDATE tier_checkpoints
-		SET attempts = attempts + 1,
+		SET attempts = ?,
 		    last_attempt_at = ?,
 		    submitted_code = ?
 		WHERE user_uid = ? AND tier_number = ?
+	`, attempt.AttemptNumber, now, attempt.SubmittedCode, attempt.UserUID, attempt.TierNumber)
+	if err != nil {
+		return fmt.Errorf("failed to record attempt: %w", err)
+	}
+
+	testResults, err := marshalTestResults(attempt.TestResults)
+	if err != nil {
+		return err
+	}
+
+	var telemetry []byte
+	if attempt.Telemetry != nil {
+		if telemetry, err = json.Marshal(attempt.Telemetry); err != nil {
+			return fmt.Errorf("failed to marshal telemetry: %w", err)
+		}
+	}
+
+	result, err = tx.Exec(`
+		INSERT INTO checkpoint_attempts (user_uid, session_id, tier_number, attempt_number, problem_id, submitted_code, test_results, telemetry, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, attempt.UserUID, attempt.SessionID, attempt.TierNumber, attempt.AttemptNumber, attempt.ProblemID, attempt.SubmittedCode, testResults, telemetry, now)
+	if err != nil {
+		return fmt.Errorf("failed to insert attempt: %w", err)
+	}
+
+	attempt.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get attempt id: %w", err)
+	}
+	attempt.CreatedAt = now
+
+	_, err = tx.Exec(`
+		UPDATE checkpoint_sessions SET attempt_id = ? WHERE id = ?
+	`, attempt.ID, attempt.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to link attempt to session: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetAttempt retrieves a single checkpoint attempt with its judgement
+func (r *CheckpointRepository) GetAttempt(attemptID int64) (*models.CheckpointAttempt, error) {
+	query := `
+		SELECT id, user_uid, COALESCE(session_id, 0), tier_number, attempt_number, COALESCE(problem_id, ''),
+		       COALESCE(submitted_code, ''), COALESCE(verdict, ''), COALESCE(feedback, ''),
+		       judge_votes, COALESCE(prompt_version, ''), test_results, telemetry,
+		       ai_risk_score, ai_risk_signals, created_at, judged_at
+		FROM checkpoint_attempts
+		WHERE id = ?
 	`
 
-	now := time.Now()
-	_, err := r.db.Exec(query, now, code, firebaseUID, tier)
+	var attempt models.CheckpointAttempt
+	var votes, testResults, telemetry, riskSignals []byte
+	var riskScore sql.NullInt64
+	err := r.db.QueryRow(query, attemptID).Scan(
+		&attempt.ID,
+		&attempt.UserUID,
+		&attempt.SessionID,
+		&attempt.TierNumber,
+		&attempt.AttemptNumber,
+		&attempt.ProblemID,
+		&attempt.SubmittedCode,
+		&attempt.Verdict,
+		&attempt.Feedback,
+		&votes,
+		&attempt.PromptVersion,
+		&testResults,
+		&telemetry,
+		&riskScore,
+		&riskSignals,
+		&attempt.CreatedAt,
+		&attempt.JudgedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to record attempt: %w", err)
+		return nil, fmt.Errorf("failed to get attempt: %w", err)
+	}
+
+	if len(votes) > 0 {
+		if err := json.Unmarshal(votes, &attempt.JudgeVotes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal judge_votes: %w", err)
+		}
+	}
+	if err := unmarshalTestResults(testResults, &attempt.TestResults); err != nil {
+		return nil, err
+	}
+	if len(telemetry) > 0 {
+		attempt.Telemetry = &models.EditorTelemetry{}
+		if err := json.Unmarshal(telemetry, attempt.Telemetry); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal telemetry: %w", err)
+		}
+	}
+	if riskScore.Valid {
+		attempt.AIRisk = &models.AIRiskAssessment{Score: int(riskScore.Int64)}
+		if len(riskSignals) > 0 {
+			if err := json.Unmarshal(riskSignals, &attempt.AIRisk.Signals); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal ai_risk_signals: %w", err)
+			}
+		}
+	}
+
+	return &attempt, nil
+}
+
+// RecordAIRisk stores the AI-generation risk assessment of an attempt
+func (r *CheckpointRepository) RecordAIRisk(attemptID int64, risk *models.AIRiskAssessment) error {
+	signals, err := json.Marshal(risk.Signals)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ai risk signals: %w", err)
+	}
+
+	query := `
+		UPDATE checkpoint_attempts
+		SET ai_risk_score = ?, ai_risk_signals = ?
+		WHERE id = ?
+	`
+
+	if _, err := r.db.Exec(query, risk.Score, signals, attemptID); err != nil {
+		return fmt.Errorf("failed to record ai risk: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecentCode returns the code of a user's most recent attempts before
+// the given one, newest first
+func (r *CheckpointRepository) ListRecentCode(firebaseUID string, beforeAttemptID int64, limit int) ([]string, error) {
+	query := `
+		SELECT submitted_code
+		FROM checkpoint_attempts
+		WHERE user_uid = ? AND id < ? AND submitted_code IS NOT NULL AND submitted_code <> ''
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, firebaseUID, beforeAttemptID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent attempts: %w", err)
+	}
+	defer rows.Close()
+
+	var code []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan attempt code: %w", err)
+		}
+		code = append(code, c)
+	}
+
+	return code, rows.Err()
+}
+
+// RecordVerdict stores the judge verdict on an attempt without touching the
+// failure streak (used for judge errors that are not the learner's fault)
+func (r *CheckpointRepository) RecordVerdict(attemptID int64, verdict string) error {
+	query := `
+		UPDATE checkpoint_attempts
+		SET verdict = ?, judged_at = ?
+		WHERE id = ?
+	`
+
+	_, err := r.db.Exec(query, verdict, time.Now(), attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to record verdict: %w", err)
+	}
+
+	return nil
+}
+
+// RecordJudgement stores the judge feedback, the individual votes behind a
+// verdict and the prompt version they were cast with
+func (r *CheckpointRepository) RecordJudgement(attemptID int64, feedback string, votes []models.JudgeVote, promptVersion string) error {
+	votesJSON, err := json.Marshal(votes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal judge votes: %w", err)
+	}
+
+	query := `
+		UPDATE checkpoint_attempts
+		SET feedback = ?, judge_votes = ?, prompt_version = NULLIF(?, '')
+		WHERE id = ?
+	`
+
+	_, err = r.db.Exec(query, feedback, votesJSON, promptVersion, attemptID)
+	if err != nil {
+		return fmt.Errorf("failed to record judgement: %w", err)
 	}
 
 	return nil
 }
 
+// RecordFailure stores a failing verdict, bumps the consecutive failure count
+// and sets next_attempt_at from the cooldown returned for the new count
+func (r *CheckpointRepository) RecordFailure(attemptID int64, firebaseUID string, tier int, verdict string, cooldownFor func(consecutiveFailures int) time.Duration) (*time.Time, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var failures int
+	err = tx.QueryRow(`
+		SELECT consecutive_failures
+		FROM tier_checkpoints
+		WHERE user_uid = ? AND tier_number = ?
+		FOR UPDATE
+	`, firebaseUID, tier).Scan(&failures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock checkpoint: %w", err)
+	}
+
+	now := time.Now()
+	failures++
+
+	var nextAttemptAt *time.Time
+	if cooldown := cooldownFor(failures); cooldown > 0 {
+		next := now.Add(cooldown)
+		nextAttemptAt = &next
+	}
+
+	_, err = tx.Exec(`
+		UPDATE tier_checkpoints
+		SET consecutive_failures = ?,
+		    next_attempt_at = ?
+		WHERE user_uid = ? AND tier_number = ?
+	`, failures, nextAttemptAt, firebaseUID, tier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update failure streak: %w", err)
+	}
+
+	_, err = tx.Exec(`
+		UPDATE checkpoint_attempts
+		SET verdict = ?, judged_at = ?
+		WHERE id = ?
+	`, verdict, now, attemptID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record verdict: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nextAttemptAt, nil
+}
+
+// countedSessions selects the sessions that use up the daily attempt cap:
+// every session becomes exactly one attempt, so sessions are counted to
+// include attempts still in progress, except those the judge failed on
+const countedSessions = `
+	FROM checkpoint_sessions s
+	LEFT JOIN checkpoint_attempts a ON a.id = s.attempt_id
+	WHERE s.user_uid = ? AND s.started_at >= ? AND (a.verdict IS NULL OR a.verdict <> 'ERROR')
+`
+
+// CountAttemptsSince returns the number of attempts per tier started since
+// the given time that count toward the daily cap
+func (r *CheckpointRepository) CountAttemptsSince(firebaseUID string, since time.Time) (map[int]int, error) {
+	query := `SELECT s.tier_number, COUNT(*)` + countedSessions + `GROUP BY s.tier_number`
+
+	rows, err := r.db.Query(query, firebaseUID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attempts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var tier, count int
+		if err := rows.Scan(&tier, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan attempt count: %w", err)
+		}
+		counts[tier] = count
+	}
+
+	return counts, nil
+}
+
+// PassedByTier returns whether each of the user's tier checkpoints is
+// passed, for the tiers they have a checkpoint record for
+func (r *CheckpointRepository) PassedByTier(firebaseUID string) (map[int]bool, error) {
+	query := `SELECT tier_number, is_passed FROM tier_checkpoints WHERE user_uid = ?`
+
+	rows, err := r.db.Query(query, firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint results: %w", err)
+	}
+	defer rows.Close()
+
+	passed := make(map[int]bool)
+	for rows.Next() {
+		var tier int
+		var isPassed bool
+		if err := rows.Scan(&tier, &isPassed); err != nil {
+			return nil, fmt.Errorf("failed to scan checkpoint result: %w", err)
+		}
+		passed[tier] = isPassed
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint results: %w", err)
+	}
+
+	return passed, nil
+}
+
 // MarkAsPassed updates checkpoint to passed status
 func (r *CheckpointRepository) MarkAsPassed(firebaseUID string, tier int) error {
 	query := `
 		UPDATE tier_checkpoints
 		SET is_passed = TRUE,
-		    passed_at = ?
+		    passed_at = ?,
+		    consecutive_failures = 0,
+		    next_attempt_at = NULL
 		WHERE user_uid = ? AND tier_number = ?
 	`
 
@@ -126,6 +484,41 @@ func (r *CheckpointRepository) MarkAsPassed(firebaseUID string, tier int) error
 	return nil
 }
 
+// GetVariantStats aggregates judged attempts per tier and problem variant
+func (r *CheckpointRepository) GetVariantStats() ([]models.CheckpointVariantStats, error) {
+	query := `
+		SELECT tier_number, problem_id,
+		       COUNT(*),
+		       COALESCE(SUM(verdict = 'ADVANCE'), 0),
+		       COALESCE(SUM(verdict = 'REPEAT'), 0),
+		       COUNT(DISTINCT user_uid)
+		FROM checkpoint_attempts
+		WHERE problem_id IS NOT NULL
+		GROUP BY tier_number, problem_id
+		ORDER BY tier_number ASC, problem_id ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []models.CheckpointVariantStats{}
+	for rows.Next() {
+		var s models.CheckpointVariantStats
+		if err := rows.Scan(&s.TierNumber, &s.ProblemID, &s.Attempts, &s.Passes, &s.Failures, &s.UniqueUsers); err != nil {
+			return nil, fmt.Errorf("failed to scan variant stats: %w", err)
+		}
+		if judged := s.Passes + s.Failures; judged > 0 {
+			s.PassRate = float64(s.Passes) / float64(judged)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats, nil
+}
+
 // InitializeCheckpoints creates all 7 checkpoint records for a new user
 func (r *CheckpointRepository) InitializeCheckpoints(firebaseUID string) error {
 	// Create 7 checkpoint records (tier 0-6)
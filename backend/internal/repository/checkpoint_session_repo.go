@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+const sessionColumns = `
+	id, user_uid, tier_number, attempt_number, problem_id, status,
+	started_at, deadline_at, draft_code, draft_saved_at, attempt_id, closed_at
+`
+
+// AttemptLimits are the per-tier limits checked when a session is opened
+type AttemptLimits struct {
+	DailyCap  int // 0 means unlimited
+	DayStart  time.Time
+	TimeLimit time.Duration
+}
+
+// CreateSession opens a timed session for the next checkpoint attempt. The
+// cooldown, daily cap and single-active-session checks run in the same
+// transaction as the insert with the checkpoint row locked, so concurrent
+// starts cannot slip past the limits. assignProblem picks the variant for the
+// attempt number being started. The returned flag is false when an already
+// running session is returned instead.
+func (r *CheckpointRepository) CreateSession(firebaseUID string, tier int, limits AttemptLimits, assignProblem func(attemptNumber int) string) (*models.CheckpointSession, bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var attempts int
+	var nextAttemptAt sql.NullTime
+	err = tx.QueryRow(`
+		SELECT attempts, next_attempt_at
+		FROM tier_checkpoints
+		WHERE user_uid = ? AND tier_number = ?
+		FOR UPDATE
+	`, firebaseUID, tier).Scan(&attempts, &nextAttemptAt)
+	if err == sql.ErrNoRows {
+		return nil, false, fmt.Errorf("checkpoint not found for tier %d", tier)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to lock checkpoint: %w", err)
+	}
+
+	now := time.Now()
+	if nextAttemptAt.Valid && nextAttemptAt.Time.After(now) {
+		return nil, false, ErrCheckpointCooldown
+	}
+
+	// Another request may have opened a session since the caller looked
+	existing, err := scanSession(tx.QueryRow(`
+		SELECT `+sessionColumns+`
+		FROM checkpoint_sessions
+		WHERE user_uid = ? AND tier_number = ? AND status = ?
+		LIMIT 1
+	`, firebaseUID, tier, models.SessionActive))
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		return existing, false, nil
+	}
+
+	if limits.DailyCap > 0 {
+		var today int
+		err = tx.QueryRow(`SELECT COUNT(*)`+countedSessions+`AND s.tier_number = ?`,
+			firebaseUID, limits.DayStart, tier).Scan(&today)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to count attempts: %w", err)
+		}
+		if today >= limits.DailyCap {
+			return nil, false, ErrDailyAttemptCapReached
+		}
+	}
+
+	session := &models.CheckpointSession{
+		UserUID:       firebaseUID,
+		TierNumber:    tier,
+		AttemptNumber: attempts + 1,
+		ProblemID:     assignProblem(attempts + 1),
+		Status:        models.SessionActive,
+		StartedAt:     now,
+		DeadlineAt:    now.Add(limits.TimeLimit),
+	}
+
+	result, err := tx.Exec(`
+		INSERT INTO checkpoint_sessions (user_uid, tier_number, attempt_number, problem_id, status, started_at, deadline_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, session.UserUID, session.TierNumber, session.AttemptNumber, session.ProblemID, session.Status, session.StartedAt, session.DeadlineAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	session.ID, err = result.LastInsertId()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get session id: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return session, true, nil
+}
+
+// GetSession retrieves a session owned by the given user
+func (r *CheckpointRepository) GetSession(firebaseUID string, sessionID int64) (*models.CheckpointSession, error) {
+	query := `SELECT ` + sessionColumns + ` FROM checkpoint_sessions WHERE id = ? AND user_uid = ?`
+
+	session, err := scanSession(r.db.QueryRow(query, sessionID, firebaseUID))
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, ErrSessionNotFound
+	}
+
+	return session, nil
+}
+
+// GetActiveSessions retrieves all sessions the user still has open
+func (r *CheckpointRepository) GetActiveSessions(firebaseUID string) ([]models.CheckpointSession, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM checkpoint_sessions
+		WHERE user_uid = ? AND status = ?
+		ORDER BY tier_number ASC
+	`
+
+	rows, err := r.db.Query(query, firebaseUID, models.SessionActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []models.CheckpointSession
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, nil
+}
+
+// SaveDraft autosaves code on a session that is still open
+func (r *CheckpointRepository) SaveDraft(firebaseUID string, sessionID int64, code string) (time.Time, error) {
+	query := `
+		UPDATE checkpoint_sessions
+		SET draft_code = ?, draft_saved_at = ?
+		WHERE id = ? AND user_uid = ? AND status = ? AND deadline_at > ?
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, code, now, sessionID, firebaseUID, models.SessionActive, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to save draft: %w", err)
+	}
+
+	saved, err := result.RowsAffected()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to save draft: %w", err)
+	}
+	if saved == 0 {
+		if _, err := r.GetSession(firebaseUID, sessionID); err != nil {
+			return time.Time{}, err
+		}
+		return time.Time{}, ErrSessionClosed
+	}
+
+	return now, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSession scans a single session row, returning nil when there is none
+func scanSession(row rowScanner) (*models.CheckpointSession, error) {
+	var session models.CheckpointSession
+	var draftCode sql.NullString
+	var attemptID sql.NullInt64
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserUID,
+		&session.TierNumber,
+		&session.AttemptNumber,
+		&session.ProblemID,
+		&session.Status,
+		&session.StartedAt,
+		&session.DeadlineAt,
+		&draftCode,
+		&session.DraftSavedAt,
+		&attemptID,
+		&session.ClosedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan session: %w", err)
+	}
+
+	session.DraftCode = draftCode.String
+	session.AttemptID = attemptID.Int64
+
+	return &session, nil
+}
@@ -0,0 +1,396 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+type CohortRepository struct {
+	db *sql.DB
+}
+
+func NewCohortRepository(db *sql.DB) *CohortRepository {
+	return &CohortRepository{db: db}
+}
+
+// Create stores a cohort and makes its creator an instructor
+func (r *CohortRepository) Create(cohort *models.Cohort, creatorUID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		INSERT INTO cohorts (name, invite_code, instructor_code, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, cohort.Name, cohort.InviteCode, cohort.InstructorCode, creatorUID, now)
+	if err != nil {
+		return fmt.Errorf("failed to create cohort: %w", err)
+	}
+	cohort.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get cohort id: %w", err)
+	}
+	cohort.CreatedAt = now
+
+	_, err = tx.Exec(`
+		INSERT INTO cohort_members (cohort_id, user_uid, role, joined_at)
+		VALUES (?, ?, ?, ?)
+	`, cohort.ID, creatorUID, models.CohortRoleInstructor, now)
+	if err != nil {
+		return fmt.Errorf("failed to add cohort instructor: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	cohort.Role = models.CohortRoleInstructor
+
+	return nil
+}
+
+const cohortColumns = `
+	c.id, c.name, c.invite_code, c.instructor_code, COALESCE(c.curriculum_key, ''), c.created_at,
+	(SELECT COUNT(*) FROM cohort_members l WHERE l.cohort_id = c.id AND l.role = 'learner')
+`
+
+// scanCohort scans cohortColumns into c, then any extra columns
+func scanCohort(row rowScanner, c *models.Cohort, extra ...interface{}) error {
+	return row.Scan(append([]interface{}{&c.ID, &c.Name, &c.InviteCode, &c.InstructorCode, &c.Curriculum, &c.CreatedAt, &c.LearnerCount}, extra...)...)
+}
+
+// GetForMember returns a cohort with the user's role in it, or nil if it
+// doesn't exist or the user isn't a member
+func (r *CohortRepository) GetForMember(cohortID int64, firebaseUID string) (*models.Cohort, error) {
+	query := `SELECT ` + cohortColumns + `, m.role
+		FROM cohorts c
+		JOIN cohort_members m ON m.cohort_id = c.id AND m.user_uid = ?
+		WHERE c.id = ?
+	`
+
+	var c models.Cohort
+	err := scanCohort(r.db.QueryRow(query, firebaseUID, cohortID), &c, &c.Role)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cohort: %w", err)
+	}
+
+	return &c, nil
+}
+
+// GetByCode finds the cohort an invite code belongs to and the role it
+// grants, or nil if no cohort uses the code
+func (r *CohortRepository) GetByCode(code string) (*models.Cohort, string, error) {
+	query := `SELECT ` + cohortColumns + `
+		FROM cohorts c
+		WHERE c.invite_code = ? OR c.instructor_code = ?
+	`
+
+	var c models.Cohort
+	err := scanCohort(r.db.QueryRow(query, code, code), &c)
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get cohort by code: %w", err)
+	}
+
+	role := models.CohortRoleLearner
+	if c.InstructorCode == code {
+		role = models.CohortRoleInstructor
+	}
+	return &c, role, nil
+}
+
+// ListForUser returns the cohorts the user belongs to, newest first
+func (r *CohortRepository) ListForUser(firebaseUID string) ([]models.Cohort, error) {
+	query := `SELECT ` + cohortColumns + `, m.role
+		FROM cohorts c
+		JOIN cohort_members m ON m.cohort_id = c.id
+		WHERE m.user_uid = ?
+		ORDER BY c.created_at DESC, c.id DESC
+	`
+
+	rows, err := r.db.Query(query, firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cohorts: %w", err)
+	}
+	defer rows.Close()
+
+	cohorts := []models.Cohort{}
+	for rows.Next() {
+		var c models.Cohort
+		if err := scanCohort(rows, &c, &c.Role); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort: %w", err)
+		}
+		cohorts = append(cohorts, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cohorts: %w", err)
+	}
+
+	return cohorts, nil
+}
+
+// AddMember adds the user to a cohort. Joining again with an instructor
+// code promotes a learner; a learner code never demotes an instructor.
+func (r *CohortRepository) AddMember(cohortID int64, firebaseUID, role string) error {
+	query := `
+		INSERT INTO cohort_members (cohort_id, user_uid, role, joined_at)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE role = IF(role = 'instructor', role, VALUES(role))
+	`
+
+	if _, err := r.db.Exec(query, cohortID, firebaseUID, role, time.Now()); err != nil {
+		return fmt.Errorf("failed to add cohort member: %w", err)
+	}
+
+	return nil
+}
+
+// SetCurriculum assigns a curriculum to a cohort; "" removes it
+func (r *CohortRepository) SetCurriculum(cohortID int64, curriculumKey string) error {
+	query := `UPDATE cohorts SET curriculum_key = NULLIF(?, '') WHERE id = ?`
+
+	if _, err := r.db.Exec(query, curriculumKey, cohortID); err != nil {
+		return fmt.Errorf("failed to set cohort curriculum: %w", err)
+	}
+
+	return nil
+}
+
+// LearnerCurriculum returns the curriculum of the cohort the user most
+// recently joined as a learner among those with one, or "" if none has
+func (r *CohortRepository) LearnerCurriculum(firebaseUID string) (string, int64, error) {
+	query := `
+		SELECT c.curriculum_key, c.id
+		FROM cohort_members m
+		JOIN cohorts c ON c.id = m.cohort_id
+		WHERE m.user_uid = ? AND m.role = 'learner' AND c.curriculum_key IS NOT NULL
+		ORDER BY m.joined_at DESC, c.id DESC
+		LIMIT 1
+	`
+
+	var key string
+	var cohortID int64
+	err := r.db.QueryRow(query, firebaseUID).Scan(&key, &cohortID)
+	if err == sql.ErrNoRows {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get cohort curriculum: %w", err)
+	}
+
+	return key, cohortID, nil
+}
+
+// ListMembers returns the members of a cohort, instructors first. If role
+// is set only members with that role are returned.
+func (r *CohortRepository) ListMembers(cohortID int64, role string) ([]models.CohortMember, error) {
+	query := `
+		SELECT m.user_uid, COALESCE(NULLIF(u.name, ''), 'Anonymous'), COALESCE(u.email, ''), m.role, m.joined_at
+		FROM cohort_members m
+		JOIN users u ON u.uid = m.user_uid
+		WHERE m.cohort_id = ? AND (? = '' OR m.role = ?)
+		ORDER BY m.role = 'learner', u.name, m.user_uid
+	`
+
+	rows, err := r.db.Query(query, cohortID, role, role)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cohort members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.CohortMember{}
+	for rows.Next() {
+		var m models.CohortMember
+		if err := rows.Scan(&m.UserUID, &m.DisplayName, &m.Email, &m.Role, &m.JoinedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort member: %w", err)
+		}
+		members = append(members, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cohort members: %w", err)
+	}
+
+	return members, nil
+}
+
+// ListLearnerMastery returns every mastery record of the cohort's learners
+func (r *CohortRepository) ListLearnerMastery(cohortID int64) ([]models.UserMastery, error) {
+	query := `
+		SELECT um.firebase_uid, um.topic_key, um.confidence, um.solved_problems
+		FROM user_mastery um
+		JOIN cohort_members m ON m.user_uid = um.firebase_uid
+		WHERE m.cohort_id = ? AND m.role = 'learner'
+	`
+
+	rows, err := r.db.Query(query, cohortID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cohort mastery: %w", err)
+	}
+	defer rows.Close()
+
+	var masteries []models.UserMastery
+	for rows.Next() {
+		var m models.UserMastery
+		var solvedJSON []byte
+		if err := rows.Scan(&m.FirebaseUID, &m.TopicKey, &m.Confidence, &solvedJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort mastery: %w", err)
+		}
+		if err := json.Unmarshal(solvedJSON, &m.SolvedProblems); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal solved_problems: %w", err)
+		}
+		masteries = append(masteries, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cohort mastery: %w", err)
+	}
+
+	return masteries, nil
+}
+
+// ListLearnerCheckpoints returns the checkpoint records of the cohort's
+// learners, keyed by user
+func (r *CohortRepository) ListLearnerCheckpoints(cohortID int64) (map[string][]models.LearnerTierStatus, error) {
+	query := `
+		SELECT tc.user_uid, tc.tier_number, tc.is_passed, tc.attempts, tc.passed_at
+		FROM tier_checkpoints tc
+		JOIN cohort_members m ON m.user_uid = tc.user_uid
+		WHERE m.cohort_id = ? AND m.role = 'learner'
+		ORDER BY tc.tier_number ASC
+	`
+
+	rows, err := r.db.Query(query, cohortID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cohort checkpoints: %w", err)
+	}
+	defer rows.Close()
+
+	checkpoints := make(map[string][]models.LearnerTierStatus)
+	for rows.Next() {
+		var uid string
+		var status models.LearnerTierStatus
+		var passedAt sql.NullTime
+		if err := rows.Scan(&uid, &status.TierNumber, &status.IsPassed, &status.Attempts, &passedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort checkpoint: %w", err)
+		}
+		if passedAt.Valid {
+			status.PassedAt = &passedAt.Time
+		}
+		checkpoints[uid] = append(checkpoints[uid], status)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cohort checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// ListRecentSubmissions returns a page of the learners' judge submissions,
+// newest first
+func (r *CohortRepository) ListRecentSubmissions(cohortID int64, limit, offset int) ([]models.CohortSubmission, error) {
+	query := `
+		SELECT s.id, s.user_uid, COALESCE(NULLIF(u.name, ''), 'Anonymous'), s.topic_key, s.problem_id, s.verdict, s.created_at
+		FROM judge_submissions s
+		JOIN cohort_members m ON m.user_uid = s.user_uid
+		JOIN users u ON u.uid = s.user_uid
+		WHERE m.cohort_id = ? AND m.role = 'learner'
+		ORDER BY s.created_at DESC, s.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, cohortID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cohort submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []models.CohortSubmission{}
+	for rows.Next() {
+		var s models.CohortSubmission
+		if err := rows.Scan(&s.ID, &s.UserUID, &s.DisplayName, &s.TopicKey, &s.ProblemID, &s.Verdict, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort submission: %w", err)
+		}
+		submissions = append(submissions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list cohort submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// CreateAssignment stores an assignment
+func (r *CohortRepository) CreateAssignment(assignment *models.CohortAssignment) error {
+	query := `
+		INSERT INTO cohort_assignments (cohort_id, topic_key, problem_id, due_at, created_by, created_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, assignment.CohortID, assignment.TopicKey, assignment.ProblemID, assignment.DueAt, assignment.CreatedBy, now)
+	if err != nil {
+		return fmt.Errorf("failed to create assignment: %w", err)
+	}
+	assignment.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get assignment id: %w", err)
+	}
+	assignment.CreatedAt = now
+
+	return nil
+}
+
+// ListAssignments returns a cohort's assignments, soonest due first
+func (r *CohortRepository) ListAssignments(cohortID int64) ([]models.CohortAssignment, error) {
+	query := `
+		SELECT id, cohort_id, topic_key, COALESCE(problem_id, ''), due_at, COALESCE(created_by, ''), created_at
+		FROM cohort_assignments
+		WHERE cohort_id = ?
+		ORDER BY due_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, cohortID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []models.CohortAssignment{}
+	for rows.Next() {
+		var a models.CohortAssignment
+		if err := rows.Scan(&a.ID, &a.CohortID, &a.TopicKey, &a.ProblemID, &a.DueAt, &a.CreatedBy, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan assignment: %w", err)
+		}
+		assignments = append(assignments, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+
+	return assignments, nil
+}
+
+// DeleteAssignment removes an assignment from a cohort. It reports false if
+// the cohort has no such assignment.
+func (r *CohortRepository) DeleteAssignment(cohortID, assignmentID int64) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM cohort_assignments WHERE id = ? AND cohort_id = ?`, assignmentID, cohortID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete assignment: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete assignment: %w", err)
+	}
+
+	return deleted > 0, nil
+}
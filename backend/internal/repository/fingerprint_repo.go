@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+type FingerprintRepository struct {
+	db *sql.DB
+}
+
+func NewFingerprintRepository(db *sql.DB) *FingerprintRepository {
+	return &FingerprintRepository{db: db}
+}
+
+// FingerprintCandidate is a stored subject sharing fingerprints with a query
+type FingerprintCandidate struct {
+	SubjectType string
+	SubjectID   int64
+	UserUID     string
+	Shared      int
+	Total       int
+}
+
+// Save stores a subject's fingerprints; uid is empty for reference solutions
+func (r *FingerprintRepository) Save(subjectType string, subjectID int64, problemID, uid string, hashes []int64) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	var userUID interface{}
+	if uid != "" {
+		userUID = uid
+	}
+
+	placeholders := make([]string, 0, len(hashes))
+	args := make([]interface{}, 0, len(hashes)*5)
+	for _, hash := range hashes {
+		placeholders = append(placeholders, "(?, ?, ?, ?, ?)")
+		args = append(args, subjectType, subjectID, problemID, userUID, hash)
+	}
+
+	query := `
+		INSERT IGNORE INTO code_fingerprints (subject_type, subject_id, problem_id, user_uid, hash)
+		VALUES ` + strings.Join(placeholders, ", ")
+
+	if _, err := r.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to save fingerprints: %w", err)
+	}
+
+	return nil
+}
+
+// FindCandidates returns the stored subjects for a problem that share the
+// most fingerprints with hashes, skipping the given user's own submissions
+func (r *FingerprintRepository) FindCandidates(problemID, excludeUID string, hashes []int64, limit int) ([]FingerprintCandidate, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(hashes)), ", ")
+	query := `
+		SELECT f.subject_type, f.subject_id, COALESCE(f.user_uid, ''), COUNT(*) AS shared, t.total
+		FROM code_fingerprints f
+		JOIN (
+			SELECT subject_type, subject_id, COUNT(*) AS total
+			FROM code_fingerprints
+			WHERE problem_id = ?
+			GROUP BY subject_type, subject_id
+		) t ON t.subject_type = f.subject_type AND t.subject_id = f.subject_id
+		WHERE f.problem_id = ?
+			AND f.hash IN (` + placeholders + `)
+			AND (f.user_uid IS NULL OR f.user_uid <> ?)
+		GROUP BY f.subject_type, f.subject_id, f.user_uid, t.total
+		ORDER BY shared DESC
+		LIMIT ?
+	`
+
+	args := make([]interface{}, 0, len(hashes)+4)
+	args = append(args, problemID, problemID)
+	for _, hash := range hashes {
+		args = append(args, hash)
+	}
+	args = append(args, excludeUID, limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find similar submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []FingerprintCandidate
+	for rows.Next() {
+		var c FingerprintCandidate
+		if err := rows.Scan(&c.SubjectType, &c.SubjectID, &c.UserUID, &c.Shared, &c.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan similar submission: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
+
+// CreateReference stores a reference solution
+func (r *FingerprintRepository) CreateReference(ref *models.ReferenceSolution) error {
+	query := `
+		INSERT INTO reference_solutions (problem_id, source, code, created_by, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, ref.ProblemID, ref.Source, ref.Code, ref.CreatedBy, now)
+	if err != nil {
+		return fmt.Errorf("failed to create reference solution: %w", err)
+	}
+
+	ref.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get reference solution id: %w", err)
+	}
+	ref.CreatedAt = now
+
+	return nil
+}
+
+// ListReferences returns reference solutions, optionally for one problem
+func (r *FingerprintRepository) ListReferences(problemID string) ([]models.ReferenceSolution, error) {
+	query := `
+		SELECT id, problem_id, source, code, created_by, created_at
+		FROM reference_solutions
+		WHERE ? = '' OR problem_id = ?
+		ORDER BY problem_id, id
+	`
+
+	rows, err := r.db.Query(query, problemID, problemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reference solutions: %w", err)
+	}
+	defer rows.Close()
+
+	refs := []models.ReferenceSolution{}
+	for rows.Next() {
+		var ref models.ReferenceSolution
+		if err := rows.Scan(&ref.ID, &ref.ProblemID, &ref.Source, &ref.Code, &ref.CreatedBy, &ref.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reference solution: %w", err)
+		}
+		refs = append(refs, ref)
+	}
+
+	return refs, rows.Err()
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type HintRepository struct {
+	db *sql.DB
+}
+
+func NewHintRepository(db *sql.DB) *HintRepository {
+	return &HintRepository{db: db}
+}
+
+// RecordUse counts a hint and raises the highest level used on the problem
+func (r *HintRepository) RecordUse(firebaseUID, topicKey, problemID string, level int) error {
+	query := `
+		INSERT INTO hint_usage (user_uid, topic_key, problem_id, max_level, hint_count, first_used_at, last_used_at)
+		VALUES (?, ?, ?, ?, 1, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			max_level = GREATEST(max_level, VALUES(max_level)),
+			hint_count = hint_count + 1,
+			last_used_at = VALUES(last_used_at)
+	`
+
+	now := time.Now()
+	if _, err := r.db.Exec(query, firebaseUID, topicKey, problemID, level, now, now); err != nil {
+		return fmt.Errorf("failed to record hint use: %w", err)
+	}
+
+	return nil
+}
+
+// GetUsage returns the highest hint level used on a problem (0 if none),
+// the number of hints taken and when the last one was
+func (r *HintRepository) GetUsage(firebaseUID, topicKey, problemID string) (int, int, *time.Time, error) {
+	query := `
+		SELECT max_level, hint_count, last_used_at
+		FROM hint_usage
+		WHERE user_uid = ? AND topic_key = ? AND problem_id = ?
+	`
+
+	var maxLevel, count int
+	var lastUsedAt time.Time
+	err := r.db.QueryRow(query, firebaseUID, topicKey, problemID).Scan(&maxLevel, &count, &lastUsedAt)
+	if err == sql.ErrNoRows {
+		return 0, 0, nil, nil
+	}
+	if err != nil {
+		return 0, 0, nil, fmt.Errorf("failed to get hint usage: %w", err)
+	}
+
+	return maxLevel, count, &lastUsedAt, nil
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// WeekPeriod is the weekly board period containing t: its ISO week in UTC,
+// e.g. "2026-W07" (the same as MySQL's DATE_FORMAT '%x-W%v')
+func WeekPeriod(t time.Time) string {
+	year, week := t.UTC().ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// addLeaderboardScores adds an XP award to the boards it counts towards,
+// inside the transaction that records it
+func addLeaderboardScores(tx *sql.Tx, award *models.XPAward, at time.Time) error {
+	boards := [][2]string{
+		{models.LeaderboardGlobal, models.LeaderboardAllTime},
+		{models.LeaderboardWeekly, WeekPeriod(at)},
+	}
+	if award.TopicKey != "" {
+		boards = append(boards, [2]string{models.LeaderboardTopicPrefix + award.TopicKey, models.LeaderboardAllTime})
+	}
+
+	for _, b := range boards {
+		_, err := tx.Exec(`
+			INSERT INTO leaderboard_scores (board, period, user_uid, score, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE score = score + VALUES(score), updated_at = VALUES(updated_at)
+		`, b[0], b[1], award.UserUID, award.Amount, at)
+		if err != nil {
+			return fmt.Errorf("failed to update %s leaderboard: %w", b[0], err)
+		}
+	}
+
+	return nil
+}
+
+type LeaderboardRepository struct {
+	db *sql.DB
+}
+
+func NewLeaderboardRepository(db *sql.DB) *LeaderboardRepository {
+	return &LeaderboardRepository{db: db}
+}
+
+// visibleScores selects a board's scores for users who haven't opted out.
+// Ties go to whoever reached the score first.
+const visibleScores = `
+	FROM leaderboard_scores s
+	JOIN users u ON u.uid = s.user_uid
+	LEFT JOIN user_settings st ON st.user_uid = s.user_uid
+	WHERE s.board = ? AND s.period = ? AND s.score > 0 AND COALESCE(st.leaderboard_opt_out, FALSE) = FALSE
+`
+
+// scoresIn returns the FROM/WHERE clause and arguments for a board's
+// visible scores, narrowed to a cohort's learners unless cohortID is 0
+func scoresIn(board, period string, cohortID int64) (string, []interface{}) {
+	if cohortID == 0 {
+		return visibleScores, []interface{}{board, period}
+	}
+	clause := visibleScores + ` AND s.user_uid IN (SELECT user_uid FROM cohort_members WHERE cohort_id = ? AND role = 'learner')`
+	return clause, []interface{}{board, period, cohortID}
+}
+
+// List returns a page of a board in rank order, the users on it and the
+// number of ranked users. A non-zero cohortID ranks only its learners.
+func (r *LeaderboardRepository) List(board, period string, cohortID int64, limit, offset int) ([]models.LeaderboardEntry, []string, int, error) {
+	scores, args := scoresIn(board, period, cohortID)
+
+	var total int
+	if err := r.db.QueryRow(`SELECT COUNT(*) `+scores, args...).Scan(&total); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to count leaderboard: %w", err)
+	}
+
+	query := `
+		SELECT s.user_uid, COALESCE(NULLIF(u.name, ''), 'Anonymous'), s.score
+	` + scores + `
+		ORDER BY s.score DESC, s.updated_at ASC, s.user_uid ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to list leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.LeaderboardEntry{}
+	var uids []string
+	for rows.Next() {
+		var uid string
+		e := models.LeaderboardEntry{Rank: offset + len(entries) + 1}
+		if err := rows.Scan(&uid, &e.DisplayName, &e.Score); err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		entries = append(entries, e)
+		uids = append(uids, uid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to list leaderboard: %w", err)
+	}
+
+	return entries, uids, total, nil
+}
+
+// Rank returns the user's entry on a board, or nil if they have no score,
+// opted out or aren't ranked in the cohort. The rank uses the same
+// ordering as List.
+func (r *LeaderboardRepository) Rank(board, period string, cohortID int64, firebaseUID string) (*models.LeaderboardEntry, error) {
+	scores, args := scoresIn(board, period, cohortID)
+
+	var entry models.LeaderboardEntry
+	var updatedAt time.Time
+	err := r.db.QueryRow(`SELECT COALESCE(NULLIF(u.name, ''), 'Anonymous'), s.score, s.updated_at `+scores+` AND s.user_uid = ?`,
+		append(args, firebaseUID)...).Scan(&entry.DisplayName, &entry.Score, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard score: %w", err)
+	}
+
+	var ahead int
+	err = r.db.QueryRow(`SELECT COUNT(*) `+scores+`
+		AND (s.score > ? OR (s.score = ? AND (s.updated_at < ? OR (s.updated_at = ? AND s.user_uid < ?))))`,
+		append(args, entry.Score, entry.Score, updatedAt, updatedAt, firebaseUID)...).Scan(&ahead)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank leaderboard score: %w", err)
+	}
+	entry.Rank = ahead + 1
+	entry.IsCaller = true
+
+	return &entry, nil
+}
@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// ErrProblemAlreadyReviewed is returned when moderating a version that is no
+// longer pending
+var ErrProblemAlreadyReviewed = errors.New("problem version already reviewed")
+
+// ProblemRepository stores user-authored problems and their versions
+type ProblemRepository struct {
+	db *sql.DB
+}
+
+func NewProblemRepository(db *sql.DB) *ProblemRepository {
+	return &ProblemRepository{db: db}
+}
+
+const versionColumns = `
+	v.id, v.authored_problem_id, p.topic_key, p.problem_id, COALESCE(p.author_uid, ''), p.source, v.version,
+	v.title, v.difficulty, v.statement, v.invariant, v.examples, v.constraints, v.tests,
+	v.solution_language, v.solution_entry, v.solution_code, v.validation, COALESCE(v.prompt_version, ''),
+	v.status, COALESCE(v.reviewed_by, ''), COALESCE(v.review_note, ''), v.reviewed_at, v.created_at
+`
+
+// scanVersion scans versionColumns, returning nil when there is no row
+func scanVersion(row rowScanner) (*models.ProblemVersion, error) {
+	var v models.ProblemVersion
+	var examples, constraints, tests, validation []byte
+	var reviewedAt sql.NullTime
+	err := row.Scan(
+		&v.ID, &v.AuthoredProblemID, &v.TopicKey, &v.ProblemID, &v.AuthorUID, &v.Source, &v.Version,
+		&v.Title, &v.Difficulty, &v.Statement, &v.Invariant, &examples, &constraints, &tests,
+		&v.Solution.Language, &v.Solution.Entry, &v.Solution.Code, &validation, &v.PromptVersion,
+		&v.Status, &v.ReviewedBy, &v.ReviewNote, &reviewedAt, &v.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan problem version: %w", err)
+	}
+	if reviewedAt.Valid {
+		v.ReviewedAt = &reviewedAt.Time
+	}
+
+	for _, field := range []struct {
+		name string
+		data []byte
+		dest interface{}
+	}{
+		{"examples", examples, &v.Examples},
+		{"constraints", constraints, &v.Constraints},
+		{"tests", tests, &v.Tests},
+		{"validation", validation, &v.Validation},
+	} {
+		if err := json.Unmarshal(field.data, field.dest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", field.name, err)
+		}
+	}
+
+	return &v, nil
+}
+
+// queryVersions runs a query selecting versionColumns
+func (r *ProblemRepository) queryVersions(query string, args ...interface{}) ([]models.ProblemVersion, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problem versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := []models.ProblemVersion{}
+	for rows.Next() {
+		v, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+
+	return versions, rows.Err()
+}
+
+// Create stores a new problem with its first version. A problem without an
+// author (an LLM draft) is stored with a NULL author_uid.
+func (r *ProblemRepository) Create(problem *models.AuthoredProblem, version *models.ProblemVersion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		INSERT INTO authored_problems (topic_key, problem_id, author_uid, source, created_at)
+		VALUES (?, ?, NULLIF(?, ''), ?, ?)
+	`, problem.TopicKey, problem.ProblemID, problem.AuthorUID, problem.Source, now)
+	if err != nil {
+		return fmt.Errorf("failed to create problem: %w", err)
+	}
+	problem.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get problem id: %w", err)
+	}
+	problem.CreatedAt = now
+
+	version.AuthoredProblemID = problem.ID
+	version.Version = 1
+	if err := insertVersion(tx, version, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddVersion stores a revision numbered after the problem's latest version
+func (r *ProblemRepository) AddVersion(version *models.ProblemVersion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the problem so concurrent revisions get distinct numbers
+	if _, err := tx.Exec(`SELECT id FROM authored_problems WHERE id = ? FOR UPDATE`, version.AuthoredProblemID); err != nil {
+		return fmt.Errorf("failed to lock problem: %w", err)
+	}
+	var latest int
+	err = tx.QueryRow(`
+		SELECT COALESCE(MAX(version), 0) FROM authored_problem_versions WHERE authored_problem_id = ?
+	`, version.AuthoredProblemID).Scan(&latest)
+	if err != nil {
+		return fmt.Errorf("failed to get latest problem version: %w", err)
+	}
+
+	version.Version = latest + 1
+	if err := insertVersion(tx, version, time.Now()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func insertVersion(db execer, version *models.ProblemVersion, now time.Time) error {
+	var encoded [4][]byte
+	for i, value := range []interface{}{version.Examples, version.Constraints, version.Tests, version.Validation} {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal problem version: %w", err)
+		}
+		encoded[i] = data
+	}
+
+	if version.Status == "" {
+		version.Status = models.ProblemVersionPending
+	}
+
+	result, err := db.Exec(`
+		INSERT INTO authored_problem_versions (
+			authored_problem_id, version, title, difficulty, statement, invariant, examples, constraints, tests,
+			solution_language, solution_entry, solution_code, validation, prompt_version, status, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+	`, version.AuthoredProblemID, version.Version, version.Title, version.Difficulty, version.Statement, version.Invariant,
+		encoded[0], encoded[1], encoded[2],
+		version.Solution.Language, version.Solution.Entry, version.Solution.Code, encoded[3], version.PromptVersion, version.Status, now)
+	if err != nil {
+		return fmt.Errorf("failed to create problem version: %w", err)
+	}
+
+	version.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get problem version id: %w", err)
+	}
+	version.CreatedAt = now
+
+	return nil
+}
+
+// ProblemIDTaken reports whether an authored problem already uses the ID in
+// the topic
+func (r *ProblemRepository) ProblemIDTaken(topicKey, problemID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(SELECT 1 FROM authored_problems WHERE topic_key = ? AND problem_id = ?)
+	`, topicKey, problemID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check problem id: %w", err)
+	}
+	return exists, nil
+}
+
+// GetByID returns a problem with all its versions, oldest first
+func (r *ProblemRepository) GetByID(id int64) (*models.AuthoredProblem, error) {
+	var p models.AuthoredProblem
+	var published sql.NullInt64
+	err := r.db.QueryRow(`
+		SELECT id, topic_key, problem_id, COALESCE(author_uid, ''), source, published_version, created_at
+		FROM authored_problems
+		WHERE id = ?
+	`, id).Scan(&p.ID, &p.TopicKey, &p.ProblemID, &p.AuthorUID, &p.Source, &published, &p.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get problem: %w", err)
+	}
+	if published.Valid {
+		version := int(published.Int64)
+		p.PublishedVersion = &version
+	}
+
+	p.Versions, err = r.queryVersions(`SELECT `+versionColumns+`
+		FROM authored_problem_versions v
+		JOIN authored_problems p ON p.id = v.authored_problem_id
+		WHERE v.authored_problem_id = ?
+		ORDER BY v.version ASC
+	`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// ListIDsByAuthor returns the IDs of a user's problems, newest first
+func (r *ProblemRepository) ListIDsByAuthor(firebaseUID string) ([]int64, error) {
+	rows, err := r.db.Query(`
+		SELECT id FROM authored_problems WHERE author_uid = ? ORDER BY created_at DESC, id DESC
+	`, firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list problems: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan problem id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetVersion returns one version, or nil if it doesn't exist
+func (r *ProblemRepository) GetVersion(versionID int64) (*models.ProblemVersion, error) {
+	query := `SELECT ` + versionColumns + `
+		FROM authored_problem_versions v
+		JOIN authored_problems p ON p.id = v.authored_problem_id
+		WHERE v.id = ?
+	`
+	return scanVersion(r.db.QueryRow(query, versionID))
+}
+
+// ListByStatus returns versions of problems from a source in a moderation
+// status, oldest first
+func (r *ProblemRepository) ListByStatus(source, status string, limit, offset int) ([]models.ProblemVersion, error) {
+	return r.queryVersions(`SELECT `+versionColumns+`
+		FROM authored_problem_versions v
+		JOIN authored_problems p ON p.id = v.authored_problem_id
+		WHERE p.source = ? AND v.status = ?
+		ORDER BY v.created_at ASC, v.id ASC
+		LIMIT ? OFFSET ?
+	`, source, status, limit, offset)
+}
+
+// GetPublished returns the published version of an authored problem, or
+// nil if there is none
+func (r *ProblemRepository) GetPublished(topicKey, problemID string) (*models.ProblemVersion, error) {
+	query := `SELECT ` + versionColumns + `
+		FROM authored_problems p
+		JOIN authored_problem_versions v ON v.authored_problem_id = p.id AND v.version = p.published_version
+		WHERE p.topic_key = ? AND p.problem_id = ?
+	`
+	return scanVersion(r.db.QueryRow(query, topicKey, problemID))
+}
+
+// GetByNumber returns one version of an authored problem, or nil if there
+// is no such version
+func (r *ProblemRepository) GetByNumber(topicKey, problemID string, version int) (*models.ProblemVersion, error) {
+	query := `SELECT ` + versionColumns + `
+		FROM authored_problems p
+		JOIN authored_problem_versions v ON v.authored_problem_id = p.id AND v.version = ?
+		WHERE p.topic_key = ? AND p.problem_id = ?
+	`
+	return scanVersion(r.db.QueryRow(query, version, topicKey, problemID))
+}
+
+// ListPublished returns the published versions of a topic's authored
+// problems, oldest problem first
+func (r *ProblemRepository) ListPublished(topicKey string) ([]models.ProblemVersion, error) {
+	return r.queryVersions(`SELECT `+versionColumns+`
+		FROM authored_problems p
+		JOIN authored_problem_versions v ON v.authored_problem_id = p.id AND v.version = p.published_version
+		WHERE p.topic_key = ?
+		ORDER BY p.created_at ASC, p.id ASC
+	`, topicKey)
+}
+
+// Review records a moderation decision on a pending version. Approving a
+// version publishes it in place of the problem's previous version.
+func (r *ProblemRepository) Review(version *models.ProblemVersion, status, reviewerUID, note string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE authored_problem_versions
+		SET status = ?, reviewed_by = ?, review_note = NULLIF(?, ''), reviewed_at = ?
+		WHERE id = ? AND status = ?
+	`, status, reviewerUID, note, now, version.ID, models.ProblemVersionPending)
+	if err != nil {
+		return fmt.Errorf("failed to review problem version: %w", err)
+	}
+	if reviewed, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to review problem version: %w", err)
+	} else if reviewed == 0 {
+		return ErrProblemAlreadyReviewed
+	}
+
+	if status == models.ProblemVersionApproved {
+		_, err := tx.Exec(`
+			UPDATE authored_problems
+			SET published_version = GREATEST(COALESCE(published_version, 0), ?)
+			WHERE id = ?
+		`, version.Version, version.AuthoredProblemID)
+		if err != nil {
+			return fmt.Errorf("failed to publish problem version: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	version.Status = status
+	version.ReviewedBy = reviewerUID
+	version.ReviewNote = note
+	version.ReviewedAt = &now
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/progression"
+)
+
+type ProgressRepository struct {
+	db *sql.DB
+}
+
+func NewProgressRepository(db *sql.DB) *ProgressRepository {
+	return &ProgressRepository{db: db}
+}
+
+// Award adds an XP entry and raises the user's total and leaderboard
+// scores. It reports false without changing anything if the source was
+// already awarded.
+func (r *ProgressRepository) Award(award *models.XPAward) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		INSERT IGNORE INTO xp_ledger (user_uid, source_type, source_id, reason, topic_key, amount, created_at)
+		VALUES (?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+	`, award.UserUID, award.SourceType, award.SourceID, award.Reason, award.TopicKey, award.Amount, now)
+	if err != nil {
+		return false, fmt.Errorf("failed to award xp: %w", err)
+	}
+	if inserted, err := result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("failed to award xp: %w", err)
+	} else if inserted == 0 {
+		return false, nil
+	}
+	award.ID, err = result.LastInsertId()
+	if err != nil {
+		return false, fmt.Errorf("failed to get xp award id: %w", err)
+	}
+	award.CreatedAt = now
+
+	_, err = tx.Exec(`
+		INSERT INTO user_progress (user_uid, total_xp)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE total_xp = total_xp + VALUES(total_xp)
+	`, award.UserUID, award.Amount)
+	if err != nil {
+		return false, fmt.Errorf("failed to update xp total: %w", err)
+	}
+	if err := addLeaderboardScores(tx, award, now); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// RecordActivity advances the user's streak for activity on day (a date in
+// the user's timezone) under a row lock, so concurrent submissions can't
+// both extend it
+func (r *ProgressRepository) RecordActivity(firebaseUID string, day time.Time, rules progression.StreakRules) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`INSERT IGNORE INTO user_progress (user_uid) VALUES (?)`, firebaseUID)
+	if err != nil {
+		return fmt.Errorf("failed to create progress: %w", err)
+	}
+
+	var streak progression.Streak
+	var lastActiveDay sql.NullString
+	var freezesUsed int
+	err = tx.QueryRow(`
+		SELECT current_streak, longest_streak, DATE_FORMAT(last_active_day, '%Y-%m-%d'), freeze_tokens, freezes_used
+		FROM user_progress
+		WHERE user_uid = ?
+		FOR UPDATE
+	`, firebaseUID).Scan(&streak.Current, &streak.Longest, &lastActiveDay, &streak.FreezeTokens, &freezesUsed)
+	if err != nil {
+		return fmt.Errorf("failed to get streak: %w", err)
+	}
+	streak.LastActiveDay = lastActiveDay.String
+
+	updated, spent := rules.RecordActivity(streak, day)
+	if updated == streak {
+		return nil
+	}
+
+	_, err = tx.Exec(`
+		UPDATE user_progress
+		SET current_streak = ?, longest_streak = ?, last_active_day = ?, freeze_tokens = ?, freezes_used = ?
+		WHERE user_uid = ?
+	`, updated.Current, updated.Longest, updated.LastActiveDay, updated.FreezeTokens, freezesUsed+spent, firebaseUID)
+	if err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns the user's progress, zero-valued if they have none yet
+func (r *ProgressRepository) Get(firebaseUID string) (*models.UserProgress, error) {
+	query := `
+		SELECT total_xp, current_streak, longest_streak, COALESCE(DATE_FORMAT(last_active_day, '%Y-%m-%d'), ''),
+		       freeze_tokens, freezes_used
+		FROM user_progress
+		WHERE user_uid = ?
+	`
+
+	var progress models.UserProgress
+	err := r.db.QueryRow(query, firebaseUID).Scan(
+		&progress.TotalXP,
+		&progress.CurrentStreak,
+		&progress.LongestStreak,
+		&progress.LastActiveDay,
+		&progress.FreezeTokens,
+		&progress.FreezesUsed,
+	)
+	if err == sql.ErrNoRows {
+		return &progress, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get progress: %w", err)
+	}
+
+	return &progress, nil
+}
+
+// ListAwards returns the user's most recent XP awards, newest first
+func (r *ProgressRepository) ListAwards(firebaseUID string, limit int) ([]models.XPAward, error) {
+	query := `
+		SELECT id, user_uid, source_type, source_id, reason, COALESCE(topic_key, ''), amount, created_at
+		FROM xp_ledger
+		WHERE user_uid = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, firebaseUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list xp awards: %w", err)
+	}
+	defer rows.Close()
+
+	awards := []models.XPAward{}
+	for rows.Next() {
+		var a models.XPAward
+		if err := rows.Scan(&a.ID, &a.UserUID, &a.SourceType, &a.SourceID, &a.Reason, &a.TopicKey, &a.Amount, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan xp award: %w", err)
+		}
+		awards = append(awards, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list xp awards: %w", err)
+	}
+
+	return awards, nil
+}
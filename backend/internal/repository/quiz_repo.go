@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// ErrQuizNotPending means the quiz was already graded or has expired
+var ErrQuizNotPending = errors.New("quiz not pending")
+
+type QuizRepository struct {
+	db *sql.DB
+}
+
+func NewQuizRepository(db *sql.DB) *QuizRepository {
+	return &QuizRepository{db: db}
+}
+
+// Create stores a new pending quiz
+func (r *QuizRepository) Create(quiz *models.VerificationQuiz) error {
+	questions, err := json.Marshal(quiz.Questions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quiz questions: %w", err)
+	}
+
+	query := `
+		INSERT INTO verification_quizzes (user_uid, subject_type, subject_id, reason, questions, status, prompt_version, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, quiz.UserUID, quiz.SubjectType, quiz.SubjectID, quiz.Reason, questions,
+		models.QuizPending, quiz.PromptVersion, now, quiz.ExpiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to create quiz: %w", err)
+	}
+
+	quiz.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get quiz id: %w", err)
+	}
+	quiz.Status = models.QuizPending
+	quiz.CreatedAt = now
+
+	return nil
+}
+
+// GetBySubject returns the quiz for a submission, or nil if there is none
+func (r *QuizRepository) GetBySubject(subjectType string, subjectID int64) (*models.VerificationQuiz, error) {
+	query := `
+		SELECT id, user_uid, subject_type, subject_id, reason, questions, answers, grades, status,
+		       score, COALESCE(prompt_version, ''), created_at, expires_at, answered_at
+		FROM verification_quizzes
+		WHERE subject_type = ? AND subject_id = ?
+	`
+
+	var quiz models.VerificationQuiz
+	var questions, answers, grades []byte
+	var score sql.NullInt64
+	err := r.db.QueryRow(query, subjectType, subjectID).Scan(
+		&quiz.ID,
+		&quiz.UserUID,
+		&quiz.SubjectType,
+		&quiz.SubjectID,
+		&quiz.Reason,
+		&questions,
+		&answers,
+		&grades,
+		&quiz.Status,
+		&score,
+		&quiz.PromptVersion,
+		&quiz.CreatedAt,
+		&quiz.ExpiresAt,
+		&quiz.AnsweredAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiz: %w", err)
+	}
+
+	if err := json.Unmarshal(questions, &quiz.Questions); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal quiz questions: %w", err)
+	}
+	if len(answers) > 0 {
+		if err := json.Unmarshal(answers, &quiz.Answers); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal quiz answers: %w", err)
+		}
+	}
+	if len(grades) > 0 {
+		if err := json.Unmarshal(grades, &quiz.Grades); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal quiz grades: %w", err)
+		}
+	}
+	if score.Valid {
+		s := int(score.Int64)
+		quiz.Score = &s
+	}
+
+	return &quiz, nil
+}
+
+// Complete records the answers, grades and outcome of a pending quiz. It
+// returns ErrQuizNotPending if the quiz was already completed, so a quiz
+// can only be graded once.
+func (r *QuizRepository) Complete(quiz *models.VerificationQuiz) error {
+	answers, err := json.Marshal(quiz.Answers)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quiz answers: %w", err)
+	}
+	grades, err := json.Marshal(quiz.Grades)
+	if err != nil {
+		return fmt.Errorf("failed to marshal quiz grades: %w", err)
+	}
+
+	query := `
+		UPDATE verification_quizzes
+		SET answers = ?, grades = ?, status = ?, score = ?, answered_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, answers, grades, quiz.Status, quiz.Score, now, quiz.ID, models.QuizPending)
+	if err != nil {
+		return fmt.Errorf("failed to complete quiz: %w", err)
+	}
+	if updated, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to complete quiz: %w", err)
+	} else if updated == 0 {
+		return ErrQuizNotPending
+	}
+	quiz.AnsweredAt = sql.NullTime{Time: now, Valid: true}
+
+	return nil
+}
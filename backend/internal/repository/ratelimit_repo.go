@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/ratelimit"
+)
+
+// RateLimitRepository is a MySQL-backed rate limit store shared by all API instances
+type RateLimitRepository struct {
+	db *sql.DB
+}
+
+func NewRateLimitRepository(db *sql.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+// Take implements ratelimit.Store using a locked row per bucket
+func (r *RateLimitRepository) Take(key string, rule ratelimit.Rule) (bool, time.Duration, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	// Create the bucket full if it doesn't exist yet
+	_, err = tx.Exec(`
+		INSERT IGNORE INTO rate_limit_buckets (bucket_key, tokens, updated_at)
+		VALUES (?, ?, ?)
+	`, key, float64(rule.Burst), now)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to create bucket: %w", err)
+	}
+
+	var tokens float64
+	var updatedAt time.Time
+	err = tx.QueryRow(`
+		SELECT tokens, updated_at FROM rate_limit_buckets WHERE bucket_key = ? FOR UPDATE
+	`, key).Scan(&tokens, &updatedAt)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to lock bucket: %w", err)
+	}
+
+	tokens, allowed, retryAfter := ratelimit.TakeToken(tokens, now.Sub(updatedAt), rule)
+
+	_, err = tx.Exec(`
+		UPDATE rate_limit_buckets SET tokens = ?, updated_at = ? WHERE bucket_key = ?
+	`, tokens, now, key)
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to update bucket: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return allowed, retryAfter, nil
+}
@@ -0,0 +1,291 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+var (
+	// ErrReviewAlreadyResolved is returned when resolving a review that is no longer pending
+	ErrReviewAlreadyResolved = errors.New("review already resolved")
+	// ErrReviewReopenLimit is returned when a review was already reopened as often as allowed
+	ErrReviewReopenLimit = errors.New("review cannot be reopened again")
+)
+
+const reviewColumns = `
+	id, user_uid, subject_type, subject_id, source, status, reopen_count, COALESCE(details, ''),
+	created_at, resolved_at, COALESCE(resolved_by, ''), COALESCE(resolution_verdict, ''), COALESCE(resolution_note, '')
+`
+
+type ReviewRepository struct {
+	db *sql.DB
+}
+
+func NewReviewRepository(db *sql.DB) *ReviewRepository {
+	return &ReviewRepository{db: db}
+}
+
+// Enqueue adds a review request. A subject already in the queue is left as is.
+func (r *ReviewRepository) Enqueue(review *models.ReviewRequest) error {
+	query := `
+		INSERT INTO review_requests (user_uid, subject_type, subject_id, source, status, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
+	`
+
+	now := time.Now()
+	if review.Status == "" {
+		review.Status = models.ReviewPending
+	}
+
+	result, err := r.db.Exec(query, review.UserUID, review.SubjectType, review.SubjectID, review.Source, review.Status, review.Details, now)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue review: %w", err)
+	}
+
+	review.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get review id: %w", err)
+	}
+	review.CreatedAt = now
+
+	return nil
+}
+
+// GetByID retrieves a review request by ID
+func (r *ReviewRepository) GetByID(id int64) (*models.ReviewRequest, error) {
+	query := `SELECT ` + reviewColumns + ` FROM review_requests WHERE id = ?`
+	return scanReview(r.db.QueryRow(query, id))
+}
+
+// GetBySubject retrieves the review request for a submission, if any
+func (r *ReviewRepository) GetBySubject(subjectType string, subjectID int64) (*models.ReviewRequest, error) {
+	query := `SELECT ` + reviewColumns + ` FROM review_requests WHERE subject_type = ? AND subject_id = ?`
+	return scanReview(r.db.QueryRow(query, subjectType, subjectID))
+}
+
+// ListByStatus returns reviews with the given status, oldest first
+func (r *ReviewRepository) ListByStatus(status string, limit, offset int) ([]models.ReviewRequest, error) {
+	query := `
+		SELECT ` + reviewColumns + `
+		FROM review_requests
+		WHERE status = ?
+		ORDER BY created_at ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []models.ReviewRequest{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+
+	return reviews, nil
+}
+
+// Reopen puts an already resolved review back in the queue with a new
+// source, unless it has been reopened maxReopens times already
+func (r *ReviewRepository) Reopen(review *models.ReviewRequest, maxReopens int) error {
+	query := `
+		UPDATE review_requests
+		SET status = ?, source = ?, details = ?, created_at = ?, reopen_count = reopen_count + 1,
+		    resolved_at = NULL, resolved_by = NULL, resolution_verdict = NULL, resolution_note = NULL
+		WHERE id = ? AND status = ? AND reopen_count < ?
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query, models.ReviewPending, review.Source, review.Details, now, review.ID, models.ReviewResolved, maxReopens)
+	if err != nil {
+		return fmt.Errorf("failed to reopen review: %w", err)
+	}
+	if reopened, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to reopen review: %w", err)
+	} else if reopened == 0 {
+		return ErrReviewReopenLimit
+	}
+
+	review.Status = models.ReviewPending
+	review.ReopenCount++
+	review.CreatedAt = now
+	review.ResolvedAt = sql.NullTime{}
+	review.ResolvedBy = ""
+	review.ResolutionVerdict = ""
+	review.ResolutionNote = ""
+
+	return nil
+}
+
+// Resolve closes a pending review with the mentor's verdict and writes the
+// audit entry in the same transaction
+func (r *ReviewRepository) Resolve(review *models.ReviewRequest, entry *models.ReviewAuditEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE review_requests
+		SET status = ?, resolved_at = ?, resolved_by = ?, resolution_verdict = ?, resolution_note = ?
+		WHERE id = ? AND status = ?
+	`, models.ReviewResolved, now, entry.ActorUID, entry.NewVerdict, entry.Note, review.ID, models.ReviewPending)
+	if err != nil {
+		return fmt.Errorf("failed to resolve review: %w", err)
+	}
+	if resolved, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to resolve review: %w", err)
+	} else if resolved == 0 {
+		return ErrReviewAlreadyResolved
+	}
+
+	if err := insertAuditEntry(tx, entry); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	review.Status = models.ReviewResolved
+	review.ResolvedAt = sql.NullTime{Time: now, Valid: true}
+	review.ResolvedBy = entry.ActorUID
+	review.ResolutionVerdict = entry.NewVerdict
+	review.ResolutionNote = entry.Note
+
+	return nil
+}
+
+// Release puts a review closed by Resolve back in the queue and removes its
+// audit entry, for a verdict that could not be applied
+func (r *ReviewRepository) Release(review *models.ReviewRequest, entry *models.ReviewAuditEntry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		UPDATE review_requests
+		SET status = ?, resolved_at = NULL, resolved_by = NULL, resolution_verdict = NULL, resolution_note = NULL
+		WHERE id = ? AND status = ? AND resolved_by = ?
+	`, models.ReviewPending, review.ID, models.ReviewResolved, entry.ActorUID)
+	if err != nil {
+		return fmt.Errorf("failed to release review: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM review_audit_log WHERE id = ?`, entry.ID); err != nil {
+		return fmt.Errorf("failed to remove audit entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	review.Status = models.ReviewPending
+	review.ResolvedAt = sql.NullTime{}
+	review.ResolvedBy = ""
+	review.ResolutionVerdict = ""
+	review.ResolutionNote = ""
+
+	return nil
+}
+
+// AddAuditEntry appends an entry to a review's audit trail
+func (r *ReviewRepository) AddAuditEntry(entry *models.ReviewAuditEntry) error {
+	return insertAuditEntry(r.db, entry)
+}
+
+// GetAuditTrail returns a review's audit entries, oldest first
+func (r *ReviewRepository) GetAuditTrail(reviewID int64) ([]models.ReviewAuditEntry, error) {
+	query := `
+		SELECT id, review_id, actor_uid, action, COALESCE(previous_verdict, ''), COALESCE(new_verdict, ''),
+		       COALESCE(note, ''), created_at
+		FROM review_audit_log
+		WHERE review_id = ?
+		ORDER BY created_at ASC, id ASC
+	`
+
+	rows, err := r.db.Query(query, reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []models.ReviewAuditEntry
+	for rows.Next() {
+		var e models.ReviewAuditEntry
+		if err := rows.Scan(&e.ID, &e.ReviewID, &e.ActorUID, &e.Action, &e.PreviousVerdict, &e.NewVerdict, &e.Note, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+func insertAuditEntry(db execer, entry *models.ReviewAuditEntry) error {
+	query := `
+		INSERT INTO review_audit_log (review_id, actor_uid, action, previous_verdict, new_verdict, note, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	result, err := db.Exec(query, entry.ReviewID, entry.ActorUID, entry.Action, entry.PreviousVerdict, entry.NewVerdict, entry.Note, now)
+	if err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+
+	entry.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get audit entry id: %w", err)
+	}
+	entry.CreatedAt = now
+
+	return nil
+}
+
+// scanReview scans a single review row, returning nil when there is none
+func scanReview(row rowScanner) (*models.ReviewRequest, error) {
+	var review models.ReviewRequest
+	err := row.Scan(
+		&review.ID,
+		&review.UserUID,
+		&review.SubjectType,
+		&review.SubjectID,
+		&review.Source,
+		&review.Status,
+		&review.ReopenCount,
+		&review.Details,
+		&review.CreatedAt,
+		&review.ResolvedAt,
+		&review.ResolvedBy,
+		&review.ResolutionVerdict,
+		&review.ResolutionNote,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan review: %w", err)
+	}
+
+	return &review, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+type SecurityEventRepository struct {
+	db *sql.DB
+}
+
+func NewSecurityEventRepository(db *sql.DB) *SecurityEventRepository {
+	return &SecurityEventRepository{db: db}
+}
+
+// Record stores a security event
+func (r *SecurityEventRepository) Record(event *models.SecurityEvent) error {
+	query := `
+		INSERT INTO security_events (user_uid, event_type, subject_type, subject_id, details, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	now := time.Now()
+	var details interface{}
+	if len(event.Details) > 0 {
+		details = []byte(event.Details)
+	}
+
+	result, err := r.db.Exec(query, event.UserUID, event.EventType, event.SubjectType, event.SubjectID, details, now)
+	if err != nil {
+		return fmt.Errorf("failed to record security event: %w", err)
+	}
+
+	event.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get security event id: %w", err)
+	}
+	event.CreatedAt = now
+
+	return nil
+}
+
+// List returns the most recent events, optionally filtered by type
+func (r *SecurityEventRepository) List(eventType string, limit, offset int) ([]models.SecurityEvent, error) {
+	query := `
+		SELECT id, user_uid, event_type, subject_type, subject_id, details, created_at
+		FROM security_events
+		WHERE ? = '' OR event_type = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	rows, err := r.db.Query(query, eventType, eventType, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.SecurityEvent{}
+	for rows.Next() {
+		var event models.SecurityEvent
+		var details []byte
+		if err := rows.Scan(&event.ID, &event.UserUID, &event.EventType, &event.SubjectType, &event.SubjectID, &details, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan security event: %w", err)
+		}
+		event.Details = details
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
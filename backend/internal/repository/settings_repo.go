@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// DefaultTimezone applies to users who never set one
+const DefaultTimezone = "UTC"
+
+type SettingsRepository struct {
+	db *sql.DB
+}
+
+func NewSettingsRepository(db *sql.DB) *SettingsRepository {
+	return &SettingsRepository{db: db}
+}
+
+// Get returns the user's settings, or the defaults if they have none
+func (r *SettingsRepository) Get(firebaseUID string) (*models.UserSettings, error) {
+	query := `SELECT timezone, leaderboard_opt_out, COALESCE(curriculum_key, '') FROM user_settings WHERE user_uid = ?`
+
+	settings := models.UserSettings{Timezone: DefaultTimezone}
+	err := r.db.QueryRow(query, firebaseUID).Scan(&settings.Timezone, &settings.LeaderboardOptOut, &settings.Curriculum)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get settings: %w", err)
+	}
+
+	return &settings, nil
+}
+
+// Save stores the user's settings
+func (r *SettingsRepository) Save(firebaseUID string, settings *models.UserSettings) error {
+	query := `
+		INSERT INTO user_settings (user_uid, timezone, leaderboard_opt_out, curriculum_key)
+		VALUES (?, ?, ?, NULLIF(?, ''))
+		ON DUPLICATE KEY UPDATE timezone = VALUES(timezone), leaderboard_opt_out = VALUES(leaderboard_opt_out),
+			curriculum_key = VALUES(curriculum_key)
+	`
+
+	if _, err := r.db.Exec(query, firebaseUID, settings.Timezone, settings.LeaderboardOptOut, settings.Curriculum); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+type SubmissionRepository struct {
+	db *sql.DB
+}
+
+func NewSubmissionRepository(db *sql.DB) *SubmissionRepository {
+	return &SubmissionRepository{db: db}
+}
+
+// Create stores a judged submission
+func (r *SubmissionRepository) Create(submission *models.JudgeSubmission) error {
+	testResults, err := marshalTestResults(submission.TestResults)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO judge_submissions (user_uid, topic_key, problem_id, problem_version, code, verdict, feedback, prompt_version, test_results, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, NULLIF(?, ''), ?, ?)
+	`
+
+	now := time.Now()
+	result, err := r.db.Exec(query,
+		submission.UserUID,
+		submission.TopicKey,
+		submission.ProblemID,
+		submission.ProblemVersion,
+		submission.Code,
+		submission.Verdict,
+		submission.Feedback,
+		submission.PromptVersion,
+		testResults,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create submission: %w", err)
+	}
+
+	submission.ID, err = result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get submission id: %w", err)
+	}
+	submission.CreatedAt = now
+
+	return nil
+}
+
+// GetByID retrieves a submission by ID
+func (r *SubmissionRepository) GetByID(id int64) (*models.JudgeSubmission, error) {
+	query := `
+		SELECT id, user_uid, topic_key, problem_id, problem_version, code, verdict, COALESCE(feedback, ''), COALESCE(prompt_version, ''),
+		       test_results, created_at
+		FROM judge_submissions
+		WHERE id = ?
+	`
+
+	var s models.JudgeSubmission
+	var testResults []byte
+	var problemVersion sql.NullInt64
+	err := r.db.QueryRow(query, id).Scan(
+		&s.ID, &s.UserUID, &s.TopicKey, &s.ProblemID, &problemVersion, &s.Code, &s.Verdict, &s.Feedback, &s.PromptVersion,
+		&testResults, &s.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	}
+
+	if err := unmarshalTestResults(testResults, &s.TestResults); err != nil {
+		return nil, err
+	}
+	if problemVersion.Valid {
+		version := int(problemVersion.Int64)
+		s.ProblemVersion = &version
+	}
+
+	return &s, nil
+}
+
+// UpdateVerdict overwrites the verdict of a submission (mentor override)
+func (r *SubmissionRepository) UpdateVerdict(id int64, verdict string) error {
+	query := `UPDATE judge_submissions SET verdict = ? WHERE id = ?`
+
+	_, err := r.db.Exec(query, verdict, id)
+	if err != nil {
+		return fmt.Errorf("failed to update submission verdict: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecentCode returns the code of a user's most recent judge
+// submissions, newest first
+func (r *SubmissionRepository) ListRecentCode(firebaseUID string, limit int) ([]string, error) {
+	query := `
+		SELECT code
+		FROM judge_submissions
+		WHERE user_uid = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.Query(query, firebaseUID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent submissions: %w", err)
+	}
+	defer rows.Close()
+
+	var code []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, fmt.Errorf("failed to scan submission code: %w", err)
+		}
+		code = append(code, c)
+	}
+
+	return code, rows.Err()
+}
+
+// marshalTestResults encodes test results as JSON, or NULL when there are none
+func marshalTestResults(results []models.TestResult) ([]byte, error) {
+	if len(results) == 0 {
+		return nil, nil
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal test_results: %w", err)
+	}
+	return data, nil
+}
+
+func unmarshalTestResults(data []byte, results *[]models.TestResult) error {
+	if len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, results); err != nil {
+		return fmt.Errorf("failed to unmarshal test_results: %w", err)
+	}
+	return nil
+}
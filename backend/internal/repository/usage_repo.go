@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+type UsageRepository struct {
+	db *sql.DB
+}
+
+func NewUsageRepository(db *sql.DB) *UsageRepository {
+	return &UsageRepository{db: db}
+}
+
+// Add accumulates one request's token usage into the user's daily row
+func (r *UsageRepository) Add(firebaseUID string, day time.Time, promptTokens, completionTokens, totalTokens int) error {
+	query := `
+		INSERT INTO llm_usage (user_uid, usage_date, requests, prompt_tokens, completion_tokens, total_tokens)
+		VALUES (?, ?, 1, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			requests = requests + 1,
+			prompt_tokens = prompt_tokens + VALUES(prompt_tokens),
+			completion_tokens = completion_tokens + VALUES(completion_tokens),
+			total_tokens = total_tokens + VALUES(total_tokens)
+	`
+
+	_, err := r.db.Exec(query, firebaseUID, day.Format("2006-01-02"), promptTokens, completionTokens, totalTokens)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+
+	return nil
+}
+
+// GetForDay returns the user's usage on the given day (zero if none)
+func (r *UsageRepository) GetForDay(firebaseUID string, day time.Time) (*models.LLMUsage, error) {
+	query := `
+		SELECT requests, prompt_tokens, completion_tokens, total_tokens
+		FROM llm_usage
+		WHERE user_uid = ? AND usage_date = ?
+	`
+
+	usage := &models.LLMUsage{UsageDate: day}
+	err := r.db.QueryRow(query, firebaseUID, day.Format("2006-01-02")).Scan(
+		&usage.Requests, &usage.PromptTokens, &usage.CompletionTokens, &usage.TotalTokens,
+	)
+	if err == sql.ErrNoRows {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	return usage, nil
+}
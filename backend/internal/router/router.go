@@ -2,6 +2,7 @@ package router
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/yourusername/skilltree/internal/handler"
 	"github.com/yourusername/skilltree/internal/middleware"
+	"github.com/yourusername/skilltree/internal/ratelimit"
 )
 
 func NewRouter(
@@ -16,11 +18,29 @@ func NewRouter(
 	masteryHandler *handler.MasteryHandler,
 	aiHandler *handler.AIHandler,
 	checkpointHandler *handler.CheckpointHandler,
+	reviewHandler *handler.ReviewHandler,
+	healthHandler *handler.HealthHandler,
+	promptHandler *handler.PromptHandler,
+	securityHandler *handler.SecurityHandler,
+	similarityHandler *handler.SimilarityHandler,
+	profileHandler *handler.ProfileHandler,
+	achievementHandler *handler.AchievementHandler,
+	leaderboardHandler *handler.LeaderboardHandler,
+	cohortHandler *handler.CohortHandler,
+	curriculumHandler *handler.CurriculumHandler,
+	problemHandler *handler.ProblemHandler,
+	rateLimitStore ratelimit.Store,
+	aiRateLimits map[string]ratelimit.Rule,
+	aiRequestTimeout time.Duration,
+	quotaChecker middleware.QuotaChecker,
 	firebaseAuth *auth.Client,
 	corsMiddleware *cors.Cors) *chi.Mux {
 
 	r := chi.NewRouter()
 
+	// Judge panels, quizzes and sandbox runs outlast the server's write timeout
+	aiRequest := middleware.RequestBudget(aiRequestTimeout)
+
 	// Global middleware
 	r.Use(middleware.LoggingMiddleware)
 	r.Use(corsMiddleware.Handler)
@@ -29,6 +49,7 @@ func NewRouter(
 	r.Get("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("OK"))
 	})
+	r.Get("/health/ai", healthHandler.AI)
 
 	// API routes
 	r.Route("/api", func(r chi.Router) {
@@ -41,16 +62,117 @@ func NewRouter(
 
 			// Mastery endpoints
 			r.Get("/mastery", masteryHandler.GetMastery)
-			r.Put("/mastery/{topicKey}", masteryHandler.UpdateMastery)
+
+			// Profile endpoints
+			r.Get("/profile/stats", profileHandler.GetStats)
+			r.Get("/profile/settings", profileHandler.GetSettings)
+			r.Put("/profile/settings", profileHandler.UpdateSettings)
+			r.Get("/profile/achievements", achievementHandler.ListAchievements)
+
+			// Leaderboard endpoints
+			r.Get("/leaderboards/global", leaderboardHandler.GetGlobal)
+			r.Get("/leaderboards/weekly", leaderboardHandler.GetWeekly)
+			r.Get("/leaderboards/topics/{topicKey}", leaderboardHandler.GetTopic)
+
+			// Cohort endpoints (membership and instructor checks are per cohort)
+			r.With(middleware.RequireRole(middleware.RoleInstructor)).Post("/cohorts", cohortHandler.CreateCohort)
+			r.Get("/cohorts", cohortHandler.ListCohorts)
+			r.Post("/cohorts/join", cohortHandler.JoinCohort)
+			r.Get("/cohorts/{cohortID}", cohortHandler.GetCohort)
+			r.Get("/cohorts/{cohortID}/members", cohortHandler.ListMembers)
+			r.Get("/cohorts/{cohortID}/mastery", cohortHandler.GetMastery)
+			r.Get("/cohorts/{cohortID}/checkpoints", cohortHandler.GetCheckpoints)
+			r.Get("/cohorts/{cohortID}/submissions", cohortHandler.ListSubmissions)
+			r.Get("/cohorts/{cohortID}/assignments", cohortHandler.ListAssignments)
+			r.Post("/cohorts/{cohortID}/assignments", cohortHandler.CreateAssignment)
+			r.Delete("/cohorts/{cohortID}/assignments/{assignmentID}", cohortHandler.DeleteAssignment)
+			r.Get("/cohorts/{cohortID}/leaderboard", cohortHandler.GetLeaderboard)
+			r.Put("/cohorts/{cohortID}/curriculum", cohortHandler.SetCurriculum)
+
+			// Curriculum endpoints
+			r.Get("/curricula", curriculumHandler.ListCurricula)
+			r.Get("/curriculum", curriculumHandler.GetActive)
 
 			// Checkpoint endpoints
 			r.Get("/checkpoints", checkpointHandler.GetCheckpoints)
-			r.Post("/checkpoints/attempt", checkpointHandler.AttemptCheckpoint)
+			r.Post("/checkpoints/{tier}/start", checkpointHandler.StartSession)
+			r.Put("/checkpoints/sessions/{sessionID}/draft", checkpointHandler.SaveDraft)
+			r.With(aiRequest).Post("/checkpoints/attempt", checkpointHandler.AttemptCheckpoint)
+			r.Get("/checkpoints/attempts/{attemptID}/quiz", checkpointHandler.GetQuiz)
+			r.With(aiRequest).Post("/checkpoints/attempts/{attemptID}/quiz", checkpointHandler.SubmitQuiz)
+
+			// AI endpoints (rate limited per user and route, metered against the daily budget)
+			r.Get("/ai/usage", aiHandler.GetUsage)
+			r.Get("/ai/hints", aiHandler.GetHintUsage)
+			r.Get("/ai/judge/{submissionID}/quiz", aiHandler.GetJudgeQuiz)
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.QuotaMiddleware(quotaChecker))
+				r.Use(aiRequest)
+
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "ai_chat", aiRateLimits["ai_chat"])).
+					Post("/ai/chat", aiHandler.Chat)
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "ai_complexity", aiRateLimits["ai_complexity"])).
+					Post("/ai/complexity", aiHandler.Complexity)
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "ai_judge", aiRateLimits["ai_judge"])).
+					Post("/ai/judge", aiHandler.Judge)
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "ai_judge_quiz", aiRateLimits["ai_judge_quiz"])).
+					Post("/ai/judge/{submissionID}/quiz", aiHandler.SubmitJudgeQuiz)
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "ai_hint", aiRateLimits["ai_hint"])).
+					Post("/ai/hint", aiHandler.Hint)
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "ai_review", aiRateLimits["ai_review"])).
+					Post("/ai/review", aiHandler.Review)
+			})
+
+			// Problem catalog and authoring (submissions run in the sandbox)
+			r.Get("/problems/topics/{topicKey}", problemHandler.ListProblems)
+			r.Get("/problems/authored", problemHandler.ListMine)
+			r.Get("/problems/authored/{problemID}", problemHandler.GetMine)
+			r.Group(func(r chi.Router) {
+				// Submissions run an arbitrary reference solution
+				r.Use(middleware.RequireRole(middleware.RoleAuthor, middleware.RoleMentor))
+				r.Use(aiRequest)
+
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "problem_submit", aiRateLimits["problem_submit"])).
+					Post("/problems/authored", problemHandler.SubmitProblem)
+				r.With(middleware.RateLimitMiddleware(rateLimitStore, "problem_submit", aiRateLimits["problem_submit"])).
+					Post("/problems/authored/{problemID}/versions", problemHandler.ReviseProblem)
+			})
+
+			// Review endpoints
+			r.Post("/reviews/appeals", reviewHandler.Appeal)
+
+			// Mentor endpoints
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(middleware.RoleMentor))
+
+				r.Get("/mentor/reviews", reviewHandler.ListQueue)
+				r.Get("/mentor/reviews/{reviewID}", reviewHandler.GetReview)
+				r.Post("/mentor/reviews/{reviewID}/resolve", reviewHandler.Resolve)
+				r.Get("/mentor/problems", problemHandler.ListQueue)
+				r.Get("/mentor/problems/{versionID}", problemHandler.GetVersion)
+				r.Post("/mentor/problems/{versionID}/moderate", problemHandler.Moderate)
+			})
+
+			// Content author endpoints
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(middleware.RoleAuthor))
+
+				r.Get("/admin/checkpoints/variants", checkpointHandler.GetVariantStats)
+				r.Get("/admin/prompts", promptHandler.ListPrompts)
+				r.Get("/admin/similarity/references", similarityHandler.ListReferences)
+				r.Post("/admin/similarity/references", similarityHandler.CreateReference)
+			})
+
+			// Admin-only endpoints
+			r.Group(func(r chi.Router) {
+				r.Use(middleware.RequireRole(middleware.RoleAdmin))
 
-			// AI endpoints
-			r.Post("/ai/chat", aiHandler.Chat)
-			r.Post("/ai/complexity", aiHandler.Complexity)
-			r.Post("/ai/judge", aiHandler.Judge)
+				r.Get("/admin/security-events", securityHandler.ListEvents)
+				r.With(aiRequest).Post("/admin/problems/generate", problemHandler.GenerateProblem)
+				r.Get("/admin/problems/generated", problemHandler.ListGenerated)
+				r.Get("/admin/problems/generated/{versionID}", problemHandler.GetGenerated)
+				r.Post("/admin/problems/generated/{versionID}/moderate", problemHandler.ModerateGenerated)
+			})
 		})
 	})
 
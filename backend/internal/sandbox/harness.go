@@ -0,0 +1,283 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// Both harnesses silence the program's own output, then call the entry
+// function on generated inputs of increasing size and print one JSON line
+// per size: {"n", "time_ms", "memory_bytes"} or {"error"}. A size whose
+// calls take longer than the per-size budget ends the run early.
+
+var javascriptHarness = template.Must(template.New("javascript").Parse(`'use strict';
+const __emit = process.stdout.write.bind(process.stdout);
+for (const level of ['log', 'info', 'warn', 'error', 'debug', 'trace']) console[level] = () => {};
+
+{{.Code}}
+
+;(() => {
+  const fn = typeof {{.Entry}} === 'function' ? {{.Entry}} : null;
+  if (!fn) {
+    __emit(JSON.stringify({ error: 'function {{.Entry}} is not defined' }) + '\n');
+    return;
+  }
+
+  let seed = 42;
+  const rand = () => {
+    seed = (seed + 0x6d2b79f5) | 0;
+    let t = Math.imul(seed ^ (seed >>> 15), 1 | seed);
+    t = (t + Math.imul(t ^ (t >>> 7), 61 | t)) ^ t;
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+  const make = (kind, n) => {
+    switch (kind) {
+      case 'array':
+        return Array.from({ length: n }, () => Math.floor(rand() * 2 * n) - n);
+      case 'sorted_array':
+        return make('array', n).sort((a, b) => a - b);
+      case 'string':
+        return Array.from({ length: n }, () => String.fromCharCode(97 + Math.floor(rand() * 26))).join('');
+      default:
+        return n;
+    }
+  };
+  const copy = (value) => (Array.isArray(value) ? value.slice() : value);
+
+  try {
+    for (const n of {{.Sizes}}) {
+      const input = make('{{.Input}}', n);
+
+      let arg = copy(input);
+      if (global.gc) global.gc();
+      const before = process.memoryUsage().heapUsed;
+      let result = fn(arg);
+      const memory = Math.max(0, process.memoryUsage().heapUsed - before);
+      result = null;
+
+      let reps = 0;
+      let elapsed = 0n;
+      while (reps < 1 || (elapsed < {{.MinBatchNanos}}n && reps < {{.MaxReps}})) {
+        arg = copy(input);
+        const start = process.hrtime.bigint();
+        result = fn(arg);
+        elapsed += process.hrtime.bigint() - start;
+        reps++;
+      }
+
+      const timeMs = Number(elapsed) / reps / 1e6;
+      __emit(JSON.stringify({ n, time_ms: timeMs, memory_bytes: memory }) + '\n');
+      if (timeMs > {{.SizeBudgetMs}}) break;
+    }
+  } catch (err) {
+    __emit(JSON.stringify({ error: String(err && err.message ? err.message : err) }) + '\n');
+  }
+})();
+`))
+
+var pythonHarness = template.Must(template.New("python").Parse(`import json as __json, os as __os, random as __random, sys as __sys, time as __time, tracemalloc as __tracemalloc
+__emit = __sys.stdout
+__sys.stdout = open(__os.devnull, "w")
+__sys.stderr = __sys.stdout
+
+{{.Code}}
+
+
+def __main():
+    fn = globals().get("{{.Entry}}")
+    if not callable(fn) and "Solution" in globals():
+        fn = getattr(globals()["Solution"](), "{{.Entry}}", None)
+    if not callable(fn):
+        __emit.write(__json.dumps({"error": "function {{.Entry}} is not defined"}) + "\n")
+        return
+
+    rng = __random.Random(42)
+
+    def make(kind, n):
+        if kind == "array":
+            return [rng.randint(-n, n) for _ in range(n)]
+        if kind == "sorted_array":
+            return sorted(make("array", n))
+        if kind == "string":
+            return "".join(chr(97 + rng.randrange(26)) for _ in range(n))
+        return n
+
+    def copy(value):
+        return list(value) if isinstance(value, list) else value
+
+    try:
+        for n in {{.Sizes}}:
+            data = make("{{.Input}}", n)
+
+            arg = copy(data)
+            __tracemalloc.start()
+            result = fn(arg)
+            memory = __tracemalloc.get_traced_memory()[1]
+            __tracemalloc.stop()
+            result = None
+
+            reps = 0
+            elapsed = 0
+            while reps < 1 or (elapsed < {{.MinBatchNanos}} and reps < {{.MaxReps}}):
+                arg = copy(data)
+                start = __time.perf_counter_ns()
+                result = fn(arg)
+                elapsed += __time.perf_counter_ns() - start
+                reps += 1
+
+            time_ms = elapsed / reps / 1e6
+            __emit.write(__json.dumps({"n": n, "time_ms": time_ms, "memory_bytes": memory}) + "\n")
+            __emit.flush()
+            if time_ms > {{.SizeBudgetMs}}:
+                break
+    except BaseException as err:
+        __emit.write(__json.dumps({"error": "%s: %s" % (type(err).__name__, err)}) + "\n")
+
+
+__main()
+`))
+
+// The test harnesses call the entry function once per test case with the
+// case's arguments spread, and print one JSON line per case:
+// {"index", "output", "time_ms"} or {"index", "error"}. A missing entry
+// function prints {"error"} and nothing else.
+
+var javascriptTestHarness = template.Must(template.New("javascript_tests").Parse(`'use strict';
+const __emit = process.stdout.write.bind(process.stdout);
+for (const level of ['log', 'info', 'warn', 'error', 'debug', 'trace']) console[level] = () => {};
+
+{{.Code}}
+
+;(() => {
+  const fn = typeof {{.Entry}} === 'function' ? {{.Entry}} : null;
+  if (!fn) {
+    __emit(JSON.stringify({ error: 'function {{.Entry}} is not defined' }) + '\n');
+    return;
+  }
+
+  const tests = JSON.parse({{.Tests}});
+  for (let index = 0; index < tests.length; index++) {
+    try {
+      const start = process.hrtime.bigint();
+      const result = fn(...tests[index]);
+      const timeMs = Number(process.hrtime.bigint() - start) / 1e6;
+      __emit(JSON.stringify({ index, output: result === undefined ? null : result, time_ms: timeMs }) + '\n');
+    } catch (err) {
+      __emit(JSON.stringify({ index, error: String(err && err.message ? err.message : err) }) + '\n');
+    }
+  }
+})();
+`))
+
+var pythonTestHarness = template.Must(template.New("python_tests").Parse(`import json as __json, os as __os, sys as __sys, time as __time
+__emit = __sys.stdout
+__sys.stdout = open(__os.devnull, "w")
+__sys.stderr = __sys.stdout
+
+{{.Code}}
+
+
+def __main():
+    fn = globals().get("{{.Entry}}")
+    if not callable(fn) and "Solution" in globals():
+        fn = getattr(globals()["Solution"](), "{{.Entry}}", None)
+    if not callable(fn):
+        __emit.write(__json.dumps({"error": "function {{.Entry}} is not defined"}) + "\n")
+        return
+
+    tests = __json.loads({{.Tests}})
+    for index, args in enumerate(tests):
+        try:
+            start = __time.perf_counter_ns()
+            result = fn(*args)
+            time_ms = (__time.perf_counter_ns() - start) / 1e6
+            line = __json.dumps({"index": index, "output": result, "time_ms": time_ms}, allow_nan=False)
+        except BaseException as err:
+            line = __json.dumps({"index": index, "error": "%s: %s" % (type(err).__name__, err)})
+        __emit.write(line + "\n")
+        __emit.flush()
+
+
+__main()
+`))
+
+type testHarnessData struct {
+	Code  string
+	Entry string
+	// Tests is a string literal, valid in both languages, holding the JSON
+	// array of argument lists
+	Tests string
+}
+
+// renderTestHarness wraps the program in the test harness for its language
+func renderTestHarness(program Program, tests []TestCase) (string, error) {
+	harness := javascriptTestHarness
+	if program.Language == Python {
+		harness = pythonTestHarness
+	}
+
+	args := make([]json.RawMessage, len(tests))
+	for i, test := range tests {
+		args[i] = test.Args
+	}
+	argsJSON, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	// A JSON string literal only uses escapes that JavaScript and Python
+	// both understand
+	literal, err := json.Marshal(string(argsJSON))
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = harness.Execute(&b, testHarnessData{
+		Code:  program.Code,
+		Entry: program.Entry,
+		Tests: string(literal),
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+type harnessData struct {
+	Code          string
+	Entry         string
+	Input         string
+	Sizes         string
+	SizeBudgetMs  int64
+	MinBatchNanos int64
+	MaxReps       int
+}
+
+// renderHarness wraps the program in the harness for its language
+func renderHarness(program Program, sizes []int, sizeBudgetMs int64) (string, error) {
+	harness := javascriptHarness
+	if program.Language == Python {
+		harness = pythonHarness
+	}
+
+	sizesJSON, err := json.Marshal(sizes)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	err = harness.Execute(&b, harnessData{
+		Code:          program.Code,
+		Entry:         program.Entry,
+		Input:         program.Input,
+		Sizes:         string(sizesJSON),
+		SizeBudgetMs:  sizeBudgetMs,
+		MinBatchNanos: 5_000_000,
+		MaxReps:       200,
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,165 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"time"
+)
+
+// Isolation modes. Both run the program as Config.UID/GID with no network,
+// its own PID namespace and nothing of the API's environment.
+const (
+	// IsolationNamespaces has the API clone the process straight into new
+	// user, mount, PID, network, IPC and UTS namespaces. The API must run as
+	// root to switch to the sandbox user; the host filesystem stays visible
+	// with that user's permissions, so secrets must not be world-readable.
+	IsolationNamespaces = "namespaces"
+	// IsolationBwrap runs the program under bubblewrap with only /usr and
+	// the system libraries mounted read-only, a private /proc and /tmp and
+	// the working directory. The API must not run as root.
+	IsolationBwrap = "bwrap"
+)
+
+// ErrNotIsolated means the configured isolation is missing or doesn't work
+var ErrNotIsolated = errors.New("sandbox isolation unavailable")
+
+// sandboxDir is where bwrap mounts the working directory
+const sandboxDir = "/sandbox"
+
+// Check runs an empty script under the configured isolation and reports
+// why it can't be used. The API refuses to start a sandbox that fails it.
+func (r *Runner) Check(ctx context.Context) error {
+	if err := r.checkIsolation(); err != nil {
+		return err
+	}
+
+	dir, err := r.workDir()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cmd, _ := r.isolatedCommand(ctx, dir, []string{"/bin/sh", "-c", "exit 0"}, "")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s: %v %s", ErrNotIsolated, r.cfg.Isolation, err, output)
+	}
+	return nil
+}
+
+// checkIsolation rejects configurations that would run the program with
+// the API's privileges
+func (r *Runner) checkIsolation() error {
+	switch r.cfg.Isolation {
+	case IsolationNamespaces:
+		if !namespacesSupported {
+			return fmt.Errorf("%w: namespaces isolation needs Linux", ErrNotIsolated)
+		}
+		if os.Geteuid() != 0 {
+			return fmt.Errorf("%w: namespaces isolation needs the API to run as root; use bwrap otherwise", ErrNotIsolated)
+		}
+	case IsolationBwrap:
+		// Running as root, bwrap would map the sandbox user to root
+		if os.Geteuid() == 0 {
+			return fmt.Errorf("%w: bwrap isolation must not run as root; use namespaces", ErrNotIsolated)
+		}
+		if _, err := exec.LookPath(r.cfg.BwrapPath); err != nil {
+			return fmt.Errorf("%w: %v", ErrNotIsolated, err)
+		}
+	case "":
+		return fmt.Errorf("%w: set SANDBOX_ISOLATION to %s or %s", ErrNotIsolated, IsolationNamespaces, IsolationBwrap)
+	default:
+		return fmt.Errorf("%w: unknown isolation %q", ErrNotIsolated, r.cfg.Isolation)
+	}
+
+	if r.cfg.UID <= 0 || r.cfg.GID <= 0 || r.cfg.UID == os.Getuid() {
+		return fmt.Errorf("%w: the sandbox needs its own non-root uid and gid", ErrNotIsolated)
+	}
+	return nil
+}
+
+// workDir creates the throwaway working directory, owned by the sandbox
+// user when the API switches to it
+func (r *Runner) workDir() (string, error) {
+	dir, err := os.MkdirTemp("", "sandbox-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create sandbox dir: %w", err)
+	}
+	if r.cfg.Isolation == IsolationNamespaces {
+		if err := os.Chown(dir, r.cfg.UID, r.cfg.GID); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to hand sandbox dir to the sandbox user: %w", err)
+		}
+	}
+	return dir, nil
+}
+
+// writeFile writes the program so only the sandbox user can read it
+func (r *Runner) writeFile(name, source string) error {
+	if err := os.WriteFile(name, []byte(source), 0o600); err != nil {
+		return fmt.Errorf("failed to write program: %w", err)
+	}
+	if r.cfg.Isolation == IsolationNamespaces {
+		if err := os.Chown(name, r.cfg.UID, r.cfg.GID); err != nil {
+			return fmt.Errorf("failed to hand program to the sandbox user: %w", err)
+		}
+	}
+	return nil
+}
+
+// isolatedCommand runs argv with file (relative to dir, empty for none)
+// appended as the program sees it. It returns the command and where dir
+// appears inside the sandbox.
+func (r *Runner) isolatedCommand(ctx context.Context, dir string, argv []string, file string) (*exec.Cmd, string) {
+	inside := dir
+	if r.cfg.Isolation == IsolationBwrap {
+		inside = sandboxDir
+	}
+	if file != "" {
+		argv = append(argv, path.Join(inside, file))
+	}
+
+	var cmd *exec.Cmd
+	if r.cfg.Isolation == IsolationBwrap {
+		cmd = exec.CommandContext(ctx, r.cfg.BwrapPath, append(r.bwrapArgs(dir), argv...)...)
+		cmd.SysProcAttr = sysProcAttr(false, 0, 0)
+	} else {
+		cmd = exec.CommandContext(ctx, argv[0], argv[1:]...)
+		cmd.Dir = dir
+		cmd.SysProcAttr = sysProcAttr(true, r.cfg.UID, r.cfg.GID)
+	}
+	cmd.Env = []string{"PATH=/usr/local/bin:/usr/bin:/bin", "HOME=" + inside, "LANG=C.UTF-8"}
+	cmd.Cancel = func() error { return killGroup(cmd) }
+	cmd.WaitDelay = time.Second
+
+	return cmd, inside
+}
+
+// bwrapArgs mounts the interpreters read-only and dir read-write at
+// sandboxDir in fresh namespaces, then ends bwrap's own flags
+func (r *Runner) bwrapArgs(dir string) []string {
+	args := []string{
+		"--unshare-all", "--unshare-user",
+		"--uid", strconv.Itoa(r.cfg.UID), "--gid", strconv.Itoa(r.cfg.GID),
+		"--die-with-parent", "--new-session",
+		"--ro-bind", "/usr", "/usr",
+	}
+	for _, system := range []string{"/bin", "/lib", "/lib64", "/etc/alternatives", "/etc/ld.so.cache"} {
+		args = append(args, "--ro-bind-try", system, system)
+	}
+	return append(args,
+		"--proc", "/proc",
+		"--dev", "/dev",
+		"--tmpfs", "/tmp",
+		"--bind", dir, sandboxDir,
+		"--chdir", sandboxDir,
+		"--",
+	)
+}
@@ -0,0 +1,36 @@
+package sandbox
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// namespacesSupported reports whether IsolationNamespaces can run here
+const namespacesSupported = true
+
+// sysProcAttr puts the sandbox in its own process group so a timeout kills
+// every process it spawned, and kills it if the API dies. With namespaces
+// the process also runs as uid and gid in new user, mount, PID, network,
+// IPC and UTS namespaces. It is PID 1 of its namespace, so when it exits
+// the kernel kills everything it started, even processes that called setsid.
+func sysProcAttr(namespaces bool, uid, gid int) *syscall.SysProcAttr {
+	attr := &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	if namespaces {
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: uid, HostID: uid, Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: gid, HostID: gid, Size: 1}}
+		attr.Credential = &syscall.Credential{Uid: uint32(uid), Gid: uint32(gid), NoSetGroups: true}
+	}
+	return attr
+}
+
+func killGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
+
+// killedExitCode reports an exit status of 128 + SIGKILL or SIGXCPU, which
+// is how bwrap reports a program the CPU limit killed
+func killedExitCode(code int) bool {
+	return code == 128+int(syscall.SIGKILL) || code == 128+int(syscall.SIGXCPU)
+}
@@ -0,0 +1,24 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// namespacesSupported reports whether IsolationNamespaces can run here
+const namespacesSupported = false
+
+// sysProcAttr has no process group or namespace support off Linux
+func sysProcAttr(namespaces bool, uid, gid int) *syscall.SysProcAttr {
+	return nil
+}
+
+func killGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
+
+func killedExitCode(code int) bool {
+	return false
+}
@@ -0,0 +1,371 @@
+// Package sandbox runs untrusted code in a separate, resource-limited
+// interpreter process, either to measure how its runtime and memory grow
+// with input size or to check it against test cases.
+//
+// Every run gets a CPU time limit, a memory limit, a file size limit, a wall
+// clock timeout, an empty environment and a throwaway working directory. It
+// runs as a dedicated unprivileged user without network access, isolated by
+// IsolationNamespaces or IsolationBwrap. A runner without a working
+// isolation mode refuses to run anything.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported languages
+const (
+	JavaScript = "javascript"
+	Python     = "python"
+)
+
+// Input kinds the harness can generate; the entry function receives one
+// argument of size n
+const (
+	InputArray       = "array"
+	InputSortedArray = "sorted_array"
+	InputString      = "string"
+	InputInt         = "int"
+)
+
+var (
+	// ErrUnsupportedLanguage means there is no harness for the language
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+	// ErrInvalidEntry means the entry point is not a plain identifier
+	ErrInvalidEntry = errors.New("invalid entry function name")
+	// ErrInvalidInput means the input kind is unknown
+	ErrInvalidInput = errors.New("invalid input kind")
+	// ErrBusy means every sandbox slot is in use
+	ErrBusy = errors.New("sandbox busy")
+)
+
+// ProgramError is a failure of the learner's program (syntax error,
+// exception, missing entry function) rather than of the sandbox
+type ProgramError struct {
+	Message string
+}
+
+func (e *ProgramError) Error() string {
+	return "program failed: " + e.Message
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]{0,63}$`)
+
+// Program is a submission to profile or test
+type Program struct {
+	Language string
+	Code     string
+	// Entry is the function called with each generated input
+	Entry string
+	// Input is the kind of input generated for the entry function when
+	// profiling
+	Input string
+}
+
+// Sample is the cost of one call at input size N
+type Sample struct {
+	N           int     `json:"n"`
+	TimeMs      float64 `json:"time_ms"`
+	MemoryBytes int64   `json:"memory_bytes"`
+}
+
+// Result holds the samples collected before the program finished or was
+// stopped
+type Result struct {
+	Samples []Sample
+	// TimedOut is set when the wall clock or CPU limit ended the run
+	TimedOut bool
+}
+
+type Config struct {
+	NodePath   string
+	PythonPath string
+	// Timeout bounds the whole run, including interpreter start-up
+	Timeout  time.Duration
+	MemoryMB int
+	// MaxInputSize is the largest n generated; sizes double up to it
+	MaxInputSize int
+	// MaxConcurrent bounds how many sandboxes run at once
+	MaxConcurrent int
+	// MaxProcesses bounds the processes and threads one run can have, so a
+	// fork bomb stops there instead of exhausting the host's PIDs
+	MaxProcesses int
+	// Isolation is IsolationNamespaces or IsolationBwrap
+	Isolation string
+	// UID and GID the program runs as. Use an account nothing else runs as.
+	UID int
+	GID int
+	// BwrapPath is the bubblewrap binary used by IsolationBwrap
+	BwrapPath string
+}
+
+// Runner profiles and tests programs in sandboxed interpreter processes
+type Runner struct {
+	cfg   Config
+	slots chan struct{}
+}
+
+func NewRunner(cfg Config) *Runner {
+	if cfg.MaxConcurrent < 1 {
+		cfg.MaxConcurrent = 1
+	}
+	if cfg.MaxProcesses < 1 {
+		cfg.MaxProcesses = defaultMaxProcesses
+	}
+	return &Runner{
+		cfg:   cfg,
+		slots: make(chan struct{}, cfg.MaxConcurrent),
+	}
+}
+
+const (
+	minInputSize = 256
+	outputLimit  = 1 << 20
+	stderrLimit  = 2048
+	// defaultMaxProcesses leaves room for Node's worker threads
+	defaultMaxProcesses = 64
+)
+
+// Profile runs the program on inputs of doubling size and returns the
+// samples it produced. A program that times out is not an error: the
+// samples gathered so far are returned with TimedOut set.
+func (r *Runner) Profile(ctx context.Context, program Program) (*Result, error) {
+	if err := validate(&program); err != nil {
+		return nil, err
+	}
+
+	release, err := r.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	sizes := InputSizes(r.cfg.MaxInputSize)
+	// Stop growing n once one call uses a twentieth of the time budget, so
+	// the next size of a quadratic or cubic program doesn't hit the limit
+	sizeBudgetMs := r.cfg.Timeout.Milliseconds() / 20
+	source, err := renderHarness(program, sizes, sizeBudgetMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render harness: %w", err)
+	}
+
+	run, err := r.execute(ctx, program.Language, source)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for _, line := range run.lines() {
+		var out struct {
+			Sample
+			Error *string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(line), &out); err != nil {
+			continue
+		}
+		if out.Error != nil {
+			return nil, &ProgramError{Message: run.relative(*out.Error)}
+		}
+		result.Samples = append(result.Samples, out.Sample)
+	}
+
+	if run.timedOut {
+		result.TimedOut = true
+		return result, nil
+	}
+	if run.err != nil && len(result.Samples) == 0 {
+		return nil, run.programError()
+	}
+
+	return result, nil
+}
+
+// acquire takes a sandbox slot; the caller must call release when done
+func (r *Runner) acquire() (release func(), err error) {
+	select {
+	case r.slots <- struct{}{}:
+		return func() { <-r.slots }, nil
+	default:
+		return nil, ErrBusy
+	}
+}
+
+// execution is the captured output of one sandboxed process
+type execution struct {
+	// dir is the working directory as the program saw it
+	dir      string
+	stdout   string
+	stderr   string
+	err      error
+	timedOut bool
+}
+
+// lines returns the non-empty lines the harness printed
+func (e *execution) lines() []string {
+	var lines []string
+	for _, line := range strings.Split(e.stdout, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// relative strips the sandbox directory from paths in a message
+func (e *execution) relative(message string) string {
+	return strings.ReplaceAll(message, e.dir+"/", "")
+}
+
+// programError describes a process that exited without reporting anything
+func (e *execution) programError() *ProgramError {
+	message := strings.TrimSpace(e.relative(e.stderr))
+	if message == "" {
+		message = e.err.Error()
+	}
+	return &ProgramError{Message: message}
+}
+
+// execute writes source to a throwaway directory and runs it under the
+// interpreter for language with the configured limits and isolation
+func (r *Runner) execute(ctx context.Context, language, source string) (*execution, error) {
+	if err := r.checkIsolation(); err != nil {
+		return nil, err
+	}
+
+	dir, err := r.workDir()
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	interpreter, args, file := r.command(language)
+	if err := r.writeFile(filepath.Join(dir, file), source); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+
+	// The shell applies the rlimits, then execs the interpreter
+	cmd, inside := r.isolatedCommand(ctx, dir, append([]string{"/bin/sh", "-c", r.limits(language), interpreter}, args...), file)
+
+	stdout := &limitedBuffer{limit: outputLimit}
+	stderr := &limitedBuffer{limit: stderrLimit}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	runErr := cmd.Run()
+
+	return &execution{
+		dir:      inside,
+		stdout:   stdout.String(),
+		stderr:   stderr.String(),
+		err:      runErr,
+		timedOut: ctx.Err() == context.DeadlineExceeded || cpuLimited(runErr),
+	}, nil
+}
+
+// InputSizes returns the doubling input sizes used for a maximum size
+func InputSizes(maxSize int) []int {
+	var sizes []int
+	for n := minInputSize; n <= maxSize; n *= 2 {
+		sizes = append(sizes, n)
+	}
+	return sizes
+}
+
+func validate(program *Program) error {
+	switch program.Language {
+	case JavaScript, Python:
+	default:
+		return ErrUnsupportedLanguage
+	}
+
+	if program.Entry == "" {
+		program.Entry = "solution"
+	}
+	if !identifierPattern.MatchString(program.Entry) || (program.Language == Python && strings.Contains(program.Entry, "$")) {
+		return ErrInvalidEntry
+	}
+
+	switch program.Input {
+	case "":
+		program.Input = InputArray
+	case InputArray, InputSortedArray, InputString, InputInt:
+	default:
+		return ErrInvalidInput
+	}
+
+	return nil
+}
+
+// command returns the interpreter, its flags and the source file name
+func (r *Runner) command(language string) (string, []string, string) {
+	if language == Python {
+		return r.cfg.PythonPath, []string{"-I"}, "main.py"
+	}
+	return r.cfg.NodePath, []string{
+		"--max-old-space-size=" + strconv.Itoa(r.cfg.MemoryMB),
+		"--expose-gc",
+		"--disallow-code-generation-from-strings",
+	}, "main.js"
+}
+
+// limits is the shell prologue that sets rlimits before exec. V8 reserves
+// far more address space than it uses, so Node's heap is capped with
+// --max-old-space-size instead of ulimit -v. The process limit is -u in
+// bash and -p in dash; the run is refused if neither works. Each run has
+// its own user namespace, so the limit counts only the run's processes.
+func (r *Runner) limits(language string) string {
+	cpuSeconds := int(r.cfg.Timeout.Seconds()) + 1
+	script := fmt.Sprintf("ulimit -t %d; ulimit -f 1024; ", cpuSeconds)
+	script += fmt.Sprintf("{ ulimit -u %[1]d || ulimit -p %[1]d; } 2>/dev/null || exit 126; ", r.cfg.MaxProcesses)
+	if language == Python {
+		script += fmt.Sprintf("ulimit -v %d; ", r.cfg.MemoryMB*1024)
+	}
+	return script + `exec "$0" "$@"`
+}
+
+// cpuLimited reports whether the process was killed for exceeding its CPU
+// time limit (SIGXCPU or SIGKILL after the hard limit)
+func cpuLimited(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	status := exitErr.String()
+	return strings.Contains(status, "CPU time limit") || strings.Contains(status, "killed") ||
+		killedExitCode(exitErr.ExitCode())
+}
+
+// limitedBuffer keeps the first limit bytes written and discards the rest
+type limitedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
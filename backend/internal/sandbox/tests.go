@@ -0,0 +1,172 @@
+package sandbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MaxTestCases bounds how many test cases one run may have
+const MaxTestCases = 50
+
+// ErrInvalidTestCase means a test case's arguments are not a JSON array or
+// its expected value is not JSON
+var ErrInvalidTestCase = errors.New("invalid test case")
+
+// TestCase is one call of the entry function
+type TestCase struct {
+	// Args is a JSON array of the arguments passed to the entry function
+	Args json.RawMessage `json:"args"`
+	// Expected is the JSON value the call must return
+	Expected json.RawMessage `json:"expected"`
+}
+
+// TestResult is the outcome of one test case
+type TestResult struct {
+	Passed bool `json:"passed"`
+	// Output is the JSON the call returned; Error is set instead when it
+	// threw or returned something that isn't JSON
+	Output json.RawMessage `json:"output,omitempty"`
+	Error  string          `json:"error,omitempty"`
+	TimeMs float64         `json:"time_ms"`
+}
+
+// TestReport holds one result per test case, in order. Cases that never ran
+// because the run timed out are reported as failed.
+type TestReport struct {
+	Results  []TestResult `json:"results"`
+	TimedOut bool         `json:"timed_out"`
+}
+
+// Passed reports whether every test case passed
+func (r *TestReport) Passed() bool {
+	if r.TimedOut {
+		return false
+	}
+	for _, result := range r.Results {
+		if !result.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// RunTests calls the program's entry function on each test case and
+// compares what it returns with the expected value. Numbers are compared
+// with a small relative tolerance; everything else must match exactly.
+func (r *Runner) RunTests(ctx context.Context, program Program, tests []TestCase) (*TestReport, error) {
+	if err := validate(&program); err != nil {
+		return nil, err
+	}
+	if len(tests) == 0 || len(tests) > MaxTestCases {
+		return nil, fmt.Errorf("%w: need 1 to %d test cases", ErrInvalidTestCase, MaxTestCases)
+	}
+	expected := make([]any, len(tests))
+	for i, test := range tests {
+		var args []json.RawMessage
+		if err := json.Unmarshal(test.Args, &args); err != nil {
+			return nil, fmt.Errorf("%w: test %d: args must be a JSON array", ErrInvalidTestCase, i+1)
+		}
+		if err := json.Unmarshal(test.Expected, &expected[i]); err != nil {
+			return nil, fmt.Errorf("%w: test %d: expected must be JSON", ErrInvalidTestCase, i+1)
+		}
+	}
+
+	release, err := r.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	source, err := renderTestHarness(program, tests)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render harness: %w", err)
+	}
+
+	run, err := r.execute(ctx, program.Language, source)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TestReport{Results: make([]TestResult, len(tests))}
+	ran := 0
+	for _, line := range run.lines() {
+		var out struct {
+			Index  *int            `json:"index"`
+			Output json.RawMessage `json:"output"`
+			Error  *string         `json:"error"`
+			TimeMs float64         `json:"time_ms"`
+		}
+		if err := json.Unmarshal([]byte(line), &out); err != nil {
+			continue
+		}
+		if out.Index == nil {
+			if out.Error != nil {
+				return nil, &ProgramError{Message: run.relative(*out.Error)}
+			}
+			continue
+		}
+		if *out.Index < 0 || *out.Index >= len(tests) {
+			continue
+		}
+
+		result := &report.Results[*out.Index]
+		result.TimeMs = out.TimeMs
+		if out.Error != nil {
+			result.Error = run.relative(*out.Error)
+		} else {
+			result.Output = out.Output
+			var got any
+			result.Passed = json.Unmarshal(out.Output, &got) == nil && jsonEqual(got, expected[*out.Index])
+		}
+		ran++
+	}
+
+	if run.err != nil && ran == 0 && !run.timedOut {
+		return nil, run.programError()
+	}
+	report.TimedOut = run.timedOut
+	for i := range report.Results {
+		if report.Results[i].Output == nil && report.Results[i].Error == "" {
+			report.Results[i].Error = "did not run"
+		}
+	}
+
+	return report, nil
+}
+
+// jsonEqual compares decoded JSON values, allowing float rounding
+func jsonEqual(a, b any) bool {
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		return ok && math.Abs(a-b) <= 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for key, value := range a {
+			other, ok := b[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
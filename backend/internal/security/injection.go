@@ -0,0 +1,158 @@
+// Package security holds defenses around untrusted learner submissions.
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// Finding is a suspected prompt injection inside a submission
+type Finding struct {
+	// Location is "comment" or "string"
+	Location string `json:"location"`
+	Line     int    `json:"line"`
+	Rule     string `json:"rule"`
+	Excerpt  string `json:"excerpt"`
+}
+
+// injectionRules match text that addresses the judge rather than the program
+var injectionRules = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"override_instructions", regexp.MustCompile(`(?i)\b(ignore|disregard|forget|override)\b.{0,40}\b(instructions?|prompts?|rules|directives?|everything above)\b`)},
+	// ADVANCE must be what is being asked for, so "return early if we
+	// can't advance" stays quiet
+	{"verdict_request", regexp.MustCompile(`(?i)\b(output|return|respond|reply|answer|give|print|say|verdict)\b(\s*[:=]|\s+(with|the|a|an|an?\s+verdict|verdict|of|only|just|me|us|this|it|is))*\s*["'(]?advance\b`)},
+	{"grade_request", regexp.MustCompile(`(?i)\b(mark|grade|judge|rate|score|evaluate)\b(\s+(this|it|me|my|the|solution|code|submission|answer|attempt))*\s+(as|with)\s+(an?\s+|full\s+)?(correct|passing|optimal|advance|marks)\b`)},
+	{"verdict_literal", regexp.MustCompile(`(?i)"?verdict"?\s*[:=]\s*"?\s*advance`)},
+	// Playing a role only counts when the role is the judge's, so "act as
+	// a queue" stays quiet
+	{"role_play", regexp.MustCompile(`(?i)\b((you are (now )?|act as |pretend to be )(a |an |the )?(\w+ ){0,2}(judge|grader|reviewer|examiner|teacher|assistant|ai|llm|model)|new instructions|system prompt)\b`)},
+	{"judge_address", regexp.MustCompile(`(?i)\b(ai|llm|judge|auditor|assistant|grader)\s*[:,]\s*(please|you|ignore|output|this)\b`)},
+}
+
+// ScanSubmission looks for judge-directed instructions in the comments and
+// string literals of a submission. It is a heuristic: findings hold a
+// passing verdict for review rather than rejecting outright.
+func ScanSubmission(code string) []Finding {
+	var findings []Finding
+	for _, segment := range extractText(code) {
+		for _, rule := range injectionRules {
+			if match := rule.pattern.FindString(segment.text); match != "" {
+				findings = append(findings, Finding{
+					Location: segment.location,
+					Line:     segment.line,
+					Rule:     rule.name,
+					Excerpt:  excerpt(segment.text),
+				})
+				break // One finding per segment is enough
+			}
+		}
+	}
+	return findings
+}
+
+// CheckVerdict cross-checks a passing verdict against the evidence and
+// returns the reasons it should not be trusted (none if it stands)
+func CheckVerdict(passed bool, findings []Finding, testResults []models.TestResult) []string {
+	if !passed {
+		return nil
+	}
+
+	var reasons []string
+	if len(findings) > 0 {
+		reasons = append(reasons, EventInjectionSuspected)
+	}
+	for _, result := range testResults {
+		if !result.Passed {
+			reasons = append(reasons, EventVerdictTestMismatch)
+			break
+		}
+	}
+	return reasons
+}
+
+// Security event types
+const (
+	EventInjectionSuspected  = "prompt_injection_suspected"
+	EventVerdictTestMismatch = "verdict_test_mismatch"
+	EventSimilarityMatch     = "similarity_match"
+)
+
+// Boundary returns a marker for fencing the submission inside a prompt. It is
+// derived from the code itself, so a submission cannot contain its own
+// closing marker, and identical code yields identical prompts.
+func Boundary(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return "SUBMISSION-" + hex.EncodeToString(sum[:8])
+}
+
+type textSegment struct {
+	location string
+	line     int
+	text     string
+}
+
+// extractText pulls comments (//, #, /* */) and string literals (”, "", “)
+// out of C-like, JavaScript and Python source. It doesn't need to be a real
+// lexer: the judge still sees the whole submission.
+func extractText(code string) []textSegment {
+	var segments []textSegment
+	line := 1
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		switch {
+		case c == '\n':
+			line++
+		case c == '/' && i+1 < len(code) && code[i+1] == '/', c == '#':
+			start := i
+			for i < len(code) && code[i] != '\n' {
+				i++
+			}
+			segments = append(segments, textSegment{"comment", line, code[start:i]})
+			i-- // Let the loop count the newline
+		case c == '/' && i+1 < len(code) && code[i+1] == '*':
+			start, startLine := i, line
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				i = len(code)
+			} else {
+				i += 2 + end + 1
+			}
+			text := code[start:min(i+1, len(code))]
+			segments = append(segments, textSegment{"comment", startLine, text})
+			line += strings.Count(text, "\n")
+		case c == '"' || c == '\'' || c == '`':
+			start, startLine := i, line
+			for i++; i < len(code) && code[i] != c; i++ {
+				if code[i] == '\\' {
+					i++
+					if i < len(code) && code[i] == '\n' {
+						line++ // Line continuation
+					}
+				} else if code[i] == '\n' {
+					line++
+					if c != '`' && c != '"' {
+						break // Unterminated single-quoted string
+					}
+				}
+			}
+			segments = append(segments, textSegment{"string", startLine, code[start:min(i+1, len(code))]})
+		}
+	}
+	return segments
+}
+
+// excerpt trims a segment for logging
+func excerpt(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if len(text) > 120 {
+		return text[:120] + "..."
+	}
+	return text
+}
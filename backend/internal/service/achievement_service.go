@@ -0,0 +1,227 @@
+package service
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/yourusername/skilltree/internal/achievements"
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/events"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+// AchievementService evaluates the badge rules on domain events and awards
+// the badges they earn
+type AchievementService struct {
+	achievementRepo *repository.AchievementRepository
+	progressRepo    *repository.ProgressRepository
+	checkpointRepo  *repository.CheckpointRepository
+	masteryService  *MasteryService
+	problems        *ProblemService
+	rules           []achievements.Rule
+}
+
+func NewAchievementService(
+	achievementRepo *repository.AchievementRepository,
+	progressRepo *repository.ProgressRepository,
+	checkpointRepo *repository.CheckpointRepository,
+	masteryService *MasteryService,
+	rules []achievements.Rule,
+) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		progressRepo:    progressRepo,
+		checkpointRepo:  checkpointRepo,
+		masteryService:  masteryService,
+		rules:           rules,
+	}
+}
+
+// SetProblemService makes topic-completion badges count published authored
+// problems
+func (s *AchievementService) SetProblemService(problems *ProblemService) {
+	s.problems = problems
+}
+
+// Handle awards every badge the event earns that the user doesn't have yet.
+// It must run after ProgressService so streak and XP facts include the event.
+func (s *AchievementService) Handle(event events.Event) error {
+	var earned map[string]bool
+	facts := &userFacts{service: s, firebaseUID: event.UserUID}
+
+	for _, rule := range s.rules {
+		if !rule.Triggers(event.Type) {
+			continue
+		}
+		if earned == nil {
+			owned, err := s.achievementRepo.ListByUser(event.UserUID)
+			if err != nil {
+				return err
+			}
+			earned = make(map[string]bool, len(owned))
+			for _, a := range owned {
+				earned[a.AchievementKey] = true
+			}
+		}
+		if earned[rule.Key] {
+			continue
+		}
+
+		ok, err := rule.When(event, facts)
+		if err != nil {
+			return fmt.Errorf("failed to evaluate achievement %s: %w", rule.Key, err)
+		}
+		if !ok {
+			continue
+		}
+
+		awarded, err := s.achievementRepo.Award(&models.UserAchievement{
+			UserUID:        event.UserUID,
+			AchievementKey: rule.Key,
+			SubjectType:    event.SubjectType,
+			SubjectID:      event.SubjectID,
+		})
+		if err != nil {
+			return err
+		}
+		if awarded {
+			log.Printf("Achievement %s awarded to user %s", rule.Key, event.UserUID)
+		}
+		earned[rule.Key] = true
+	}
+
+	return nil
+}
+
+// List returns the badge catalog with the user's earned badges marked
+func (s *AchievementService) List(firebaseUID string) ([]models.Achievement, error) {
+	owned, err := s.achievementRepo.ListByUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	awardedAt := make(map[string]models.UserAchievement, len(owned))
+	for _, a := range owned {
+		awardedAt[a.AchievementKey] = a
+	}
+
+	list := make([]models.Achievement, 0, len(s.rules))
+	for _, rule := range s.rules {
+		a := models.Achievement{
+			Key:         rule.Key,
+			Title:       rule.Title,
+			Description: rule.Description,
+		}
+		if owned, ok := awardedAt[rule.Key]; ok {
+			a.Earned = true
+			a.AwardedAt = &owned.AwardedAt
+		}
+		list = append(list, a)
+	}
+
+	return list, nil
+}
+
+// userFacts loads the facts rules ask for lazily, once per event
+type userFacts struct {
+	service     *AchievementService
+	firebaseUID string
+	mastery     *models.MasteryResponse
+	progress    *models.UserProgress
+	checkpoints *int
+}
+
+func (f *userFacts) loadMastery() (*models.MasteryResponse, error) {
+	if f.mastery == nil {
+		mastery, err := f.service.masteryService.GetMasteryByFirebaseUID(f.firebaseUID)
+		if err != nil {
+			return nil, err
+		}
+		f.mastery = mastery
+	}
+	return f.mastery, nil
+}
+
+func (f *userFacts) loadProgress() (*models.UserProgress, error) {
+	if f.progress == nil {
+		progress, err := f.service.progressRepo.Get(f.firebaseUID)
+		if err != nil {
+			return nil, err
+		}
+		f.progress = progress
+	}
+	return f.progress, nil
+}
+
+func (f *userFacts) SolvedProblems(topicKey string) ([]string, error) {
+	mastery, err := f.loadMastery()
+	if err != nil {
+		return nil, err
+	}
+	return mastery.Mastery[topicKey].Solved, nil
+}
+
+func (f *userFacts) TopicProblems(topicKey string) ([]string, error) {
+	if f.service.problems == nil {
+		var ids []string
+		for _, p := range data.ProblemsDB[topicKey] {
+			ids = append(ids, p.ID)
+		}
+		return ids, nil
+	}
+
+	catalog, err := f.service.problems.List(topicKey)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(catalog))
+	for _, p := range catalog {
+		ids = append(ids, p.ID)
+	}
+	return ids, nil
+}
+
+func (f *userFacts) TotalSolved() (int, error) {
+	mastery, err := f.loadMastery()
+	if err != nil {
+		return 0, err
+	}
+	total := 0
+	for _, m := range mastery.Mastery {
+		total += len(m.Solved)
+	}
+	return total, nil
+}
+
+func (f *userFacts) CurrentStreak() (int, error) {
+	progress, err := f.loadProgress()
+	if err != nil {
+		return 0, err
+	}
+	return progress.CurrentStreak, nil
+}
+
+func (f *userFacts) TotalXP() (int, error) {
+	progress, err := f.loadProgress()
+	if err != nil {
+		return 0, err
+	}
+	return progress.TotalXP, nil
+}
+
+func (f *userFacts) PassedCheckpoints() (int, error) {
+	if f.checkpoints == nil {
+		checkpoints, err := f.service.checkpointRepo.GetAllByFirebaseUID(f.firebaseUID)
+		if err != nil {
+			return 0, err
+		}
+		passed := 0
+		for _, c := range checkpoints {
+			if c.IsPassed {
+				passed++
+			}
+		}
+		f.checkpoints = &passed
+	}
+	return *f.checkpoints, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheStore is an optional persistent layer behind the in-memory cache
+type CacheStore interface {
+	Get(key string) (response string, expiresAt time.Time, found bool, err error)
+	Set(key, response string, expiresAt time.Time) error
+	DeleteExpired() error
+}
+
+type cacheEntry struct {
+	key       string
+	response  string
+	expiresAt time.Time
+}
+
+// ResponseCache is a content-addressed cache for deterministic AI calls: an
+// LRU bounded by entry count with a TTL, optionally backed by a CacheStore
+type ResponseCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List // front = most recently used
+	entries    map[string]*list.Element
+	store      CacheStore
+	sets       int
+}
+
+// NewResponseCache creates a cache; store may be nil for memory only
+func NewResponseCache(ttl time.Duration, maxEntries int, store CacheStore) *ResponseCache {
+	return &ResponseCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+		store:      store,
+	}
+}
+
+// CacheKey derives the cache key from everything that determines the output
+func CacheKey(provider, model, promptVersion, code string, inputs ...string) string {
+	codeHash := sha256.Sum256([]byte(normalizeCode(code)))
+
+	h := sha256.New()
+	for _, part := range append([]string{provider, model, promptVersion, hex.EncodeToString(codeHash[:])}, inputs...) {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// normalizeCode ignores differences that don't change the program:
+// line endings, trailing whitespace and surrounding blank lines
+func normalizeCode(code string) string {
+	lines := strings.Split(strings.ReplaceAll(code, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// Get returns a cached response, falling back to the persistent store
+func (c *ResponseCache) Get(key string) (string, bool) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		if now.Before(entry.expiresAt) {
+			c.order.MoveToFront(elem)
+			c.mu.Unlock()
+			return entry.response, true
+		}
+		c.removeElement(elem)
+	}
+	c.mu.Unlock()
+
+	if c.store == nil {
+		return "", false
+	}
+
+	response, expiresAt, found, err := c.store.Get(key)
+	if err != nil {
+		log.Printf("AI cache store lookup failed: %v", err)
+		return "", false
+	}
+	if !found {
+		return "", false
+	}
+
+	c.mu.Lock()
+	c.add(key, response, expiresAt)
+	c.mu.Unlock()
+
+	return response, true
+}
+
+// Set caches a response for the configured TTL
+func (c *ResponseCache) Set(key, response string) {
+	expiresAt := time.Now().Add(c.ttl)
+
+	c.mu.Lock()
+	c.add(key, response, expiresAt)
+	c.sets++
+	prune := c.sets%100 == 0
+	c.mu.Unlock()
+
+	if c.store == nil {
+		return
+	}
+
+	if err := c.store.Set(key, response, expiresAt); err != nil {
+		log.Printf("AI cache store write failed: %v", err)
+	}
+
+	// Periodically clear out expired rows so the table doesn't grow forever
+	if prune {
+		if err := c.store.DeleteExpired(); err != nil {
+			log.Printf("AI cache store prune failed: %v", err)
+		}
+	}
+}
+
+// add inserts or refreshes an entry and evicts the least recently used
+// entries beyond maxEntries. Caller must hold mu.
+func (c *ResponseCache) add(key, response string, expiresAt time.Time) {
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry)
+		entry.response = response
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, response: response, expiresAt: expiresAt})
+
+	for c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// removeElement drops an entry. Caller must hold mu.
+func (c *ResponseCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry).key)
+}
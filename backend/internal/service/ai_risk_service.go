@@ -0,0 +1,206 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+	"github.com/yourusername/skilltree/internal/stylometry"
+)
+
+const (
+	// styleBaselineSize is how many earlier submissions form the style baseline
+	styleBaselineSize = 10
+	// styleBaselineMin is the fewest earlier submissions worth comparing to
+	styleBaselineMin = 3
+	// minCodeLength skips signals on code too short to judge
+	minCodeLength = 80
+)
+
+// AIRiskService scores how likely a checkpoint submission was pasted from an
+// AI assistant. It combines the client's editor telemetry, how long the
+// session took and how far the code's style is from the learner's earlier
+// submissions. No single signal is proof; a high score only asks the
+// learner to explain their code before the pass counts.
+type AIRiskService struct {
+	checkpointRepo *repository.CheckpointRepository
+	submissionRepo *repository.SubmissionRepository
+	// quizThreshold is the score that requires a verification quiz (0 disables)
+	quizThreshold int
+}
+
+func NewAIRiskService(checkpointRepo *repository.CheckpointRepository, submissionRepo *repository.SubmissionRepository, quizThreshold int) *AIRiskService {
+	return &AIRiskService{
+		checkpointRepo: checkpointRepo,
+		submissionRepo: submissionRepo,
+		quizThreshold:  quizThreshold,
+	}
+}
+
+// QuizRequired reports whether the risk is high enough to require a quiz
+func (s *AIRiskService) QuizRequired(risk *models.AIRiskAssessment) bool {
+	return s.quizThreshold > 0 && risk.Score >= s.quizThreshold
+}
+
+// Assess scores a recorded attempt written between startedAt and submittedAt
+func (s *AIRiskService) Assess(attempt *models.CheckpointAttempt, startedAt, submittedAt time.Time) (*models.AIRiskAssessment, error) {
+	risk := &models.AIRiskAssessment{Signals: []models.AIRiskSignal{}}
+	code := attempt.SubmittedCode
+	if len(code) < minCodeLength {
+		return risk, nil
+	}
+
+	if t := attempt.Telemetry; t != nil {
+		addSignal(risk, pasteSignal(t, len(code)))
+		addSignal(risk, typingSignal(t, len(code)))
+	} else {
+		addSignal(risk, &models.AIRiskSignal{Name: "no_telemetry", Detail: "the client sent no editor telemetry"})
+	}
+	addSignal(risk, durationSignal(submittedAt.Sub(startedAt), len(code)))
+
+	baseline, err := s.styleBaseline(attempt.UserUID, attempt.ID)
+	if err != nil {
+		return nil, err
+	}
+	addSignal(risk, styleSignal(code, baseline))
+
+	if risk.Score > 100 {
+		risk.Score = 100
+	}
+	return risk, nil
+}
+
+// addSignal adds a signal's points to the score; nil signals didn't fire
+func addSignal(risk *models.AIRiskAssessment, signal *models.AIRiskSignal) {
+	if signal == nil {
+		return
+	}
+	risk.Score += signal.Points
+	risk.Signals = append(risk.Signals, *signal)
+}
+
+// styleBaseline extracts the style of the learner's most recent earlier
+// checkpoint attempts and practice submissions
+func (s *AIRiskService) styleBaseline(firebaseUID string, attemptID int64) ([]stylometry.Features, error) {
+	attempts, err := s.checkpointRepo.ListRecentCode(firebaseUID, attemptID, styleBaselineSize)
+	if err != nil {
+		return nil, err
+	}
+	submissions, err := s.submissionRepo.ListRecentCode(firebaseUID, styleBaselineSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline []stylometry.Features
+	for _, code := range append(attempts, submissions...) {
+		if len(baseline) == styleBaselineSize {
+			break
+		}
+		if len(code) >= minCodeLength {
+			baseline = append(baseline, stylometry.Extract(code))
+		}
+	}
+	return baseline, nil
+}
+
+// pasteSignal: most of the code arrived in one paste
+func pasteSignal(t *models.EditorTelemetry, codeLength int) *models.AIRiskSignal {
+	share := float64(t.LargestPasteChars) / float64(codeLength)
+	points := 0
+	switch {
+	case share >= 0.8:
+		points = 45
+	case share >= 0.5:
+		points = 30
+	case share >= 0.25:
+		points = 15
+	}
+	if points == 0 {
+		return nil
+	}
+	return &models.AIRiskSignal{
+		Name:   "large_paste",
+		Points: points,
+		Detail: fmt.Sprintf("largest paste was %.0f%% of the submission (%d pastes, %d chars)", share*100, t.PasteEvents, t.PastedChars),
+	}
+}
+
+// typingSignal: far fewer keystrokes than the code needs, or typing faster
+// than a person sustains
+func typingSignal(t *models.EditorTelemetry, codeLength int) *models.AIRiskSignal {
+	typed := float64(t.Keystrokes) / float64(codeLength)
+	switch {
+	case codeLength < 200:
+		// short solutions are often retyped from memory quickly
+	case typed < 0.25:
+		return &models.AIRiskSignal{
+			Name:   "few_keystrokes",
+			Points: 20,
+			Detail: fmt.Sprintf("%d keystrokes for %d characters of code", t.Keystrokes, codeLength),
+		}
+	case typed < 0.5:
+		return &models.AIRiskSignal{
+			Name:   "few_keystrokes",
+			Points: 10,
+			Detail: fmt.Sprintf("%d keystrokes for %d characters of code", t.Keystrokes, codeLength),
+		}
+	}
+
+	if t.ActiveTypingMs > 0 {
+		perSecond := float64(t.Keystrokes) / (float64(t.ActiveTypingMs) / 1000)
+		if perSecond > 12 {
+			return &models.AIRiskSignal{
+				Name:   "typing_speed",
+				Points: 10,
+				Detail: fmt.Sprintf("%.1f keystrokes per second while typing", perSecond),
+			}
+		}
+	}
+	return nil
+}
+
+// durationSignal: a long solution submitted soon after the session opened
+func durationSignal(elapsed time.Duration, codeLength int) *models.AIRiskSignal {
+	if codeLength < 400 {
+		return nil
+	}
+	points := 0
+	switch {
+	case elapsed < 2*time.Minute:
+		points = 15
+	case elapsed < 5*time.Minute:
+		points = 8
+	}
+	if points == 0 {
+		return nil
+	}
+	return &models.AIRiskSignal{
+		Name:   "fast_submission",
+		Points: points,
+		Detail: fmt.Sprintf("%d characters submitted %s after the session started", codeLength, elapsed.Round(time.Second)),
+	}
+}
+
+// styleSignal: the code's style is far from the learner's usual style
+func styleSignal(code string, baseline []stylometry.Features) *models.AIRiskSignal {
+	if len(baseline) < styleBaselineMin {
+		return nil
+	}
+	distance := stylometry.Distance(stylometry.Extract(code), baseline)
+	points := 0
+	switch {
+	case distance > 3:
+		points = 20
+	case distance > 2:
+		points = 10
+	}
+	if points == 0 {
+		return nil
+	}
+	return &models.AIRiskSignal{
+		Name:   "style_shift",
+		Points: points,
+		Detail: fmt.Sprintf("style is %.1f deviations from the learner's last %d submissions", distance, len(baseline)),
+	}
+}
@@ -1,32 +1,107 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"time"
 
+	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/curriculum"
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/events"
 	"github.com/yourusername/skilltree/internal/models"
 	"github.com/yourusername/skilltree/internal/repository"
 )
 
+var (
+	// ErrCheckpointCooldown means the learner failed too often and must wait
+	ErrCheckpointCooldown = repository.ErrCheckpointCooldown
+	// ErrDailyAttemptCapReached means the tier's attempts for today are used up
+	ErrDailyAttemptCapReached = repository.ErrDailyAttemptCapReached
+	// ErrSessionNotFound means the session doesn't exist for this user
+	ErrSessionNotFound = repository.ErrSessionNotFound
+	// ErrSessionClosed means the session was already submitted or has expired
+	ErrSessionClosed = repository.ErrSessionClosed
+	// ErrAttemptNotFound means the attempt doesn't exist for this user
+	ErrAttemptNotFound = errors.New("attempt not found")
+	// ErrCheckpointAlreadyPassed means the tier's checkpoint is already passed;
+	// passing it again would pay its XP and events a second time
+	ErrCheckpointAlreadyPassed = errors.New("checkpoint already passed")
+)
+
+// VerdictQuizRequired holds a passing attempt until the learner explains
+// their code in a verification quiz
+const VerdictQuizRequired = "QUIZ_REQUIRED"
+
 type CheckpointService struct {
 	checkpointRepo *repository.CheckpointRepository
 	masteryRepo    *repository.MasteryRepository
-	geminiService  *GeminiService
+	reviewRepo     *repository.ReviewRepository
+	judge          *ConsensusJudge
+	guard          *SubmissionGuard
+	similarity     *SimilarityService
+	aiRisk         *AIRiskService
+	quizzes        *QuizService
+	curricula      *CurriculumService
+	events         *events.Bus
 }
 
 func NewCheckpointService(
 	checkpointRepo *repository.CheckpointRepository,
 	masteryRepo *repository.MasteryRepository,
-	geminiService *GeminiService,
+	reviewRepo *repository.ReviewRepository,
+	judge *ConsensusJudge,
+	guard *SubmissionGuard,
+	similarity *SimilarityService,
+	aiRisk *AIRiskService,
+	quizzes *QuizService,
 ) *CheckpointService {
 	return &CheckpointService{
 		checkpointRepo: checkpointRepo,
 		masteryRepo:    masteryRepo,
-		geminiService:  geminiService,
+		reviewRepo:     reviewRepo,
+		judge:          judge,
+		guard:          guard,
+		similarity:     similarity,
+		aiRisk:         aiRisk,
+		quizzes:        quizzes,
 	}
 }
 
+// SetEventBus publishes judged attempts and checkpoint passes to bus
+func (s *CheckpointService) SetEventBus(bus *events.Bus) {
+	s.events = bus
+}
+
+// SetCurriculumService evaluates checkpoint readiness against each
+// learner's curriculum instead of the standard one
+func (s *CheckpointService) SetCurriculumService(curricula *CurriculumService) {
+	s.curricula = curricula
+}
+
+// curriculumFor returns the learner's active curriculum
+func (s *CheckpointService) curriculumFor(firebaseUID string) (*curriculum.Curriculum, error) {
+	if s.curricula == nil {
+		return &curriculum.Standard, nil
+	}
+	c, _, _, err := s.curricula.Active(firebaseUID)
+	return c, err
+}
+
 // GetCheckpointStatus returns all checkpoint statuses with can_attempt flags
 func (s *CheckpointService) GetCheckpointStatus(firebaseUID string) (*models.CheckpointResponse, error) {
+	// Close out sessions whose time ran out so they count as failed attempts
+	if err := s.expireSessions(firebaseUID); err != nil {
+		return nil, err
+	}
+
+	activeSessions, err := s.checkpointRepo.GetActiveSessions(firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active sessions: %w", err)
+	}
+
 	// Get all checkpoints for user
 	checkpoints, err := s.checkpointRepo.GetAllByFirebaseUID(firebaseUID)
 	if err != nil {
@@ -38,6 +113,16 @@ func (s *CheckpointService) GetCheckpointStatus(firebaseUID string) (*models.Che
 	if err != nil {
 		return nil, fmt.Errorf("failed to get mastery: %w", err)
 	}
+	cur, err := s.curriculumFor(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count today's attempts to report remaining daily allowance
+	attemptsToday, err := s.checkpointRepo.CountAttemptsSince(firebaseUID, startOfDay(time.Now()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count attempts: %w", err)
+	}
 
 	// Build response
 	response := &models.CheckpointResponse{
@@ -58,12 +143,22 @@ func (s *CheckpointService) GetCheckpointStatus(firebaseUID string) (*models.Che
 			if checkpoint.TierNumber == tier {
 				status.IsPassed = checkpoint.IsPassed
 				status.Attempts = checkpoint.Attempts
+				status.NextAllowedAt = futureTime(checkpoint.NextAttemptAt.Time, checkpoint.NextAttemptAt.Valid)
+				break
+			}
+		}
+
+		status.RemainingAttempts = remainingAttempts(config.GetCheckpointPolicy(tier), attemptsToday[tier])
+		for i := range activeSessions {
+			if activeSessions[i].TierNumber == tier {
+				status.ActiveSession = &activeSessions[i]
 				break
 			}
 		}
 
-		// Calculate can_attempt: all topics in tier must have ≥70% confidence
-		status.CanAttempt = s.canAttemptCheckpoint(tier, masteryData)
+		// Calculate can_attempt: all of the tier's topics in the learner's
+		// curriculum must reach their threshold
+		status.CanAttempt = cur.ReadyForCheckpoint(tier, confidenceByTopic(masteryData))
 
 		response.Checkpoints[tier] = status
 	}
@@ -71,160 +166,490 @@ func (s *CheckpointService) GetCheckpointStatus(firebaseUID string) (*models.Che
 	return response, nil
 }
 
-// canAttemptCheckpoint checks if user has completed all topics in a tier
-func (s *CheckpointService) canAttemptCheckpoint(tier int, masteryData []models.UserMastery) bool {
-	// Get all topics in the tier
-	tierTopics := getTopicsForTier(tier)
-	if len(tierTopics) == 0 {
-		return false
+// StartSession opens a timed checkpoint session with its assigned problem,
+// or resumes the tier's session if one is already running
+func (s *CheckpointService) StartSession(firebaseUID string, tier int) (*models.StartSessionResponse, error) {
+	// Get checkpoint
+	checkpoint, err := s.checkpointRepo.GetByFirebaseUIDAndTier(firebaseUID, tier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		return nil, fmt.Errorf("checkpoint not found for tier %d", tier)
+	}
+	if checkpoint.IsPassed {
+		return nil, ErrCheckpointAlreadyPassed
 	}
 
-	// Check if ALL topics in tier have ≥70% confidence
-	for _, topicKey := range tierTopics {
-		found := false
-		for _, mastery := range masteryData {
-			if mastery.TopicKey == topicKey {
-				if mastery.Confidence < 70 {
-					return false // Topic not ready
-				}
-				found = true
-				break
-			}
+	// Verify user can attempt (all of the tier's topics at their threshold)
+	masteryData, err := s.masteryRepo.GetAllByUserID(firebaseUID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mastery: %w", err)
+	}
+	cur, err := s.curriculumFor(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	canAttempt := cur.ReadyForCheckpoint(tier, confidenceByTopic(masteryData))
+	if !canAttempt {
+		return nil, fmt.Errorf("cannot attempt checkpoint: complete all tier %d topics first", tier)
+	}
+
+	if err := s.expireSessions(firebaseUID); err != nil {
+		return nil, err
+	}
+
+	// Open the session (enforces cooldown and daily cap atomically)
+	policy := config.GetCheckpointPolicy(tier)
+	limits := repository.AttemptLimits{
+		DailyCap:  policy.DailyAttemptCap,
+		DayStart:  startOfDay(time.Now()),
+		TimeLimit: policy.TimeLimit,
+	}
+	session, created, err := s.checkpointRepo.CreateSession(firebaseUID, tier, limits, func(attemptNumber int) string {
+		return assignCheckpointProblem(firebaseUID, tier, attemptNumber).ID
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+
+	problem := checkpointProblemIn(cur, session.ProblemID)
+	if problem == nil {
+		return nil, fmt.Errorf("checkpoint problem %s not found", session.ProblemID)
+	}
+
+	return &models.StartSessionResponse{
+		Session:    session,
+		Problem:    problem,
+		ServerTime: time.Now(),
+		Resumed:    !created,
+	}, nil
+}
+
+// SaveDraft autosaves the learner's code on an open session
+func (s *CheckpointService) SaveDraft(firebaseUID string, sessionID int64, code string) (time.Time, error) {
+	savedAt, err := s.checkpointRepo.SaveDraft(firebaseUID, sessionID, code)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to save draft: %w", err)
+	}
+	return savedAt, nil
+}
+
+// expireSessions records every open session past its deadline as a failed
+// attempt, using the last autosaved draft as the submission
+func (s *CheckpointService) expireSessions(firebaseUID string) error {
+	sessions, err := s.checkpointRepo.GetActiveSessions(firebaseUID)
+	if err != nil {
+		return fmt.Errorf("failed to get active sessions: %w", err)
+	}
+
+	now := time.Now()
+	for i := range sessions {
+		if now.Before(sessions[i].DeadlineAt.Add(config.CheckpointSubmitGrace)) {
+			continue
 		}
-		if !found {
-			return false // Topic not initialized
+		if err := s.expireSession(&sessions[i]); err != nil {
+			return err
 		}
 	}
 
-	return true
+	return nil
 }
 
-// AttemptCheckpoint validates code and updates checkpoint if passed
-func (s *CheckpointService) AttemptCheckpoint(firebaseUID string, req *models.CheckpointAttemptRequest) (*models.CheckpointJudgeResponse, error) {
-	// Get checkpoint
-	checkpoint, err := s.checkpointRepo.GetByFirebaseUIDAndTier(firebaseUID, req.TierNumber)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get checkpoint: %w", err)
+// expireSession closes one session as expired and applies the failure policy
+func (s *CheckpointService) expireSession(session *models.CheckpointSession) error {
+	attempt := &models.CheckpointAttempt{
+		UserUID:       session.UserUID,
+		SessionID:     session.ID,
+		TierNumber:    session.TierNumber,
+		ProblemID:     session.ProblemID,
+		SubmittedCode: session.DraftCode,
 	}
-	if checkpoint == nil {
-		return nil, fmt.Errorf("checkpoint not found for tier %d", req.TierNumber)
+
+	err := s.checkpointRepo.RecordAttempt(attempt, models.SessionExpired)
+	if errors.Is(err, repository.ErrSessionClosed) {
+		return nil // Submitted or expired concurrently
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record expired attempt: %w", err)
 	}
 
-	// Verify user can attempt (all tier topics >= 70%)
-	masteryData, err := s.masteryRepo.GetAllByUserID(firebaseUID)
+	policy := config.GetCheckpointPolicy(session.TierNumber)
+	_, err = s.checkpointRepo.RecordFailure(attempt.ID, session.UserUID, session.TierNumber, models.VerdictExpired, policy.CooldownAfter)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get mastery: %w", err)
+		return fmt.Errorf("failed to record expired attempt: %w", err)
 	}
 
-	canAttempt := s.canAttemptCheckpoint(req.TierNumber, masteryData)
-	if !canAttempt {
-		return nil, fmt.Errorf("cannot attempt checkpoint: complete all tier %d topics first", req.TierNumber)
+	return nil
+}
+
+// failAttempt records an ERROR verdict for a submitted attempt that couldn't
+// be judged, so it doesn't count toward the daily cap, and returns err
+func (s *CheckpointService) failAttempt(attemptID int64, err error) error {
+	if verdictErr := s.checkpointRepo.RecordVerdict(attemptID, "ERROR"); verdictErr != nil {
+		return fmt.Errorf("%v (and %w)", err, verdictErr)
 	}
+	return err
+}
 
-	// Get checkpoint problem details
-	checkpointProblem := getCheckpointProblem(req.TierNumber)
-	if checkpointProblem == nil {
-		return nil, fmt.Errorf("checkpoint problem not found for tier %d", req.TierNumber)
+// AttemptCheckpoint judges the code submitted for an open session and updates
+// the checkpoint if passed. Submissions after the deadline expire the session.
+func (s *CheckpointService) AttemptCheckpoint(ctx context.Context, firebaseUID string, req *models.CheckpointAttemptRequest) (*models.CheckpointJudgeResponse, error) {
+	session, err := s.checkpointRepo.GetSession(firebaseUID, req.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.Status != models.SessionActive {
+		return nil, fmt.Errorf("failed to submit: %w", ErrSessionClosed)
+	}
+	if time.Now().After(session.DeadlineAt.Add(config.CheckpointSubmitGrace)) {
+		if err := s.expireSession(session); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to submit: %w", ErrSessionClosed)
 	}
 
-	// Record attempt
-	err = s.checkpointRepo.RecordAttempt(firebaseUID, req.TierNumber, req.Code)
+	cur, err := s.curriculumFor(firebaseUID)
 	if err != nil {
+		return nil, err
+	}
+	checkpointProblem := checkpointProblemIn(cur, session.ProblemID)
+	if checkpointProblem == nil {
+		return nil, fmt.Errorf("checkpoint problem %s not found", session.ProblemID)
+	}
+
+	// Record attempt (closes the session so it can't be submitted twice and
+	// numbers it under the checkpoint lock)
+	attempt := &models.CheckpointAttempt{
+		UserUID:       firebaseUID,
+		SessionID:     session.ID,
+		TierNumber:    session.TierNumber,
+		ProblemID:     session.ProblemID,
+		SubmittedCode: req.Code,
+		TestResults:   req.TestResults,
+		Telemetry:     req.Telemetry,
+	}
+	if err := s.checkpointRepo.RecordAttempt(attempt, models.SessionSubmitted); err != nil {
 		return nil, fmt.Errorf("failed to record attempt: %w", err)
 	}
 
-	// Call Gemini judge with checkpoint-specific validation
-	judgeResult, err := s.geminiService.JudgeCheckpoint(
+	// Estimate whether the code was pasted from an AI assistant
+	risk, err := s.aiRisk.Assess(attempt, session.StartedAt, attempt.CreatedAt)
+	if err != nil {
+		return nil, s.failAttempt(attempt.ID, err)
+	}
+	if err := s.checkpointRepo.RecordAIRisk(attempt.ID, risk); err != nil {
+		return nil, s.failAttempt(attempt.ID, err)
+	}
+
+	policy := config.GetCheckpointPolicy(session.TierNumber)
+
+	// Call the judge panel with checkpoint-specific validation
+	judgeResult, err := s.judge.JudgeCheckpoint(
+		ctx,
+		firebaseUID,
 		req.Code,
-		req.TierNumber,
+		session.TierNumber,
 		checkpointProblem.RequiredPatterns,
 		checkpointProblem.Description,
 	)
+	reviewSource := models.ReviewSourceJudgeSplit
+	if errors.Is(err, ErrAIUnavailable) {
+		// Degraded mode: keep the attempt and let a mentor judge it
+		judgeResult = &ConsensusResult{
+			Verdict:         VerdictNeedsReview,
+			Feedback:        "The judge is temporarily unavailable. Your attempt was saved and a mentor will review it.",
+			PatternsFound:   []string{},
+			MissingPatterns: []string{},
+		}
+		reviewSource = models.ReviewSourceJudgeUnavailable
+		err = nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to judge checkpoint: %w", err)
+		return nil, s.failAttempt(attempt.ID, fmt.Errorf("failed to judge checkpoint: %w", err))
 	}
 
-	// Get updated attempts count
-	updatedCheckpoint, _ := s.checkpointRepo.GetByFirebaseUIDAndTier(firebaseUID, req.TierNumber)
-	attempts := 0
-	if updatedCheckpoint != nil {
-		attempts = updatedCheckpoint.Attempts
+	// Second pass: a pass backed by injected instructions or failing tests
+	// is held for a mentor
+	screening := s.guard.Screen(req.Code, judgeResult.Verdict, req.TestResults)
+	if err := s.guard.Record(firebaseUID, models.ReviewSubjectCheckpointAttempt, attempt.ID, judgeResult.Verdict, screening); err != nil {
+		return nil, s.failAttempt(attempt.ID, err)
+	}
+
+	// Compare against other learners' attempts and known public solutions
+	similarityReport, err := s.similarity.Check(firebaseUID, models.ReviewSubjectCheckpointAttempt, attempt.ID, session.ProblemID, req.Code)
+	if err != nil {
+		return nil, s.failAttempt(attempt.ID, err)
+	}
+
+	attemptsToday, err := s.checkpointRepo.CountAttemptsSince(firebaseUID, startOfDay(time.Now()))
+	if err != nil {
+		return nil, s.failAttempt(attempt.ID, fmt.Errorf("failed to count attempts: %w", err))
+	}
+
+	// Keep the individual votes so disagreements can be audited
+	if err := s.checkpointRepo.RecordJudgement(attempt.ID, judgeResult.Feedback, judgeResult.Votes, judgeResult.PromptVersion); err != nil {
+		return nil, s.failAttempt(attempt.ID, fmt.Errorf("failed to record judgement: %w", err))
 	}
 
 	// Build response
 	response := &models.CheckpointJudgeResponse{
-		Verdict:         judgeResult["verdict"].(string),
-		Feedback:        judgeResult["feedback"].(string),
-		PatternsFound:   convertToStringSlice(judgeResult["patterns_found"]),
-		MissingPatterns: convertToStringSlice(judgeResult["missing_patterns"]),
-		IsPassed:        false,
-		Attempts:        attempts,
+		AttemptID:         attempt.ID,
+		ProblemID:         checkpointProblem.ID,
+		Verdict:           judgeResult.Verdict,
+		Feedback:          judgeResult.Feedback,
+		PatternsFound:     judgeResult.PatternsFound,
+		MissingPatterns:   judgeResult.MissingPatterns,
+		IsPassed:          false,
+		Attempts:          attempt.AttemptNumber,
+		Agreement:         judgeResult.Agreement,
+		Votes:             judgeResult.Votes,
+		RemainingAttempts: remainingAttempts(policy, attemptsToday[session.TierNumber]),
+		Degraded:          reviewSource == models.ReviewSourceJudgeUnavailable,
+	}
+	reviewDetails := ""
+	if screening.Held() {
+		response.Verdict = VerdictNeedsReview
+		response.Feedback = heldFeedback(screening)
+		reviewSource = models.ReviewSourceSecurityFlag
+	} else if similarityReport.Flagged() && isAdvance(response.Verdict) {
+		response.Verdict = VerdictNeedsReview
+		response.Feedback = "Your solution closely matches existing code for this problem, so a mentor will review it before the checkpoint is passed."
+		reviewSource = models.ReviewSourceSimilarity
+		reviewDetails = similarityDetails(similarityReport)
+	} else if s.aiRisk.QuizRequired(risk) && isAdvance(response.Verdict) {
+		// The pass only counts once the learner explains their code
+		quiz, err := s.quizzes.Create(ctx, firebaseUID, models.ReviewSubjectCheckpointAttempt, attempt.ID,
+			models.QuizReasonAIRisk, req.Code, checkpointProblem.Description, checkpointProblem.Invariant)
+		if err != nil {
+			if !errors.Is(err, ErrAIUnavailable) {
+				return nil, s.failAttempt(attempt.ID, err)
+			}
+			response.Verdict = VerdictNeedsReview
+			response.Feedback = "Your solution passed, but a mentor will confirm it before the checkpoint is passed."
+			reviewSource = models.ReviewSourceAIRisk
+			reviewDetails = fmt.Sprintf("AI risk %d; verification quiz unavailable", risk.Score)
+		} else {
+			response.Verdict = VerdictQuizRequired
+			response.Feedback = "Your solution passed. Answer a few questions about how it works to complete the checkpoint."
+			response.Quiz = quiz
+		}
 	}
 
-	// If ADVANCE, mark checkpoint as passed
-	if response.Verdict == "ADVANCE" {
-		err = s.checkpointRepo.MarkAsPassed(firebaseUID, req.TierNumber)
+	switch response.Verdict {
+	case "ADVANCE":
+		// Mark checkpoint as passed (also clears any cooldown)
+		if err := s.checkpointRepo.RecordVerdict(attempt.ID, response.Verdict); err != nil {
+			return nil, fmt.Errorf("failed to record verdict: %w", err)
+		}
+		err = s.checkpointRepo.MarkAsPassed(firebaseUID, session.TierNumber)
 		if err != nil {
 			return nil, fmt.Errorf("failed to mark checkpoint as passed: %w", err)
 		}
 		response.IsPassed = true
+		s.events.Publish(checkpointEvent(events.CheckpointPassed, attempt))
+	case "REPEAT":
+		// Count the failure and start a cooldown if the policy calls for it
+		nextAllowedAt, err := s.checkpointRepo.RecordFailure(attempt.ID, firebaseUID, session.TierNumber, response.Verdict, policy.CooldownAfter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record failure: %w", err)
+		}
+		response.NextAllowedAt = nextAllowedAt
+	case VerdictNeedsReview:
+		// Judges split: neither pass nor fail until a mentor decides
+		if err := s.checkpointRepo.RecordVerdict(attempt.ID, response.Verdict); err != nil {
+			return nil, fmt.Errorf("failed to record verdict: %w", err)
+		}
+		if reviewDetails == "" {
+			reviewDetails = response.Feedback
+		}
+		review := &models.ReviewRequest{
+			UserUID:     firebaseUID,
+			SubjectType: models.ReviewSubjectCheckpointAttempt,
+			SubjectID:   attempt.ID,
+			Source:      reviewSource,
+			Details:     reviewDetails,
+		}
+		if err := queueReview(s.reviewRepo, review, response.Verdict); err != nil {
+			return nil, err
+		}
+		response.NeedsReview = true
+	case VerdictQuizRequired:
+		// Neither pass nor fail until the quiz is answered
+		if err := s.checkpointRepo.RecordVerdict(attempt.ID, response.Verdict); err != nil {
+			return nil, fmt.Errorf("failed to record verdict: %w", err)
+		}
+	default:
+		// Judge errors don't count against the learner: no failure is
+		// recorded and the session is left out of the daily cap
+		if err := s.checkpointRepo.RecordVerdict(attempt.ID, response.Verdict); err != nil {
+			return nil, fmt.Errorf("failed to record verdict: %w", err)
+		}
 	}
+	s.events.Publish(checkpointEvent(events.CheckpointAttempted, attempt))
 
 	return response, nil
 }
 
-// Helper function to get topics for a specific tier
-func getTopicsForTier(tier int) []string {
-	tierMap := map[int][]string{
-		0: {"ARRAY_SCAN", "RECURSION_ROOTS"},
-		1: {"SORTING", "HASHING", "STACKS"},
-		2: {"PREFIX_SUM", "TWO_POINTERS", "QUEUES", "LINKED_LISTS"},
-		3: {"SLIDING_WINDOW", "BINARY_SEARCH", "MONOTONIC_STACK"},
-		4: {"BINARY_TREES", "INTERVALS", "GREEDY"},
-		5: {"DFS_BFS", "BACKTRACKING", "TRIES"},
-		6: {"TOPOLOGICAL_SORT", "UNION_FIND", "BIT_MANIPULATION"},
-	}
-	return tierMap[tier]
-}
-
-// Helper function to get checkpoint problem metadata
-func getCheckpointProblem(tier int) *CheckpointProblem {
-	problems := map[int]*CheckpointProblem{
-		0: {
-			Description:      "Generate all subsets (power set) using recursion and array iteration",
-			RequiredPatterns: []string{"Array Iteration", "Recursive Backtracking"},
-		},
-		1: {
-			Description:      "Merge overlapping intervals with hash deduplication and stack validation",
-			RequiredPatterns: []string{"Sorting", "Hashing", "Stack"},
-		},
-		2: {
-			Description:      "Maximum sum subarray with prefix sum optimization and sliding window",
-			RequiredPatterns: []string{"Prefix Sum", "Sliding Window", "Queue"},
-		},
-		3: {
-			Description:      "Largest rectangle in histogram using binary search and monotonic stack",
-			RequiredPatterns: []string{"Binary Search", "Monotonic Stack", "Sliding Window"},
-		},
-		4: {
-			Description:      "Maximum non-overlapping intervals in binary tree with greedy selection",
-			RequiredPatterns: []string{"Tree Traversal", "Interval Merging", "Greedy"},
-		},
-		5: {
-			Description:      "Word Search II using Trie construction, DFS traversal, and backtracking",
-			RequiredPatterns: []string{"Trie", "DFS", "Backtracking"},
-		},
-		6: {
-			Description:      "Course scheduling with topological sort, union-find, and bitmask states",
-			RequiredPatterns: []string{"Topological Sort", "Union-Find", "Bit Manipulation"},
-		},
-	}
-	return problems[tier]
-}
-
-type CheckpointProblem struct {
-	Description      string
-	RequiredPatterns []string
+// checkpointEvent describes a checkpoint attempt for the event bus
+func checkpointEvent(eventType string, attempt *models.CheckpointAttempt) events.Event {
+	return events.Event{
+		Type:          eventType,
+		UserUID:       attempt.UserUID,
+		SubjectType:   models.ReviewSubjectCheckpointAttempt,
+		SubjectID:     attempt.ID,
+		ProblemID:     attempt.ProblemID,
+		Tier:          attempt.TierNumber,
+		AttemptNumber: attempt.AttemptNumber,
+	}
+}
+
+// GetQuiz returns the verification quiz for one of the learner's attempts
+func (s *CheckpointService) GetQuiz(firebaseUID string, attemptID int64) (*models.VerificationQuiz, error) {
+	return s.quizzes.Get(firebaseUID, models.ReviewSubjectCheckpointAttempt, attemptID)
+}
+
+// SubmitQuiz grades the learner's explanation of an attempt held for a
+// verification quiz. A pass applies the ADVANCE verdict; a fail or an
+// expired quiz sends the attempt to a mentor instead of failing it.
+func (s *CheckpointService) SubmitQuiz(ctx context.Context, firebaseUID string, attemptID int64, answers []models.QuizAnswer) (*models.QuizResult, error) {
+	attempt, err := s.checkpointRepo.GetAttempt(attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == nil || attempt.UserUID != firebaseUID {
+		return nil, ErrAttemptNotFound
+	}
+
+	quiz, err := s.quizzes.Get(firebaseUID, models.ReviewSubjectCheckpointAttempt, attemptID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.Verdict != VerdictQuizRequired {
+		return nil, ErrQuizNotPending
+	}
+
+	problem := ""
+	if checkpointProblem := data.FindCheckpointProblem(attempt.ProblemID); checkpointProblem != nil {
+		problem = checkpointProblem.Description
+	}
+
+	result, err := s.quizzes.Grade(ctx, quiz, attempt.SubmittedCode, problem, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Passed {
+		if err := s.ApplyReviewVerdict(attempt, "ADVANCE"); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+
+	if err := s.checkpointRepo.RecordVerdict(attempt.ID, VerdictNeedsReview); err != nil {
+		return nil, fmt.Errorf("failed to record verdict: %w", err)
+	}
+	riskScore := 0
+	if attempt.AIRisk != nil {
+		riskScore = attempt.AIRisk.Score
+	}
+	review := &models.ReviewRequest{
+		UserUID:     firebaseUID,
+		SubjectType: models.ReviewSubjectCheckpointAttempt,
+		SubjectID:   attempt.ID,
+		Source:      models.ReviewSourceAIRisk,
+		Details:     fmt.Sprintf("AI risk %d; verification quiz %s with score %d", riskScore, quiz.Status, *quiz.Score),
+	}
+	if err := queueReview(s.reviewRepo, review, VerdictNeedsReview); err != nil {
+		return nil, err
+	}
+	result.NeedsReview = true
+
+	return result, nil
+}
+
+// ApplyReviewVerdict applies a mentor's verdict to a checkpoint attempt.
+// ADVANCE passes the checkpoint; REPEAT only records the verdict since the
+// decision comes too late to fairly start a cooldown.
+func (s *CheckpointService) ApplyReviewVerdict(attempt *models.CheckpointAttempt, verdict string) error {
+	if err := s.checkpointRepo.RecordVerdict(attempt.ID, verdict); err != nil {
+		return fmt.Errorf("failed to record verdict: %w", err)
+	}
+
+	if verdict == "ADVANCE" {
+		if err := s.checkpointRepo.MarkAsPassed(attempt.UserUID, attempt.TierNumber); err != nil {
+			return fmt.Errorf("failed to mark checkpoint as passed: %w", err)
+		}
+		s.events.Publish(checkpointEvent(events.CheckpointPassed, attempt))
+	}
+
+	return nil
+}
+
+// startOfDay returns the UTC midnight that daily attempt caps reset at
+func startOfDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
+
+// remainingAttempts returns how many attempts are left today, or nil when uncapped
+func remainingAttempts(policy config.CheckpointPolicy, usedToday int) *int {
+	if policy.DailyAttemptCap <= 0 {
+		return nil
+	}
+	remaining := policy.DailyAttemptCap - usedToday
+	if remaining < 0 {
+		remaining = 0
+	}
+	return &remaining
+}
+
+// futureTime returns t only if it is set and still ahead of now
+func futureTime(t time.Time, valid bool) *time.Time {
+	if !valid || !t.After(time.Now()) {
+		return nil
+	}
+	return &t
+}
+
+// checkpointProblemIn returns a pool variant with the patterns its tier's
+// checkpoint requires in the learner's curriculum, or nil
+func checkpointProblemIn(cur *curriculum.Curriculum, problemID string) *data.CheckpointProblem {
+	problem := data.FindCheckpointProblem(problemID)
+	if problem == nil {
+		return nil
+	}
+	evaluated := *problem
+	evaluated.RequiredPatterns = cur.CheckpointPatterns(problem.Tier, problem.RequiredPatterns)
+	return &evaluated
+}
+
+// assignCheckpointProblem picks the pool variant for a user's nth attempt at a tier.
+// Each user starts at a hashed offset into the pool and rotates by one variant
+// per attempt, so retries see a different problem and neighbours rarely share one.
+func assignCheckpointProblem(firebaseUID string, tier, attemptNumber int) *data.CheckpointProblem {
+	pool := data.CheckpointPools[tier]
+	if len(pool) == 0 {
+		return nil
+	}
+
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", firebaseUID, tier)
+	offset := int(h.Sum32() % uint32(len(pool)))
+
+	if attemptNumber < 1 {
+		attemptNumber = 1
+	}
+	return &pool[(offset+attemptNumber-1)%len(pool)]
+}
+
+// GetVariantStats returns pass/fail statistics for every checkpoint pool variant
+func (s *CheckpointService) GetVariantStats() ([]models.CheckpointVariantStats, error) {
+	stats, err := s.checkpointRepo.GetVariantStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get variant stats: %w", err)
+	}
+	return stats, nil
 }
 
 // Helper function to convert interface{} to []string
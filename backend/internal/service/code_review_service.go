@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// CodeReviewService produces line-anchored review findings for the IDE
+type CodeReviewService struct {
+	geminiService *GeminiService
+	problems      *ProblemService
+}
+
+func NewCodeReviewService(geminiService *GeminiService) *CodeReviewService {
+	return &CodeReviewService{geminiService: geminiService}
+}
+
+// SetProblemService also reviews against published authored problems
+func (s *CodeReviewService) SetProblemService(problems *ProblemService) {
+	s.problems = problems
+}
+
+// Review reviews the code, against the problem's invariant if one is given.
+// Findings are clamped to the code's lines and sorted by position.
+func (s *CodeReviewService) Review(ctx context.Context, firebaseUID string, req *models.CodeReviewRequest) (*models.CodeReviewResponse, error) {
+	var topic, title, invariant string
+	if req.TopicKey != "" || req.ProblemID != "" {
+		problem, _, err := s.problems.Find(req.TopicKey, req.ProblemID)
+		if err != nil {
+			return nil, err
+		}
+		topic, title, invariant = req.TopicKey, problem.Title, problem.Invariant
+	}
+
+	review, err := s.geminiService.CodeReview(ctx, firebaseUID, req.Code, topic, title, invariant)
+	if err != nil {
+		return nil, fmt.Errorf("failed to review code: %w", err)
+	}
+
+	review.Findings = anchorFindings(review.Findings, lineCount(req.Code))
+	return review, nil
+}
+
+// anchorFindings drops findings without a message, clamps line ranges to
+// [1, lines] and normalizes severity and category
+func anchorFindings(findings []models.CodeReviewFinding, lines int) []models.CodeReviewFinding {
+	anchored := make([]models.CodeReviewFinding, 0, len(findings))
+	for _, f := range findings {
+		if strings.TrimSpace(f.Message) == "" {
+			continue
+		}
+
+		f.StartLine = clampLine(f.StartLine, lines)
+		if f.EndLine == 0 {
+			f.EndLine = f.StartLine
+		}
+		f.EndLine = clampLine(f.EndLine, lines)
+		if f.EndLine < f.StartLine {
+			f.StartLine, f.EndLine = f.EndLine, f.StartLine
+		}
+
+		f.Severity = normalizeSeverity(f.Severity)
+		f.Category = normalizeCategory(f.Category)
+		anchored = append(anchored, f)
+	}
+
+	sort.SliceStable(anchored, func(i, j int) bool {
+		return anchored[i].StartLine < anchored[j].StartLine
+	})
+	return anchored
+}
+
+func clampLine(line, lines int) int {
+	if line < 1 {
+		return 1
+	}
+	if line > lines {
+		return lines
+	}
+	return line
+}
+
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case models.FindingError, "critical", "high":
+		return models.FindingError
+	case models.FindingInfo, "low", "note":
+		return models.FindingInfo
+	default:
+		return models.FindingWarning
+	}
+}
+
+func normalizeCategory(category string) string {
+	category = strings.ToLower(strings.TrimSpace(category))
+	switch {
+	case strings.HasPrefix(category, "correct"), category == "bug":
+		return models.FindingCorrectness
+	case strings.HasPrefix(category, "complex"), category == "performance":
+		return models.FindingComplexity
+	case strings.HasPrefix(category, "invariant"):
+		return models.FindingInvariant
+	default:
+		return models.FindingStyle
+	}
+}
+
+// lineCount counts the lines of code, ignoring a trailing newline
+func lineCount(code string) int {
+	return strings.Count(strings.TrimSuffix(code, "\n"), "\n") + 1
+}
+
+// numberLines prefixes each line with its 1-based number for the model
+func numberLines(code string) string {
+	lines := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		fmt.Fprintf(&b, "%d| %s\n", i+1, line)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
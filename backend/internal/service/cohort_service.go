@@ -0,0 +1,431 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/curriculum"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+var (
+	// ErrCohortNotFound means the cohort doesn't exist or the user isn't in it
+	ErrCohortNotFound = errors.New("cohort not found")
+	// ErrNotCohortInstructor means the action needs an instructor of the cohort
+	ErrNotCohortInstructor = errors.New("not a cohort instructor")
+	// ErrInvalidInviteCode means no cohort uses the invite code
+	ErrInvalidInviteCode = errors.New("invalid invite code")
+	// ErrInvalidCohortName means the name is empty or too long
+	ErrInvalidCohortName = errors.New("invalid cohort name")
+	// ErrInvalidDueDate means an assignment's due date is missing or past
+	ErrInvalidDueDate = errors.New("invalid due date")
+	// ErrAssignmentNotFound means the cohort has no such assignment
+	ErrAssignmentNotFound = errors.New("assignment not found")
+)
+
+const (
+	maxCohortNameLength = 100
+	// inviteCodeLength characters from inviteCodeAlphabet give 40 bits
+	inviteCodeLength   = 8
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+)
+
+// CohortService manages classes: membership through invite codes, the
+// instructor views of their learners, and assignments
+type CohortService struct {
+	cohortRepo   *repository.CohortRepository
+	leaderboards *LeaderboardService
+	problems     *ProblemService
+}
+
+func NewCohortService(cohortRepo *repository.CohortRepository, leaderboards *LeaderboardService) *CohortService {
+	return &CohortService{
+		cohortRepo:   cohortRepo,
+		leaderboards: leaderboards,
+	}
+}
+
+// SetProblemService lets instructors assign published authored problems
+func (s *CohortService) SetProblemService(problems *ProblemService) {
+	s.problems = problems
+}
+
+// Create starts a cohort with the caller as its instructor
+func (s *CohortService) Create(firebaseUID string, req *models.CreateCohortRequest) (*models.Cohort, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxCohortNameLength {
+		return nil, ErrInvalidCohortName
+	}
+
+	inviteCode, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+	instructorCode, err := newInviteCode()
+	if err != nil {
+		return nil, err
+	}
+
+	cohort := &models.Cohort{
+		Name:           name,
+		InviteCode:     inviteCode,
+		InstructorCode: instructorCode,
+	}
+	if err := s.cohortRepo.Create(cohort, firebaseUID); err != nil {
+		return nil, err
+	}
+	return cohort, nil
+}
+
+// Join adds the caller to the cohort an invite code belongs to, as a
+// learner or, with the instructor code, as an instructor
+func (s *CohortService) Join(firebaseUID, code string) (*models.Cohort, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, ErrInvalidInviteCode
+	}
+
+	cohort, role, err := s.cohortRepo.GetByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if cohort == nil {
+		return nil, ErrInvalidInviteCode
+	}
+
+	if err := s.cohortRepo.AddMember(cohort.ID, firebaseUID, role); err != nil {
+		return nil, err
+	}
+	return s.Get(firebaseUID, cohort.ID)
+}
+
+// List returns the caller's cohorts
+func (s *CohortService) List(firebaseUID string) ([]models.Cohort, error) {
+	cohorts, err := s.cohortRepo.ListForUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range cohorts {
+		hideInstructorCode(&cohorts[i])
+	}
+	return cohorts, nil
+}
+
+// Get returns a cohort the caller belongs to
+func (s *CohortService) Get(firebaseUID string, cohortID int64) (*models.Cohort, error) {
+	cohort, err := s.member(firebaseUID, cohortID)
+	if err != nil {
+		return nil, err
+	}
+	hideInstructorCode(cohort)
+	return cohort, nil
+}
+
+// Members lists a cohort's instructors and learners
+func (s *CohortService) Members(firebaseUID string, cohortID int64) ([]models.CohortMember, error) {
+	if _, err := s.instructor(firebaseUID, cohortID); err != nil {
+		return nil, err
+	}
+	return s.cohortRepo.ListMembers(cohortID, "")
+}
+
+// MasteryMatrix returns each learner's confidence and solve count per topic
+// of the cohort's curriculum
+func (s *CohortService) MasteryMatrix(firebaseUID string, cohortID int64) (*models.CohortMasteryMatrix, error) {
+	cohort, err := s.instructor(firebaseUID, cohortID)
+	if err != nil {
+		return nil, err
+	}
+	cur := cohortCurriculum(cohort)
+	topics := make([]string, 0, len(cur.Topics))
+	for _, t := range cur.Topics {
+		topics = append(topics, t.Key)
+	}
+
+	learners, err := s.cohortRepo.ListMembers(cohortID, models.CohortRoleLearner)
+	if err != nil {
+		return nil, err
+	}
+	masteries, err := s.cohortRepo.ListLearnerMastery(cohortID)
+	if err != nil {
+		return nil, err
+	}
+
+	column := make(map[string]int, len(topics))
+	for i, topic := range topics {
+		column[topic] = i
+	}
+
+	rows := make([]models.LearnerMasteryRow, len(learners))
+	rowOf := make(map[string]*models.LearnerMasteryRow, len(learners))
+	for i, learner := range learners {
+		rows[i] = models.LearnerMasteryRow{
+			UserUID:     learner.UserUID,
+			DisplayName: learner.DisplayName,
+			Confidence:  make([]int, len(topics)),
+			Solved:      make([]int, len(topics)),
+		}
+		rowOf[learner.UserUID] = &rows[i]
+	}
+	for _, m := range masteries {
+		row, ok := rowOf[m.FirebaseUID]
+		i, known := column[m.TopicKey]
+		if !ok || !known {
+			continue
+		}
+		row.Confidence[i] = m.Confidence
+		row.Solved[i] = len(m.SolvedProblems)
+	}
+
+	return &models.CohortMasteryMatrix{Topics: topics, Learners: rows}, nil
+}
+
+// Checkpoints returns each learner's checkpoint status for every tier
+func (s *CohortService) Checkpoints(firebaseUID string, cohortID int64) ([]models.LearnerCheckpointRow, error) {
+	if _, err := s.instructor(firebaseUID, cohortID); err != nil {
+		return nil, err
+	}
+
+	learners, err := s.cohortRepo.ListMembers(cohortID, models.CohortRoleLearner)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints, err := s.cohortRepo.ListLearnerCheckpoints(cohortID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]models.LearnerCheckpointRow, 0, len(learners))
+	for _, learner := range learners {
+		row := models.LearnerCheckpointRow{UserUID: learner.UserUID, DisplayName: learner.DisplayName}
+		// Tiers 0-6, as in GetCheckpointStatus
+		for tier := 0; tier <= 6; tier++ {
+			status := models.LearnerTierStatus{TierNumber: tier}
+			for _, c := range checkpoints[learner.UserUID] {
+				if c.TierNumber == tier {
+					status = c
+					break
+				}
+			}
+			row.Tiers = append(row.Tiers, status)
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// Submissions returns a page of the learners' recent judge submissions
+func (s *CohortService) Submissions(firebaseUID string, cohortID int64, limit, offset int) ([]models.CohortSubmission, error) {
+	if _, err := s.instructor(firebaseUID, cohortID); err != nil {
+		return nil, err
+	}
+	return s.cohortRepo.ListRecentSubmissions(cohortID, limit, offset)
+}
+
+// CreateAssignment sets a due date for a problem, or for a whole topic if
+// no problem is given
+func (s *CohortService) CreateAssignment(firebaseUID string, cohortID int64, req *models.CreateAssignmentRequest) (*models.CohortAssignment, error) {
+	cohort, err := s.instructor(firebaseUID, cohortID)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := cohortCurriculum(cohort)
+	if req.ProblemID != "" {
+		if _, _, err := s.problems.Find(req.TopicKey, req.ProblemID); err != nil {
+			return nil, err
+		}
+		if !problemInCurriculum(cur, req.TopicKey, req.ProblemID) {
+			return nil, ErrTopicNotInCurriculum
+		}
+	} else if !slices.Contains(config.AllTopics, req.TopicKey) {
+		return nil, ErrInvalidTopic
+	} else if cur.Topic(req.TopicKey) == nil {
+		return nil, ErrTopicNotInCurriculum
+	}
+	if req.DueAt.IsZero() || !req.DueAt.After(time.Now()) {
+		return nil, ErrInvalidDueDate
+	}
+
+	assignment := &models.CohortAssignment{
+		CohortID:  cohortID,
+		TopicKey:  req.TopicKey,
+		ProblemID: req.ProblemID,
+		DueAt:     req.DueAt,
+		CreatedBy: firebaseUID,
+	}
+	if err := s.cohortRepo.CreateAssignment(assignment); err != nil {
+		return nil, err
+	}
+	completed := 0
+	assignment.CompletedCount = &completed
+	return assignment, nil
+}
+
+// Assignments lists a cohort's assignments. Learners see whether they have
+// completed each one; instructors see how many learners have.
+func (s *CohortService) Assignments(firebaseUID string, cohortID int64) ([]models.CohortAssignment, error) {
+	cohort, err := s.member(firebaseUID, cohortID)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := s.cohortRepo.ListAssignments(cohortID)
+	if err != nil {
+		return nil, err
+	}
+	if len(assignments) == 0 {
+		return assignments, nil
+	}
+
+	masteries, err := s.cohortRepo.ListLearnerMastery(cohortID)
+	if err != nil {
+		return nil, err
+	}
+	byLearner := make(map[string]map[string]models.UserMastery)
+	for _, m := range masteries {
+		if byLearner[m.FirebaseUID] == nil {
+			byLearner[m.FirebaseUID] = make(map[string]models.UserMastery)
+		}
+		byLearner[m.FirebaseUID][m.TopicKey] = m
+	}
+
+	cur := cohortCurriculum(cohort)
+	now := time.Now()
+	for i := range assignments {
+		a := &assignments[i]
+		if cohort.Role == models.CohortRoleInstructor {
+			count := 0
+			for _, topics := range byLearner {
+				if assignmentCompleted(a, topics, cur) {
+					count++
+				}
+			}
+			a.CompletedCount = &count
+			continue
+		}
+		completed := assignmentCompleted(a, byLearner[firebaseUID], cur)
+		a.Completed = &completed
+		a.Overdue = !completed && now.After(a.DueAt)
+	}
+
+	return assignments, nil
+}
+
+// DeleteAssignment removes an assignment
+func (s *CohortService) DeleteAssignment(firebaseUID string, cohortID, assignmentID int64) error {
+	if _, err := s.instructor(firebaseUID, cohortID); err != nil {
+		return err
+	}
+
+	deleted, err := s.cohortRepo.DeleteAssignment(cohortID, assignmentID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAssignmentNotFound
+	}
+	return nil
+}
+
+// SetCurriculum assigns a curriculum to the cohort's learners; "" removes
+// it. Learners who chose their own curriculum keep it.
+func (s *CohortService) SetCurriculum(firebaseUID string, cohortID int64, curriculumKey string) (*models.Cohort, error) {
+	if _, err := s.instructor(firebaseUID, cohortID); err != nil {
+		return nil, err
+	}
+	if curriculumKey != "" && curriculum.Find(curriculumKey) == nil {
+		return nil, ErrUnknownCurriculum
+	}
+
+	if err := s.cohortRepo.SetCurriculum(cohortID, curriculumKey); err != nil {
+		return nil, err
+	}
+	return s.Get(firebaseUID, cohortID)
+}
+
+// Leaderboard ranks the cohort's learners by XP, all-time or in an ISO week
+func (s *CohortService) Leaderboard(firebaseUID string, cohortID int64, week string, limit, offset int) (*models.Leaderboard, error) {
+	if _, err := s.member(firebaseUID, cohortID); err != nil {
+		return nil, err
+	}
+	return s.leaderboards.Cohort(firebaseUID, cohortID, week, limit, offset)
+}
+
+// assignmentCompleted reports whether a learner with the given mastery by
+// topic has solved the assigned problem, or brought the assigned topic to
+// its threshold in the cohort's curriculum
+func assignmentCompleted(a *models.CohortAssignment, topics map[string]models.UserMastery, cur *curriculum.Curriculum) bool {
+	mastery, ok := topics[a.TopicKey]
+	if !ok {
+		return false
+	}
+	if a.ProblemID != "" {
+		return slices.Contains(mastery.SolvedProblems, a.ProblemID)
+	}
+	threshold := curriculum.DefaultThreshold
+	if topic := cur.Topic(a.TopicKey); topic != nil {
+		threshold = topic.Threshold
+	}
+	return mastery.Confidence >= threshold
+}
+
+// cohortCurriculum returns the curriculum assigned to a cohort, or the
+// standard one
+func cohortCurriculum(cohort *models.Cohort) *curriculum.Curriculum {
+	if c := curriculum.Find(cohort.Curriculum); c != nil {
+		return c
+	}
+	return &curriculum.Standard
+}
+
+// member returns the cohort if the caller belongs to it
+func (s *CohortService) member(firebaseUID string, cohortID int64) (*models.Cohort, error) {
+	cohort, err := s.cohortRepo.GetForMember(cohortID, firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	if cohort == nil {
+		return nil, ErrCohortNotFound
+	}
+	return cohort, nil
+}
+
+// instructor returns the cohort if the caller is one of its instructors
+func (s *CohortService) instructor(firebaseUID string, cohortID int64) (*models.Cohort, error) {
+	cohort, err := s.member(firebaseUID, cohortID)
+	if err != nil {
+		return nil, err
+	}
+	if cohort.Role != models.CohortRoleInstructor {
+		return nil, ErrNotCohortInstructor
+	}
+	return cohort, nil
+}
+
+// hideInstructorCode clears the instructor code for learners
+func hideInstructorCode(cohort *models.Cohort) {
+	if cohort.Role != models.CohortRoleInstructor {
+		cohort.InstructorCode = ""
+	}
+}
+
+// newInviteCode returns a random code without look-alike characters
+func newInviteCode() (string, error) {
+	buf := make([]byte, inviteCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate invite code: %w", err)
+	}
+	for i, b := range buf {
+		buf[i] = inviteCodeAlphabet[int(b)%len(inviteCodeAlphabet)]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/skilltree/internal/complexity"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/sandbox"
+)
+
+// ErrSandboxDisabled means empirical profiling was requested but no sandbox
+// is configured
+var ErrSandboxDisabled = errors.New("sandbox disabled")
+
+// ComplexityService pairs the LLM's Big-O analysis with an optional
+// empirical profile measured in the sandbox
+type ComplexityService struct {
+	geminiService *GeminiService
+	runner        *sandbox.Runner
+}
+
+// NewComplexityService creates the service; runner may be nil to disable
+// empirical profiling
+func NewComplexityService(geminiService *GeminiService, runner *sandbox.Runner) *ComplexityService {
+	return &ComplexityService{
+		geminiService: geminiService,
+		runner:        runner,
+	}
+}
+
+// Analyze asks the LLM for the code's complexity and, if requested, measures
+// it and flags where the two disagree
+func (s *ComplexityService) Analyze(ctx context.Context, firebaseUID string, req *models.ComplexityRequest) (*models.ComplexityResponse, error) {
+	if req.Empirical && s.runner == nil {
+		return nil, ErrSandboxDisabled
+	}
+
+	analysis, cached, err := s.geminiService.ComplexityAnalysis(ctx, firebaseUID, req.Code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze complexity: %w", err)
+	}
+
+	response := &models.ComplexityResponse{Analysis: analysis, Cached: cached}
+	if !req.Empirical {
+		return response, nil
+	}
+
+	result, err := s.runner.Profile(ctx, sandbox.Program{
+		Language: req.Language,
+		Code:     req.Code,
+		Entry:    req.Entry,
+		Input:    req.Input,
+	})
+	var programErr *sandbox.ProgramError
+	if errors.As(err, &programErr) {
+		response.Empirical = &models.ComplexityProfile{
+			Samples:       []models.ComplexitySample{},
+			Disagreements: []string{},
+			Error:         programErr.Message,
+		}
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response.Empirical = buildProfile(result, complexity.ParseClaim(analysis))
+	return response, nil
+}
+
+// buildProfile fits the samples and compares the fits with the claim
+func buildProfile(result *sandbox.Result, claim complexity.Claim) *models.ComplexityProfile {
+	profile := &models.ComplexityProfile{
+		Samples:       make([]models.ComplexitySample, 0, len(result.Samples)),
+		TimedOut:      result.TimedOut,
+		ClaimedTime:   claim.Time,
+		ClaimedSpace:  claim.Space,
+		Disagreements: []string{},
+	}
+
+	timePoints := make([]complexity.Point, 0, len(result.Samples))
+	memoryPoints := make([]complexity.Point, 0, len(result.Samples))
+	for _, sample := range result.Samples {
+		profile.Samples = append(profile.Samples, models.ComplexitySample(sample))
+		timePoints = append(timePoints, complexity.Point{N: float64(sample.N), Y: sample.TimeMs})
+		memoryPoints = append(memoryPoints, complexity.Point{N: float64(sample.N), Y: float64(sample.MemoryBytes)})
+	}
+
+	timeFit := complexity.FitCurve(timePoints, complexity.TimeOptions)
+	spaceFit := complexity.FitCurve(memoryPoints, complexity.MemoryOptions)
+
+	if timeFit != nil {
+		profile.TimeClass = timeFit.Class
+		profile.TimeAlternatives = timeFit.Alternatives
+		if claim.Time != "" && !timeFit.Matches(claim.Time) {
+			profile.Disagreements = append(profile.Disagreements,
+				fmt.Sprintf("time: analysis claims %s but runtime grows like %s", claim.Time, timeFit.Class))
+		}
+	} else if result.TimedOut && complexity.Rank(claim.Time) >= 0 && complexity.Rank(claim.Time) <= complexity.Rank(complexity.Linear) {
+		// A linear algorithm finishes these sizes long before the limit
+		profile.Disagreements = append(profile.Disagreements,
+			fmt.Sprintf("time: analysis claims %s but the run timed out after %d input sizes", claim.Time, len(result.Samples)))
+	}
+
+	// Measured memory includes the returned value, which analyses often
+	// leave out, so only flag using more space than claimed
+	if spaceFit != nil {
+		profile.SpaceClass = spaceFit.Class
+		if claim.Space != "" && !spaceFit.Matches(claim.Space) && complexity.Rank(spaceFit.Class) > complexity.Rank(claim.Space) {
+			profile.Disagreements = append(profile.Disagreements,
+				fmt.Sprintf("space: analysis claims %s but memory grows like %s", claim.Space, spaceFit.Class))
+		}
+	}
+
+	return profile
+}
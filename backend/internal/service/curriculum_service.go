@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/skilltree/internal/curriculum"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+var (
+	// ErrUnknownCurriculum means no curriculum in the catalog has the key
+	ErrUnknownCurriculum = errors.New("unknown curriculum")
+	// ErrTopicNotInCurriculum means the learner's curriculum leaves out the
+	// topic or problem
+	ErrTopicNotInCurriculum = errors.New("topic not in curriculum")
+	// ErrTopicLocked means the topic's prerequisites or the previous tier's
+	// checkpoint aren't done yet
+	ErrTopicLocked = errors.New("topic is locked")
+)
+
+// CurriculumService resolves each learner's active curriculum and evaluates
+// unlocks against it
+type CurriculumService struct {
+	settingsRepo   *repository.SettingsRepository
+	cohortRepo     *repository.CohortRepository
+	masteryRepo    *repository.MasteryRepository
+	checkpointRepo *repository.CheckpointRepository
+}
+
+func NewCurriculumService(
+	settingsRepo *repository.SettingsRepository,
+	cohortRepo *repository.CohortRepository,
+	masteryRepo *repository.MasteryRepository,
+	checkpointRepo *repository.CheckpointRepository,
+) *CurriculumService {
+	return &CurriculumService{
+		settingsRepo:   settingsRepo,
+		cohortRepo:     cohortRepo,
+		masteryRepo:    masteryRepo,
+		checkpointRepo: checkpointRepo,
+	}
+}
+
+// List returns the curriculum catalog
+func (s *CurriculumService) List() []curriculum.Curriculum {
+	return curriculum.Catalog
+}
+
+// Active returns the learner's curriculum: their own choice, else the one
+// of the cohort they most recently joined as a learner, else the standard
+// one. It also returns where it came from and the assigning cohort.
+func (s *CurriculumService) Active(firebaseUID string) (*curriculum.Curriculum, string, int64, error) {
+	settings, err := s.settingsRepo.Get(firebaseUID)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if c := curriculum.Find(settings.Curriculum); c != nil {
+		return c, models.CurriculumSourceUser, 0, nil
+	}
+
+	key, cohortID, err := s.cohortRepo.LearnerCurriculum(firebaseUID)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	if c := curriculum.Find(key); c != nil {
+		return c, models.CurriculumSourceCohort, cohortID, nil
+	}
+
+	return &curriculum.Standard, models.CurriculumSourceDefault, 0, nil
+}
+
+// Overview returns the learner's active curriculum with the status of each
+// topic
+func (s *CurriculumService) Overview(firebaseUID string) (*models.ActiveCurriculum, error) {
+	c, source, cohortID, err := s.Active(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.progress(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	topics := make([]models.TopicProgress, 0, len(c.Topics))
+	for _, t := range c.Topics {
+		topics = append(topics, models.TopicProgress{
+			Key:        t.Key,
+			Tier:       t.Tier,
+			Status:     c.Status(t.Key, progress),
+			Confidence: progress.Confidence[t.Key],
+			Threshold:  t.Threshold,
+		})
+	}
+
+	return &models.ActiveCurriculum{
+		Curriculum: c,
+		Source:     source,
+		CohortID:   cohortID,
+		Topics:     topics,
+	}, nil
+}
+
+// CheckAvailable returns an error unless the problem is in the learner's
+// curriculum and its topic is open to them
+func (s *CurriculumService) CheckAvailable(firebaseUID, topicKey, problemID string) error {
+	c, _, _, err := s.Active(firebaseUID)
+	if err != nil {
+		return err
+	}
+	if !problemInCurriculum(c, topicKey, problemID) {
+		return ErrTopicNotInCurriculum
+	}
+
+	progress, err := s.progress(firebaseUID)
+	if err != nil {
+		return err
+	}
+	if !c.Open(topicKey, progress) {
+		return ErrTopicLocked
+	}
+	return nil
+}
+
+// progress loads the confidence and checkpoint results unlocks depend on
+func (s *CurriculumService) progress(firebaseUID string) (curriculum.Progress, error) {
+	masteries, err := s.masteryRepo.GetAllByUserID(firebaseUID)
+	if err != nil {
+		return curriculum.Progress{}, fmt.Errorf("failed to get mastery: %w", err)
+	}
+	passed, err := s.checkpointRepo.PassedByTier(firebaseUID)
+	if err != nil {
+		return curriculum.Progress{}, err
+	}
+
+	return curriculum.Progress{
+		Confidence:       confidenceByTopic(masteries),
+		CheckpointPassed: passed,
+	}, nil
+}
+
+// confidenceByTopic indexes mastery records by topic
+func confidenceByTopic(masteries []models.UserMastery) map[string]int {
+	confidence := make(map[string]int, len(masteries))
+	for _, m := range masteries {
+		confidence[m.TopicKey] = m.Confidence
+	}
+	return confidence
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// ErrAIUnavailable means Gemini is down or overloaded (circuit open or
+// retries exhausted); callers should degrade rather than fail hard
+var ErrAIUnavailable = errors.New("AI provider temporarily unavailable")
+
+// Retry policy for Gemini calls
+const (
+	geminiMaxAttempts    = 3
+	geminiRetryBaseDelay = 500 * time.Millisecond
+	// geminiRetryMaxDelay also caps how long we honor Retry-After; longer
+	// waits would outlast the client's request
+	geminiRetryMaxDelay = 8 * time.Second
+)
+
+// GeminiAPIError is a non-200 response from the Gemini API
+type GeminiAPIError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Body       string
+}
+
+func (e *GeminiAPIError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the status is transient (rate limited or a
+// server-side failure) rather than a problem with our request
+func (e *GeminiAPIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isRetryableGeminiError classifies an error from callGemini: transient API
+// statuses and network failures are retried, everything else (bad requests,
+// unparsable bodies, cancelled contexts) is fatal
+func isRetryableGeminiError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var apiErr *GeminiAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Retryable()
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// retryAfterOf returns the server-requested delay carried by err, if any
+func retryAfterOf(err error) time.Duration {
+	var apiErr *GeminiAPIError
+	if errors.As(err, &apiErr) {
+		return apiErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or HTTP-date form
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(at); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+// backoffDelay is exponential backoff with full jitter, never shorter than
+// the server's Retry-After
+func backoffDelay(attempt int, retryAfter time.Duration) time.Duration {
+	ceiling := geminiRetryBaseDelay << attempt
+	if ceiling > geminiRetryMaxDelay {
+		ceiling = geminiRetryMaxDelay
+	}
+
+	delay := time.Duration(rand.Int63n(int64(ceiling)))
+	if retryAfter > delay {
+		delay = retryAfter
+	}
+	return delay
+}
@@ -2,27 +2,105 @@ package service
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/prompts"
+	"github.com/yourusername/skilltree/internal/security"
 )
 
+// TokenUsage is the token accounting Gemini returns in usageMetadata
+type TokenUsage struct {
+	PromptTokens     int `json:"promptTokenCount"`
+	CompletionTokens int `json:"candidatesTokenCount"`
+	TotalTokens      int `json:"totalTokenCount"`
+}
+
+// UsageRecorder receives the token usage of every successful Gemini call
+type UsageRecorder interface {
+	RecordUsage(firebaseUID string, usage TokenUsage) error
+}
+
 type GeminiService struct {
-	apiKey string
-	apiURL string
-	client *http.Client
+	apiKey  string
+	apiURL  string
+	client  *http.Client
+	usage   UsageRecorder
+	cache   *ResponseCache
+	breaker *CircuitBreaker
+	prompts *prompts.Registry
 }
 
-func NewGeminiService(apiKey, apiURL string) *GeminiService {
+func NewGeminiService(apiKey, apiURL string, promptRegistry *prompts.Registry) *GeminiService {
 	return &GeminiService{
-		apiKey: apiKey,
-		apiURL: apiURL,
+		apiKey:  apiKey,
+		apiURL:  apiURL,
+		prompts: promptRegistry,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		breaker: NewCircuitBreaker(5, 30*time.Second),
+	}
+}
+
+// SetUsageRecorder attributes token usage to the firebaseUID each call is
+// made for; calls with an empty UID (e.g. CLI tools) aren't recorded
+func (g *GeminiService) SetUsageRecorder(recorder UsageRecorder) {
+	g.usage = recorder
+}
+
+// SetCache enables response caching for deterministic calls
+func (g *GeminiService) SetCache(cache *ResponseCache) {
+	g.cache = cache
+}
+
+// SetTransport replaces the HTTP transport (e.g. with a fixture recorder)
+func (g *GeminiService) SetTransport(transport http.RoundTripper) {
+	g.client.Transport = transport
+}
+
+// SetCircuitBreaker replaces the default circuit breaker
+func (g *GeminiService) SetCircuitBreaker(breaker *CircuitBreaker) {
+	g.breaker = breaker
+}
+
+// BreakerStatus reports the circuit breaker state for health checks
+func (g *GeminiService) BreakerStatus() BreakerStatus {
+	return g.breaker.Status()
+}
+
+// RetryAfter is how long callers should wait before the breaker admits calls
+// again (0 when closed)
+func (g *GeminiService) RetryAfter() time.Duration {
+	status := g.breaker.Status()
+	if status.RetryAt == nil {
+		return 0
+	}
+	if wait := time.Until(*status.RetryAt); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Model returns the model name from the configured API URL
+// (e.g. ".../models/gemini-2.5-flash:generateContent" -> "gemini-2.5-flash")
+func (g *GeminiService) Model() string {
+	model := g.apiURL
+	if i := strings.LastIndex(model, "/models/"); i >= 0 {
+		model = model[i+len("/models/"):]
+	}
+	if i := strings.Index(model, ":"); i >= 0 {
+		model = model[:i]
 	}
+	return model
 }
 
 type geminiRequest struct {
@@ -49,95 +127,91 @@ type geminiResponse struct {
 			} `json:"parts"`
 		} `json:"content"`
 	} `json:"candidates"`
+	UsageMetadata *TokenUsage `json:"usageMetadata,omitempty"`
 }
 
 // ChatCompletion sends a chat message to Gemini and returns the response
-func (g *GeminiService) ChatCompletion(prompt, systemPrompt string) (string, error) {
-	return g.callGeminiWithRetry(prompt, systemPrompt, false)
+func (g *GeminiService) ChatCompletion(ctx context.Context, firebaseUID, prompt, systemPrompt string) (string, error) {
+	return g.callGeminiWithRetry(ctx, firebaseUID, prompt, systemPrompt, false)
 }
 
-// ComplexityAnalysis analyzes code complexity
-func (g *GeminiService) ComplexityAnalysis(code string) (string, error) {
-	systemPrompt := `You are a Performance Engineer. Analyze the user's code for:
-1. Time Complexity (Big O)
-2. Space Complexity (Big O)
-3. Identify the bottleneck line of code.
-Be extremely concise. Use markdown.`
+// ArchitectChat answers a learner's question as the topic's Architect
+func (g *GeminiService) ArchitectChat(ctx context.Context, firebaseUID, topic, message string) (string, error) {
+	prompt, err := g.prompts.Render(prompts.ArchitectChat, map[string]interface{}{
+		"Topic":   topic,
+		"Message": message,
+	})
+	if err != nil {
+		return "", err
+	}
 
-	return g.callGeminiWithRetry(code, systemPrompt, false)
+	return g.callGeminiWithRetry(ctx, firebaseUID, prompt.User, prompt.System, false)
 }
 
-// JudgeAudit validates code against a pattern and returns verdict
-func (g *GeminiService) JudgeAudit(code, topic, problem, invariant string) (map[string]interface{}, error) {
-	prompt := fmt.Sprintf(`
-Pattern: %s
-Problem: %s
-Invariant Strategy: %s
-User Code:
-%s
-`, topic, problem, invariant, code)
-
-	systemPrompt := `
-You are a ruthless Senior Engineer Auditor.
-Your Goal: Verify the user used the specific O-notation strategy and Invariant for the given pattern.
-1. If they used Brute Force instead of the Pattern, REJECT.
-2. If they ignored the Invariant, REJECT.
-3. If code is optimal, ADVANCE.
-Output JSON: { "verdict": "ADVANCE" or "REPEAT", "feedback": "Short, sharp technical critique." }`
-
-	resultStr, err := g.callGeminiWithRetry(prompt, systemPrompt, true)
+// ComplexityAnalysis analyzes code complexity; cached reports whether the
+// analysis came from the response cache
+func (g *GeminiService) ComplexityAnalysis(ctx context.Context, firebaseUID, code string) (analysis string, cached bool, err error) {
+	prompt, err := g.prompts.Render(prompts.ComplexityAnalysis, map[string]interface{}{
+		"Code": code,
+	})
 	if err != nil {
-		return nil, err
+		return "", false, err
+	}
+
+	key := CacheKey("gemini", g.Model(), prompt.Fingerprint, code)
+	return g.callGeminiCached(ctx, firebaseUID, key, prompt.User, prompt.System, false)
+}
+
+// JudgeAudit validates code against a pattern and returns verdict; cached
+// reports whether the verdict came from the response cache. The result
+// carries the prompt version under "prompt_version".
+func (g *GeminiService) JudgeAudit(ctx context.Context, firebaseUID, code, topic, problem, invariant string) (result map[string]interface{}, cached bool, err error) {
+	prompt, err := g.prompts.Render(prompts.JudgeAudit, map[string]interface{}{
+		"Topic":     topic,
+		"Problem":   problem,
+		"Invariant": invariant,
+		"Code":      code,
+		"Boundary":  security.Boundary(code),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := CacheKey("gemini", g.Model(), prompt.Fingerprint, code, topic, problem, invariant)
+	resultStr, cached, err := g.callGeminiCached(ctx, firebaseUID, key, prompt.User, prompt.System, true)
+	if err != nil {
+		return nil, false, err
 	}
 
 	// Parse JSON response
-	var result map[string]interface{}
 	if err := json.Unmarshal([]byte(resultStr), &result); err != nil {
 		// If parsing fails, return error verdict
 		return map[string]interface{}{
-			"verdict":  "ERROR",
-			"feedback": "Failed to parse AI response",
-		}, nil
+			"verdict":        "ERROR",
+			"feedback":       "Failed to parse AI response",
+			"prompt_version": prompt.Version,
+		}, false, nil
 	}
+	result["prompt_version"] = prompt.Version
 
-	return result, nil
+	return result, cached, nil
 }
 
-// JudgeCheckpoint validates checkpoint code against multiple required patterns
-func (g *GeminiService) JudgeCheckpoint(code string, tier int, requiredPatterns []string, problemDescription string) (map[string]interface{}, error) {
-	prompt := fmt.Sprintf(`
-Tier %d Checkpoint Problem:
-%s
-
-Required Patterns (ALL must be present):
-%v
-
-User Code:
-%s
-`, tier, problemDescription, requiredPatterns, code)
-
-	systemPrompt := `
-You are an EXTREMELY STRICT checkpoint auditor.
-This is a TIER CHECKPOINT - user must demonstrate mastery of ALL required patterns.
-
-Critical Rules:
-1. ALL patterns listed must be EXPLICITLY present in the code
-2. If even ONE pattern is missing or implemented via brute force, REJECT immediately
-3. Code must be optimal for ALL patterns (no O(N^2) when O(N) is possible with the pattern)
-4. Verify pattern correctness (e.g., real binary search with log(N), not linear scan)
-5. No shortcuts - each pattern must be properly implemented
-
-Output JSON format:
-{
-  "verdict": "ADVANCE" or "REPEAT",
-  "feedback": "Detailed critique of each pattern implementation (which worked, which didn't, and why)",
-  "patterns_found": ["PATTERN1", "PATTERN2"],
-  "missing_patterns": ["PATTERN3"]
-}
-
-If even ONE pattern is missing or incorrectly implemented, verdict MUST be "REPEAT".`
+// JudgeCheckpoint validates checkpoint code against multiple required
+// patterns. The result carries the prompt version under "prompt_version".
+func (g *GeminiService) JudgeCheckpoint(ctx context.Context, firebaseUID, code string, tier int, requiredPatterns []string, problemDescription string) (map[string]interface{}, error) {
+	prompt, err := g.prompts.Render(prompts.JudgeCheckpoint, map[string]interface{}{
+		"Tier":             tier,
+		"Description":      problemDescription,
+		"RequiredPatterns": requiredPatterns,
+		"Code":             code,
+		"Boundary":         security.Boundary(code),
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	resultStr, err := g.callGeminiWithRetry(prompt, systemPrompt, true)
+	resultStr, err := g.callGeminiWithRetry(ctx, firebaseUID, prompt.User, prompt.System, true)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +225,7 @@ If even ONE pattern is missing or incorrectly implemented, verdict MUST be "REPE
 			"feedback":         "Failed to parse AI response",
 			"patterns_found":   []string{},
 			"missing_patterns": requiredPatterns,
+			"prompt_version":   prompt.Version,
 		}, nil
 	}
 
@@ -167,34 +242,251 @@ If even ONE pattern is missing or incorrectly implemented, verdict MUST be "REPE
 	if result["missing_patterns"] == nil {
 		result["missing_patterns"] = []string{}
 	}
+	result["prompt_version"] = prompt.Version
 
 	return result, nil
 }
 
-// callGeminiWithRetry calls Gemini API with exponential backoff retry logic
-func (g *GeminiService) callGeminiWithRetry(prompt, systemPrompt string, jsonMode bool) (string, error) {
-	maxRetries := 3
-	delay := time.Second
+// GenerateHint writes a hint at the given ladder level for the learner's
+// current code; cached reports whether it came from the response cache
+func (g *GeminiService) GenerateHint(ctx context.Context, firebaseUID, level, code, topic, problem, description, invariant string) (hint string, cached bool, err error) {
+	prompt, err := g.prompts.Render(prompts.Hint, map[string]interface{}{
+		"Level":       level,
+		"Topic":       topic,
+		"Problem":     problem,
+		"Description": description,
+		"Invariant":   invariant,
+		"Code":        code,
+		"Boundary":    security.Boundary(code),
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	key := CacheKey("gemini", g.Model(), prompt.Fingerprint, code, level, topic, problem, description, invariant)
+	return g.callGeminiCached(ctx, firebaseUID, key, prompt.User, prompt.System, false)
+}
+
+// CodeReview reviews code line by line. topic, problem and invariant may be
+// empty for a review that isn't tied to a problem. Findings are returned as
+// the model wrote them; callers should validate the line ranges.
+func (g *GeminiService) CodeReview(ctx context.Context, firebaseUID, code, topic, problem, invariant string) (*models.CodeReviewResponse, error) {
+	prompt, err := g.prompts.Render(prompts.CodeReview, map[string]interface{}{
+		"Topic":        topic,
+		"Problem":      problem,
+		"Invariant":    invariant,
+		"NumberedCode": numberLines(code),
+		"Boundary":     security.Boundary(code),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := CacheKey("gemini", g.Model(), prompt.Fingerprint, code, topic, problem, invariant)
+	resultStr, cached, err := g.callGeminiCached(ctx, firebaseUID, key, prompt.User, prompt.System, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var review models.CodeReviewResponse
+	if err := json.Unmarshal([]byte(resultStr), &review); err != nil {
+		return nil, fmt.Errorf("failed to parse code review: %w", err)
+	}
+	review.PromptVersion = prompt.Version
+	review.Cached = cached
+
+	return &review, nil
+}
+
+// GenerateExplainQuiz writes count questions about the learner's code that
+// probe its invariant and complexity. Returns the prompt version used.
+func (g *GeminiService) GenerateExplainQuiz(ctx context.Context, firebaseUID, code, problem, invariant string, count int) ([]models.QuizQuestion, string, error) {
+	prompt, err := g.prompts.Render(prompts.ExplainQuiz, map[string]interface{}{
+		"Count":     count,
+		"Problem":   problem,
+		"Invariant": invariant,
+		"Code":      code,
+		"Boundary":  security.Boundary(code),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	resultStr, err := g.callGeminiWithRetry(ctx, firebaseUID, prompt.User, prompt.System, true)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var result struct {
+		Questions []models.QuizQuestion `json:"questions"`
+	}
+	if err := json.Unmarshal([]byte(resultStr), &result); err != nil {
+		return nil, "", fmt.Errorf("failed to parse quiz questions: %w", err)
+	}
+	if len(result.Questions) == 0 {
+		return nil, "", fmt.Errorf("failed to parse quiz questions: none returned")
+	}
+	if len(result.Questions) > count {
+		result.Questions = result.Questions[:count]
+	}
+	for i := range result.Questions {
+		result.Questions[i].ID = i + 1
+	}
+
+	return result.Questions, prompt.Version, nil
+}
+
+// GradeExplainQuiz scores the learner's answers from 0 to 100 each
+func (g *GeminiService) GradeExplainQuiz(ctx context.Context, firebaseUID, code, problem string, questions []models.QuizQuestion, answers map[int]string) ([]models.QuizGrade, string, error) {
+	// The boundary must not appear in the answers either
+	var answerText strings.Builder
+	for _, q := range questions {
+		answerText.WriteString(answers[q.ID])
+	}
+	prompt, err := g.prompts.Render(prompts.ExplainQuizGrade, map[string]interface{}{
+		"Problem":   problem,
+		"Code":      code,
+		"Questions": questions,
+		"Answers":   answers,
+		"Boundary":  security.Boundary(code + answerText.String()),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	resultStr, err := g.callGeminiWithRetry(ctx, firebaseUID, prompt.User, prompt.System, true)
+	if err != nil {
+		return nil, "", err
+	}
 
+	var result struct {
+		Grades   []models.QuizGrade `json:"grades"`
+		Feedback string             `json:"feedback"`
+	}
+	if err := json.Unmarshal([]byte(resultStr), &result); err != nil {
+		return nil, "", fmt.Errorf("failed to parse quiz grades: %w", err)
+	}
+
+	return result.Grades, result.Feedback, nil
+}
+
+// ProblemBrief describes the problem GenerateProblem should draft
+type ProblemBrief struct {
+	Topic       string
+	TopicLabel  string
+	TopicTheory string
+	Pattern     string
+	Difficulty  string
+	Language    string
+	Entry       string
+	// Tests is how many test cases to write
+	Tests int
+	// Existing are titles the draft must not repeat
+	Existing []string
+	// Feedback explains why the previous draft failed validation
+	Feedback string
+}
+
+// GenerateProblem drafts a practice problem with a reference solution and
+// tests. Drafts are never cached: a retry with feedback must reach the
+// model. Returns the prompt version used.
+func (g *GeminiService) GenerateProblem(ctx context.Context, firebaseUID string, brief ProblemBrief) (*models.ProblemDraft, string, error) {
+	prompt, err := g.prompts.Render(prompts.ProblemGeneration, brief)
+	if err != nil {
+		return nil, "", err
+	}
+
+	resultStr, err := g.callGeminiWithRetry(ctx, firebaseUID, prompt.User, prompt.System, true)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var draft models.ProblemDraft
+	if err := json.Unmarshal([]byte(resultStr), &draft); err != nil {
+		return nil, "", fmt.Errorf("failed to parse problem draft: %w", err)
+	}
+
+	return &draft, prompt.Version, nil
+}
+
+// callGeminiCached serves a response from the cache when possible and
+// caches fresh responses; cache hits cost no quota
+func (g *GeminiService) callGeminiCached(ctx context.Context, firebaseUID, key, prompt, systemPrompt string, jsonMode bool) (string, bool, error) {
+	if g.cache != nil {
+		if response, ok := g.cache.Get(key); ok {
+			return response, true, nil
+		}
+	}
+
+	response, err := g.callGeminiWithRetry(ctx, firebaseUID, prompt, systemPrompt, jsonMode)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Don't cache unparseable JSON so a retry gets a fresh answer
+	if g.cache != nil && (!jsonMode || json.Valid([]byte(response))) {
+		g.cache.Set(key, response)
+	}
+
+	return response, false, nil
+}
+
+// callGeminiWithRetry calls Gemini, retrying transient failures with jittered
+// exponential backoff. The circuit breaker fails calls fast during outages.
+func (g *GeminiService) callGeminiWithRetry(ctx context.Context, firebaseUID, prompt, systemPrompt string, jsonMode bool) (string, error) {
 	var lastErr error
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		result, err := g.callGemini(prompt, systemPrompt, jsonMode)
+	for attempt := 0; attempt < geminiMaxAttempts; attempt++ {
+		if allowed, _ := g.breaker.Allow(); !allowed {
+			if lastErr != nil {
+				return "", fmt.Errorf("%w: circuit opened after: %v", ErrAIUnavailable, lastErr)
+			}
+			return "", fmt.Errorf("%w: circuit open", ErrAIUnavailable)
+		}
+
+		result, err := g.callGemini(ctx, firebaseUID, prompt, systemPrompt, jsonMode)
 		if err == nil {
+			g.breaker.Success()
 			return result, nil
 		}
 
 		lastErr = err
-		if attempt < maxRetries-1 {
-			time.Sleep(delay)
-			delay *= 2 // Exponential backoff
+		if !isRetryableGeminiError(ctx, err) {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				// The request's time budget ran out: answer as if the API were down
+				g.breaker.Release()
+				return "", fmt.Errorf("%w: request timed out: %v", ErrAIUnavailable, err)
+			}
+			if ctx.Err() != nil {
+				g.breaker.Release()
+			} else {
+				// The API answered, so it is up; the request itself is bad
+				g.breaker.Success()
+			}
+			return "", err
+		}
+		g.breaker.Failure()
+
+		if attempt < geminiMaxAttempts-1 {
+			retryAfter := retryAfterOf(err)
+			if retryAfter > geminiRetryMaxDelay {
+				break // Not worth holding the request open that long
+			}
+			select {
+			case <-ctx.Done():
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					return "", fmt.Errorf("%w: request timed out: %v", ErrAIUnavailable, lastErr)
+				}
+				return "", ctx.Err()
+			case <-time.After(backoffDelay(attempt, retryAfter)):
+			}
 		}
 	}
 
-	return "", fmt.Errorf("gemini API failed after %d attempts: %w", maxRetries, lastErr)
+	return "", fmt.Errorf("%w: gemini API failed: %v", ErrAIUnavailable, lastErr)
 }
 
 // callGemini makes a single API call to Gemini
-func (g *GeminiService) callGemini(prompt, systemPrompt string, jsonMode bool) (string, error) {
+func (g *GeminiService) callGemini(ctx context.Context, firebaseUID, prompt, systemPrompt string, jsonMode bool) (string, error) {
 	req := geminiRequest{
 		Contents: []struct {
 			Parts []struct {
@@ -243,7 +535,7 @@ func (g *GeminiService) callGemini(prompt, systemPrompt string, jsonMode bool) (
 
 	// Create HTTP request
 	url := fmt.Sprintf("%s?key=%s", g.apiURL, g.apiKey)
-	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(body))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -264,7 +556,11 @@ func (g *GeminiService) callGemini(prompt, systemPrompt string, jsonMode bool) (
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+		return "", &GeminiAPIError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+			Body:       string(respBody),
+		}
 	}
 
 	// Parse response
@@ -273,6 +569,9 @@ func (g *GeminiService) callGemini(prompt, systemPrompt string, jsonMode bool) (
 		return "", fmt.Errorf("failed to parse response: %w", err)
 	}
 
+	// Attribute token usage to the calling user
+	g.recordUsage(firebaseUID, geminiResp.UsageMetadata)
+
 	if len(geminiResp.Candidates) == 0 || len(geminiResp.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("empty response from Gemini")
 	}
@@ -291,3 +590,14 @@ func (g *GeminiService) callGemini(prompt, systemPrompt string, jsonMode bool) (
 
 	return text, nil
 }
+
+// recordUsage reports token usage for firebaseUID; failures are only logged
+func (g *GeminiService) recordUsage(firebaseUID string, usage *TokenUsage) {
+	if g.usage == nil || usage == nil || firebaseUID == "" {
+		return
+	}
+
+	if err := g.usage.RecordUsage(firebaseUID, *usage); err != nil {
+		log.Printf("Failed to record LLM usage for %s: %v", firebaseUID, err)
+	}
+}
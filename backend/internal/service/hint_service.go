@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+var (
+	// ErrInvalidHintLevel means the requested level is not on the ladder
+	ErrInvalidHintLevel = errors.New("invalid hint level")
+	// ErrHintLevelLocked means the user asked for a level above the next rung
+	ErrHintLevelLocked = errors.New("hint level locked")
+)
+
+// HintService serves the nudge → approach → pseudocode hint ladder and
+// tracks how far up it each user went per problem
+type HintService struct {
+	geminiService *GeminiService
+	hintRepo      *repository.HintRepository
+	// penalties[i] is the percent of credit lost once level i+1 is used
+	penalties []int
+	problems  *ProblemService
+}
+
+func NewHintService(geminiService *GeminiService, hintRepo *repository.HintRepository, penalties []int) *HintService {
+	return &HintService{
+		geminiService: geminiService,
+		hintRepo:      hintRepo,
+		penalties:     penalties,
+	}
+}
+
+// SetProblemService also serves hints for published authored problems
+func (s *HintService) SetProblemService(problems *ProblemService) {
+	s.problems = problems
+}
+
+// Hint generates a hint for the problem. Levels must be climbed in order;
+// repeating a level already unlocked is allowed.
+func (s *HintService) Hint(ctx context.Context, firebaseUID string, req *models.HintRequest) (*models.HintResponse, error) {
+	problem, _, err := s.problems.Find(req.TopicKey, req.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+
+	used, _, _, err := s.hintRepo.GetUsage(firebaseUID, req.TopicKey, req.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+
+	level := used + 1
+	if level > len(models.HintLevels) {
+		level = len(models.HintLevels)
+	}
+	if req.Level != "" {
+		requested := hintRank(req.Level)
+		if requested == 0 {
+			return nil, ErrInvalidHintLevel
+		}
+		if requested > used+1 {
+			return nil, ErrHintLevelLocked
+		}
+		level = requested
+	}
+
+	levelName := models.HintLevels[level-1]
+	hint, _, err := s.geminiService.GenerateHint(ctx, firebaseUID, levelName, req.Code, req.TopicKey, problem.Title, problem.Description, problem.Invariant)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only count hints that were actually delivered
+	if err := s.hintRepo.RecordUse(firebaseUID, req.TopicKey, req.ProblemID, level); err != nil {
+		return nil, err
+	}
+	if level > used {
+		used = level
+	}
+
+	return &models.HintResponse{
+		Level:         levelName,
+		Hint:          hint,
+		NextLevel:     nextHintLevel(used),
+		CreditPenalty: s.penaltyFor(used),
+	}, nil
+}
+
+// GetUsage reports the user's hint usage on a problem
+func (s *HintService) GetUsage(firebaseUID, topicKey, problemID string) (*models.HintUsage, error) {
+	if _, _, err := s.problems.Find(topicKey, problemID); err != nil {
+		return nil, err
+	}
+
+	used, count, lastUsedAt, err := s.hintRepo.GetUsage(firebaseUID, topicKey, problemID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.HintUsage{
+		TopicKey:      topicKey,
+		ProblemID:     problemID,
+		HintCount:     count,
+		NextLevel:     nextHintLevel(used),
+		CreditPenalty: s.penaltyFor(used),
+		LastUsedAt:    lastUsedAt,
+	}
+	if used > 0 {
+		usage.MaxLevel = models.HintLevels[used-1]
+	}
+
+	return usage, nil
+}
+
+// CreditPenalty returns the percent of judge credit the user loses on a
+// problem because of the hints they took
+func (s *HintService) CreditPenalty(firebaseUID, topicKey, problemID string) (int, error) {
+	used, _, _, err := s.hintRepo.GetUsage(firebaseUID, topicKey, problemID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get hint usage: %w", err)
+	}
+	return s.penaltyFor(used), nil
+}
+
+func (s *HintService) penaltyFor(level int) int {
+	if level <= 0 || level > len(s.penalties) {
+		return 0
+	}
+	return s.penalties[level-1]
+}
+
+// hintRank returns the 1-based position of a level on the ladder, or 0
+func hintRank(level string) int {
+	for i, name := range models.HintLevels {
+		if name == level {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func nextHintLevel(used int) *string {
+	if used >= len(models.HintLevels) {
+		return nil
+	}
+	next := models.HintLevels[used]
+	return &next
+}
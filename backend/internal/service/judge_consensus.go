@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// Consensus modes for the checkpoint judge panel
+const (
+	ConsensusMajority  = "majority"
+	ConsensusUnanimous = "unanimous"
+)
+
+// VerdictNeedsReview is returned when the judges split and a mentor must decide
+const VerdictNeedsReview = "NEEDS_REVIEW"
+
+// VerdictUnavailable is the degraded practice verdict while the AI is down
+const VerdictUnavailable = "UNAVAILABLE"
+
+// CheckpointJudge is anything that can judge a checkpoint submission
+type CheckpointJudge interface {
+	JudgeCheckpoint(ctx context.Context, firebaseUID, code string, tier int, requiredPatterns []string, problemDescription string) (map[string]interface{}, error)
+}
+
+// JudgePanelMember is a named judge on the consensus panel. Members can
+// differ by model, by prompt templates or by provider: anything that
+// implements CheckpointJudge can sit on the panel.
+type JudgePanelMember struct {
+	Name  string
+	Judge CheckpointJudge
+}
+
+// ConsensusResult is the combined outcome of all judge votes
+type ConsensusResult struct {
+	Verdict         string
+	Feedback        string
+	PatternsFound   []string
+	MissingPatterns []string
+	Votes           []models.JudgeVote
+	// Agreement is the share of valid votes that match the final verdict
+	Agreement float64
+	// PromptVersion is the judge prompt of the first vote; each vote
+	// records its own
+	PromptVersion string
+}
+
+// ConsensusJudge runs several independent judge calls and votes on the verdict
+type ConsensusJudge struct {
+	panel []JudgePanelMember
+	votes int
+	mode  string
+}
+
+// NewConsensusJudge creates a judge that casts the given number of votes,
+// spread round-robin over the panel members. With a single vote it behaves
+// exactly like calling the first panel member directly.
+func NewConsensusJudge(panel []JudgePanelMember, votes int, mode string) *ConsensusJudge {
+	if votes < 1 {
+		votes = 1
+	}
+	if mode != ConsensusUnanimous {
+		mode = ConsensusMajority
+	}
+	return &ConsensusJudge{
+		panel: panel,
+		votes: votes,
+		mode:  mode,
+	}
+}
+
+// JudgeCheckpoint collects all votes in parallel and reduces them to a verdict
+func (c *ConsensusJudge) JudgeCheckpoint(ctx context.Context, firebaseUID, code string, tier int, requiredPatterns []string, problemDescription string) (*ConsensusResult, error) {
+	if len(c.panel) == 0 {
+		return nil, fmt.Errorf("judge panel is empty")
+	}
+
+	votes := make([]models.JudgeVote, c.votes)
+	results := make([]map[string]interface{}, c.votes)
+	errs := make([]error, c.votes)
+
+	var wg sync.WaitGroup
+	for i := 0; i < c.votes; i++ {
+		member := c.panel[i%len(c.panel)]
+		votes[i].Judge = member.Name
+
+		wg.Add(1)
+		go func(i int, member JudgePanelMember) {
+			defer wg.Done()
+
+			result, err := member.Judge.JudgeCheckpoint(ctx, firebaseUID, code, tier, requiredPatterns, problemDescription)
+			if err != nil {
+				votes[i].Verdict = "ERROR"
+				votes[i].Error = err.Error()
+				errs[i] = err
+				return
+			}
+
+			verdict, _ := result["verdict"].(string)
+			feedback, _ := result["feedback"].(string)
+			votes[i].Verdict = strings.ToUpper(verdict)
+			votes[i].Feedback = feedback
+			votes[i].PromptVersion, _ = result["prompt_version"].(string)
+			results[i] = result
+		}(i, member)
+	}
+	wg.Wait()
+
+	// Surface a provider outage as such so the caller can degrade gracefully
+	if err := allUnavailable(errs); err != nil {
+		return nil, fmt.Errorf("all %d judge calls failed: %w", len(errs), err)
+	}
+
+	return c.tally(votes, results, requiredPatterns)
+}
+
+// tally applies the voting mode to the collected votes
+func (c *ConsensusJudge) tally(votes []models.JudgeVote, results []map[string]interface{}, requiredPatterns []string) (*ConsensusResult, error) {
+	advance, repeat := 0, 0
+	var lastErr string
+	for _, vote := range votes {
+		switch vote.Verdict {
+		case "ADVANCE":
+			advance++
+		case "REPEAT":
+			repeat++
+		default:
+			if vote.Error != "" {
+				lastErr = vote.Error
+			}
+		}
+	}
+
+	valid := advance + repeat
+	if valid == 0 {
+		// Every judge call failed or returned garbage
+		if lastErr != "" {
+			return nil, fmt.Errorf("all %d judge calls failed: %s", len(votes), lastErr)
+		}
+		return &ConsensusResult{
+			Verdict:         "ERROR",
+			Feedback:        "Failed to parse AI response",
+			PatternsFound:   []string{},
+			MissingPatterns: requiredPatterns,
+			Votes:           votes,
+			PromptVersion:   promptVersionOf(results),
+		}, nil
+	}
+
+	verdict := VerdictNeedsReview
+	switch c.mode {
+	case ConsensusUnanimous:
+		if advance == valid {
+			verdict = "ADVANCE"
+		} else if repeat == valid {
+			verdict = "REPEAT"
+		}
+	default:
+		if advance*2 > valid {
+			verdict = "ADVANCE"
+		} else if repeat*2 > valid {
+			verdict = "REPEAT"
+		}
+	}
+
+	result := &ConsensusResult{
+		Verdict:         verdict,
+		PatternsFound:   []string{},
+		MissingPatterns: []string{},
+		Votes:           votes,
+		PromptVersion:   promptVersionOf(results),
+	}
+
+	if verdict == VerdictNeedsReview {
+		result.Agreement = float64(max(advance, repeat)) / float64(valid)
+		result.Feedback = fmt.Sprintf("The judges disagreed (%d ADVANCE vs %d REPEAT). A mentor will review this attempt.", advance, repeat)
+		return result, nil
+	}
+
+	agreeing := advance
+	if verdict == "REPEAT" {
+		agreeing = repeat
+	}
+	result.Agreement = float64(agreeing) / float64(valid)
+
+	// Report the first agreeing judge's critique and pattern breakdown
+	for i, vote := range votes {
+		if vote.Verdict == verdict {
+			result.Feedback = vote.Feedback
+			result.PatternsFound = convertToStringSlice(results[i]["patterns_found"])
+			result.MissingPatterns = convertToStringSlice(results[i]["missing_patterns"])
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// allUnavailable returns the first error if every vote failed because the
+// AI provider was unavailable, nil otherwise
+func allUnavailable(errs []error) error {
+	for _, err := range errs {
+		if !errors.Is(err, ErrAIUnavailable) {
+			return nil
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs[0]
+}
+
+// promptVersionOf returns the prompt version reported by the first judge
+// that answered
+func promptVersionOf(results []map[string]interface{}) string {
+	for _, result := range results {
+		if version, ok := result["prompt_version"].(string); ok {
+			return version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/yourusername/skilltree/internal/models"
+)
+
+// fakeJudge answers every call with the same verdict, or fails with err
+type fakeJudge struct {
+	verdict string
+	err     error
+}
+
+func (f fakeJudge) JudgeCheckpoint(ctx context.Context, firebaseUID, code string, tier int, requiredPatterns []string, problemDescription string) (map[string]interface{}, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return map[string]interface{}{
+		"verdict":          f.verdict,
+		"feedback":         f.verdict + " feedback",
+		"patterns_found":   []interface{}{"Stack"},
+		"missing_patterns": []interface{}{},
+		"prompt_version":   "v1",
+	}, nil
+}
+
+// ballot builds the votes and judge results tally sees for the given verdicts.
+// "ERROR" stands for a failed call and "GARBAGE" for an unparseable answer.
+func ballot(verdicts ...string) ([]models.JudgeVote, []map[string]interface{}) {
+	votes := make([]models.JudgeVote, len(verdicts))
+	results := make([]map[string]interface{}, len(verdicts))
+	for i, verdict := range verdicts {
+		votes[i].Judge = fmt.Sprintf("judge-%d", i)
+		switch verdict {
+		case "ERROR":
+			votes[i].Verdict = "ERROR"
+			votes[i].Error = "judge unreachable"
+			continue
+		case "GARBAGE":
+			verdict = ""
+		}
+		votes[i].Verdict = verdict
+		votes[i].Feedback = fmt.Sprintf("%s from judge-%d", verdict, i)
+		results[i] = map[string]interface{}{
+			"patterns_found":   []interface{}{fmt.Sprintf("pattern-%d", i)},
+			"missing_patterns": []interface{}{},
+		}
+	}
+	return votes, results
+}
+
+func TestConsensusTally(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          string
+		verdicts      []string
+		wantVerdict   string
+		wantAgreement float64
+		wantFeedback  string
+		wantErr       bool
+	}{
+		{"single vote", ConsensusMajority, []string{"ADVANCE"}, "ADVANCE", 1, "ADVANCE from judge-0", false},
+		{"clear majority", ConsensusMajority, []string{"REPEAT", "ADVANCE", "ADVANCE"}, "ADVANCE", 2.0 / 3, "ADVANCE from judge-1", false},
+		{"majority repeat", ConsensusMajority, []string{"REPEAT", "REPEAT", "ADVANCE"}, "REPEAT", 2.0 / 3, "REPEAT from judge-0", false},
+		{"tied vote", ConsensusMajority, []string{"ADVANCE", "REPEAT"}, VerdictNeedsReview, 0.5, "", false},
+		{"failed votes don't count", ConsensusMajority, []string{"ERROR", "ADVANCE", "GARBAGE"}, "ADVANCE", 1, "ADVANCE from judge-1", false},
+		{"tie after a failed vote", ConsensusMajority, []string{"ADVANCE", "ERROR", "REPEAT"}, VerdictNeedsReview, 0.5, "", false},
+		{"unanimous agreement", ConsensusUnanimous, []string{"REPEAT", "REPEAT", "REPEAT"}, "REPEAT", 1, "REPEAT from judge-0", false},
+		{"unanimous split", ConsensusUnanimous, []string{"ADVANCE", "ADVANCE", "REPEAT"}, VerdictNeedsReview, 2.0 / 3, "", false},
+		{"all unparseable", ConsensusMajority, []string{"GARBAGE", "GARBAGE"}, "ERROR", 0, "Failed to parse AI response", false},
+		{"all failed", ConsensusMajority, []string{"ERROR", "GARBAGE"}, "", 0, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsensusJudge(nil, len(tt.verdicts), tt.mode)
+			votes, results := ballot(tt.verdicts...)
+
+			got, err := c.tally(votes, results, []string{"Stack"})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("tally() = %+v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tally(): %v", err)
+			}
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("Verdict = %q, want %q", got.Verdict, tt.wantVerdict)
+			}
+			if diff := got.Agreement - tt.wantAgreement; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Agreement = %v, want %v", got.Agreement, tt.wantAgreement)
+			}
+			if tt.wantFeedback != "" && got.Feedback != tt.wantFeedback {
+				t.Errorf("Feedback = %q, want %q", got.Feedback, tt.wantFeedback)
+			}
+			if len(got.Votes) != len(tt.verdicts) {
+				t.Errorf("len(Votes) = %d, want %d", len(got.Votes), len(tt.verdicts))
+			}
+		})
+	}
+}
+
+// The reported patterns come from the first judge that agrees with the verdict
+func TestConsensusTallyPatternsFromAgreeingJudge(t *testing.T) {
+	c := NewConsensusJudge(nil, 3, ConsensusMajority)
+	votes, results := ballot("REPEAT", "ADVANCE", "ADVANCE")
+
+	got, err := c.tally(votes, results, nil)
+	if err != nil {
+		t.Fatalf("tally(): %v", err)
+	}
+	if len(got.PatternsFound) != 1 || got.PatternsFound[0] != "pattern-1" {
+		t.Errorf("PatternsFound = %v, want [pattern-1]", got.PatternsFound)
+	}
+}
+
+func TestConsensusJudgeCheckpoint(t *testing.T) {
+	unavailable := fmt.Errorf("%w: quota exhausted", ErrAIUnavailable)
+
+	tests := []struct {
+		name        string
+		panel       []JudgePanelMember
+		votes       int
+		wantVerdict string
+		wantErr     error
+	}{
+		{
+			"round robin tie",
+			[]JudgePanelMember{{"a", fakeJudge{verdict: "advance"}}, {"b", fakeJudge{verdict: "repeat"}}},
+			4,
+			VerdictNeedsReview,
+			nil,
+		},
+		{
+			"one outage is outvoted",
+			[]JudgePanelMember{{"a", fakeJudge{verdict: "ADVANCE"}}, {"b", fakeJudge{err: unavailable}}},
+			3,
+			"ADVANCE",
+			nil,
+		},
+		{
+			"every judge unavailable",
+			[]JudgePanelMember{{"a", fakeJudge{err: unavailable}}},
+			3,
+			"",
+			ErrAIUnavailable,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewConsensusJudge(tt.panel, tt.votes, ConsensusMajority)
+			got, err := c.JudgeCheckpoint(context.Background(), "uid", "code", 1, nil, "")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("JudgeCheckpoint() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("JudgeCheckpoint(): %v", err)
+			}
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("Verdict = %q, want %q", got.Verdict, tt.wantVerdict)
+			}
+			if len(got.Votes) != tt.votes {
+				t.Errorf("len(Votes) = %d, want %d", len(got.Votes), tt.votes)
+			}
+			if got.PromptVersion != "v1" {
+				t.Errorf("PromptVersion = %q, want v1", got.PromptVersion)
+			}
+		})
+	}
+}
+
+func TestConsensusJudgeEmptyPanel(t *testing.T) {
+	if _, err := NewConsensusJudge(nil, 3, ConsensusMajority).JudgeCheckpoint(context.Background(), "uid", "code", 1, nil, ""); err == nil {
+		t.Fatal("JudgeCheckpoint() with an empty panel succeeded, want an error")
+	}
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/events"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+var (
+	// ErrInvalidTopic means the topic key is not in the problem catalog
+	ErrInvalidTopic = errors.New("invalid topic")
+	// ErrInvalidProblem means the problem ID is not in the topic
+	ErrInvalidProblem = errors.New("invalid problem")
+)
+
+// JudgeService judges practice submissions and credits mastery on ADVANCE
+type JudgeService struct {
+	geminiService  *GeminiService
+	masteryService *MasteryService
+	submissionRepo *repository.SubmissionRepository
+	reviewRepo     *repository.ReviewRepository
+	guard          *SubmissionGuard
+	quizzes        *QuizService
+	// quizTopics are the topics whose passes need an explain quiz for full
+	// credit; "*" enables every topic
+	quizTopics map[string]bool
+	// unverifiedPenalty is the percent of credit withheld until the quiz is passed
+	unverifiedPenalty int
+	curricula         *CurriculumService
+	problems          *ProblemService
+	events            *events.Bus
+}
+
+func NewJudgeService(
+	geminiService *GeminiService,
+	masteryService *MasteryService,
+	submissionRepo *repository.SubmissionRepository,
+	reviewRepo *repository.ReviewRepository,
+	guard *SubmissionGuard,
+) *JudgeService {
+	return &JudgeService{
+		geminiService:  geminiService,
+		masteryService: masteryService,
+		submissionRepo: submissionRepo,
+		reviewRepo:     reviewRepo,
+		guard:          guard,
+	}
+}
+
+// SetQuizService makes passes on the given topics ask the learner to explain
+// their solution; penalty percent of the credit is withheld until they do
+func (s *JudgeService) SetQuizService(quizzes *QuizService, topics []string, penalty int) {
+	s.quizzes = quizzes
+	s.quizTopics = make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		s.quizTopics[topic] = true
+	}
+	s.unverifiedPenalty = penalty
+}
+
+// SetCurriculumService only accepts submissions for problems in the
+// learner's curriculum whose topic is unlocked
+func (s *JudgeService) SetCurriculumService(curricula *CurriculumService) {
+	s.curricula = curricula
+}
+
+// SetProblemService also judges published authored problems, recording
+// the version each submission was judged against
+func (s *JudgeService) SetProblemService(problems *ProblemService) {
+	s.problems = problems
+}
+
+// SetEventBus publishes judged submissions and first solves to bus
+func (s *JudgeService) SetEventBus(bus *events.Bus) {
+	s.events = bus
+}
+
+// quizEnabled reports whether passes on the topic need an explain quiz
+func (s *JudgeService) quizEnabled(topicKey string) bool {
+	return s.quizzes != nil && (s.quizTopics["*"] || s.quizTopics[topicKey])
+}
+
+// Judge audits the code for a problem, stores the submission and updates
+// mastery if the verdict is ADVANCE
+func (s *JudgeService) Judge(ctx context.Context, firebaseUID string, req *models.JudgeRequest) (*models.JudgeResponse, error) {
+	problem, version, err := s.problems.Find(req.TopicKey, req.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+	if s.curricula != nil {
+		if err := s.curricula.CheckAvailable(firebaseUID, req.TopicKey, req.ProblemID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Call Gemini judge
+	result, cached, err := s.geminiService.JudgeAudit(ctx, firebaseUID, req.Code, req.TopicKey, problem.Title, problem.Invariant)
+	if errors.Is(err, ErrAIUnavailable) {
+		// Degraded mode: don't store a verdict the AI never gave
+		return &models.JudgeResponse{
+			Verdict:           VerdictUnavailable,
+			Feedback:          "The judge is temporarily unavailable. Your code was not graded; please resubmit shortly.",
+			Degraded:          true,
+			RetryAfterSeconds: int(math.Ceil(s.geminiService.RetryAfter().Seconds())),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to judge code: %w", err)
+	}
+
+	verdict, _ := result["verdict"].(string)
+	feedback, _ := result["feedback"].(string)
+	promptVersion, _ := result["prompt_version"].(string)
+
+	// Second pass: a pass backed by injected instructions or failing tests
+	// is held for a mentor. The judge's own feedback is kept for them.
+	screening := s.guard.Screen(req.Code, verdict, req.TestResults)
+	storedVerdict := verdict
+	if screening.Held() {
+		storedVerdict = VerdictNeedsReview
+	}
+
+	submission := &models.JudgeSubmission{
+		UserUID:        firebaseUID,
+		TopicKey:       req.TopicKey,
+		ProblemID:      req.ProblemID,
+		ProblemVersion: version,
+		Code:           req.Code,
+		Verdict:        storedVerdict,
+		Feedback:       feedback,
+		TestResults:    req.TestResults,
+		PromptVersion:  promptVersion,
+	}
+	if err := s.submissionRepo.Create(submission); err != nil {
+		return nil, fmt.Errorf("failed to store submission: %w", err)
+	}
+
+	if err := s.guard.Record(firebaseUID, models.ReviewSubjectJudgeSubmission, submission.ID, verdict, screening); err != nil {
+		return nil, err
+	}
+	s.events.Publish(practiceEvent(events.PracticeSubmitted, submission, problem))
+
+	response := &models.JudgeResponse{
+		SubmissionID: submission.ID,
+		Verdict:      storedVerdict,
+		Feedback:     feedback,
+		Cached:       cached,
+	}
+
+	if screening.Held() {
+		response.Feedback = heldFeedback(screening)
+		response.NeedsReview = true
+		review := &models.ReviewRequest{
+			UserUID:     firebaseUID,
+			SubjectType: models.ReviewSubjectJudgeSubmission,
+			SubjectID:   submission.ID,
+			Source:      models.ReviewSourceSecurityFlag,
+			Details:     response.Feedback,
+		}
+		if err := queueReview(s.reviewRepo, review, storedVerdict); err != nil {
+			return nil, err
+		}
+		return response, nil
+	}
+
+	// If verdict is ADVANCE, update mastery
+	if !isAdvance(verdict) {
+		return response, nil
+	}
+	var solved bool
+	if s.quizEnabled(req.TopicKey) {
+		if solved, err = s.recordQuizzedSolve(ctx, firebaseUID, req, problem, submission, response); err != nil {
+			return nil, err
+		}
+	} else if solved, err = s.masteryService.RecordSolve(firebaseUID, req.TopicKey, req.ProblemID); err != nil {
+		return nil, fmt.Errorf("failed to update mastery: %w", err)
+	}
+	if solved {
+		s.events.Publish(practiceEvent(events.PracticeSolved, submission, problem))
+	}
+
+	return response, nil
+}
+
+// practiceEvent describes a practice submission for the event bus
+func practiceEvent(eventType string, submission *models.JudgeSubmission, problem *data.Problem) events.Event {
+	return events.Event{
+		Type:        eventType,
+		UserUID:     submission.UserUID,
+		SubjectType: models.ReviewSubjectJudgeSubmission,
+		SubjectID:   submission.ID,
+		TopicKey:    submission.TopicKey,
+		ProblemID:   submission.ProblemID,
+		Difficulty:  problem.Diff,
+	}
+}
+
+// recordQuizzedSolve credits a first solve with the unverified penalty and
+// asks the learner to explain it. If no quiz can be generated the reduced
+// credit stands. It reports whether the problem was newly solved.
+func (s *JudgeService) recordQuizzedSolve(ctx context.Context, firebaseUID string, req *models.JudgeRequest, problem *data.Problem, submission *models.JudgeSubmission, response *models.JudgeResponse) (bool, error) {
+	solved, err := s.masteryService.RecordUnverifiedSolve(firebaseUID, req.TopicKey, req.ProblemID, s.unverifiedPenalty)
+	if err != nil {
+		return false, fmt.Errorf("failed to update mastery: %w", err)
+	}
+	if !solved {
+		// Already credited by an earlier pass
+		return false, nil
+	}
+
+	quiz, err := s.quizzes.Create(ctx, firebaseUID, models.ReviewSubjectJudgeSubmission, submission.ID,
+		models.QuizReasonPractice, req.Code, problem.Title+"\n\n"+problem.Description, problem.Invariant)
+	if errors.Is(err, ErrAIUnavailable) {
+		log.Printf("Explain quiz unavailable for submission %d: %v", submission.ID, err)
+		return true, nil
+	}
+	if err != nil {
+		return true, err
+	}
+	response.Quiz = quiz
+
+	return true, nil
+}
+
+// GetQuiz returns the explain quiz for one of the learner's submissions
+func (s *JudgeService) GetQuiz(firebaseUID string, submissionID int64) (*models.VerificationQuiz, error) {
+	if s.quizzes == nil {
+		return nil, ErrQuizNotFound
+	}
+	return s.quizzes.Get(firebaseUID, models.ReviewSubjectJudgeSubmission, submissionID)
+}
+
+// SubmitQuiz grades the learner's explanation of a passing submission and
+// grants the withheld confidence credit if they pass. A failed quiz keeps
+// the reduced credit.
+func (s *JudgeService) SubmitQuiz(ctx context.Context, firebaseUID string, submissionID int64, answers []models.QuizAnswer) (*models.QuizResult, error) {
+	quiz, err := s.GetQuiz(firebaseUID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	submission, err := s.submissionRepo.GetByID(submissionID)
+	if err != nil {
+		return nil, err
+	}
+	if submission == nil {
+		return nil, ErrQuizNotFound
+	}
+
+	problemText := ""
+	if problem, err := s.problems.FindVersion(submission.TopicKey, submission.ProblemID, submission.ProblemVersion); err == nil {
+		problemText = problem.Title + "\n\n" + problem.Description
+	}
+
+	result, err := s.quizzes.Grade(ctx, quiz, submission.Code, problemText, answers)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Passed {
+		if err := s.masteryService.VerifySolve(firebaseUID, submission.TopicKey, submission.ProblemID, s.unverifiedPenalty); err != nil {
+			return nil, fmt.Errorf("failed to update mastery: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// findProblem looks up a problem in the catalog
+func findProblem(topicKey, problemID string) (*data.Problem, error) {
+	problems, ok := data.ProblemsDB[topicKey]
+	if !ok {
+		return nil, ErrInvalidTopic
+	}
+
+	for i := range problems {
+		if problems[i].ID == problemID {
+			return &problems[i], nil
+		}
+	}
+
+	return nil, ErrInvalidProblem
+}
+
+// isAdvance reports whether a judge verdict passes the submission
+func isAdvance(verdict string) bool {
+	return verdict == "ADVANCE" || verdict == "Optimal"
+}
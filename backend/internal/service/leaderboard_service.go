@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+// ErrInvalidWeek means the week isn't an ISO week like "2026-W07"
+var ErrInvalidWeek = errors.New("invalid week")
+
+// LeaderboardService reads the leaderboards. Scores are kept up to date by
+// ProgressRepository.Award as XP is earned, so reads never scan the ledger.
+type LeaderboardService struct {
+	leaderboardRepo *repository.LeaderboardRepository
+}
+
+func NewLeaderboardService(leaderboardRepo *repository.LeaderboardRepository) *LeaderboardService {
+	return &LeaderboardService{leaderboardRepo: leaderboardRepo}
+}
+
+// Global returns the all-time XP board
+func (s *LeaderboardService) Global(firebaseUID string, limit, offset int) (*models.Leaderboard, error) {
+	return s.get(models.LeaderboardGlobal, models.LeaderboardAllTime, 0, firebaseUID, limit, offset)
+}
+
+// Weekly returns the XP earned in an ISO week (UTC), the current one if
+// week is empty
+func (s *LeaderboardService) Weekly(firebaseUID, week string, limit, offset int) (*models.Leaderboard, error) {
+	week, err := weekPeriod(week)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(models.LeaderboardWeekly, week, 0, firebaseUID, limit, offset)
+}
+
+// Cohort ranks a cohort's learners by all-time XP, or by the XP of an ISO
+// week if week is set. Callers must check membership.
+func (s *LeaderboardService) Cohort(firebaseUID string, cohortID int64, week string, limit, offset int) (*models.Leaderboard, error) {
+	if week == "" {
+		return s.get(models.LeaderboardGlobal, models.LeaderboardAllTime, cohortID, firebaseUID, limit, offset)
+	}
+	week, err := weekPeriod(week)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(models.LeaderboardWeekly, week, cohortID, firebaseUID, limit, offset)
+}
+
+// Topic returns the all-time board of practice XP earned in one topic
+func (s *LeaderboardService) Topic(firebaseUID, topicKey string, limit, offset int) (*models.Leaderboard, error) {
+	if _, ok := data.ProblemsDB[topicKey]; !ok {
+		return nil, ErrInvalidTopic
+	}
+	return s.get(models.LeaderboardTopicPrefix+topicKey, models.LeaderboardAllTime, 0, firebaseUID, limit, offset)
+}
+
+// weekPeriod validates an ISO week, defaulting to the current one
+func weekPeriod(week string) (string, error) {
+	if week == "" {
+		return repository.WeekPeriod(time.Now()), nil
+	}
+	var year, number int
+	if _, err := fmt.Sscanf(week, "%d-W%d", &year, &number); err != nil || number < 1 || number > 53 ||
+		fmt.Sprintf("%d-W%02d", year, number) != week {
+		return "", ErrInvalidWeek
+	}
+	return week, nil
+}
+
+func (s *LeaderboardService) get(board, period string, cohortID int64, firebaseUID string, limit, offset int) (*models.Leaderboard, error) {
+	entries, uids, total, err := s.leaderboardRepo.List(board, period, cohortID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	for i, uid := range uids {
+		if uid == firebaseUID {
+			entries[i].IsCaller = true
+		}
+	}
+
+	caller, err := s.leaderboardRepo.Rank(board, period, cohortID, firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Leaderboard{
+		Board:   board,
+		Period:  period,
+		Entries: entries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		Caller:  caller,
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 type MasteryService struct {
 	masteryRepo *repository.MasteryRepository
 	userRepo    *repository.UserRepository
+	hints       *HintService
 }
 
 func NewMasteryService(masteryRepo *repository.MasteryRepository, userRepo *repository.UserRepository) *MasteryService {
@@ -19,6 +20,12 @@ func NewMasteryService(masteryRepo *repository.MasteryRepository, userRepo *repo
 	}
 }
 
+// SetHintService makes RecordSolve discount credit for problems solved with
+// hints
+func (s *MasteryService) SetHintService(hints *HintService) {
+	s.hints = hints
+}
+
 // GetMasteryByFirebaseUID retrieves all mastery data for a user by Firebase UID
 func (s *MasteryService) GetMasteryByFirebaseUID(firebaseUID string) (*models.MasteryResponse, error) {
 	// Get user by Firebase UID
@@ -51,8 +58,9 @@ func (s *MasteryService) GetMasteryByFirebaseUID(firebaseUID string) (*models.Ma
 	return response, nil
 }
 
-// UpdateMastery updates mastery for a specific topic
-func (s *MasteryService) UpdateMastery(firebaseUID, topicKey string, req *models.UpdateMasteryRequest) error {
+// updateMastery stores a topic's confidence and solved list. Learners can't
+// set it directly; it only changes through RecordSolve and VerifySolve.
+func (s *MasteryService) updateMastery(firebaseUID, topicKey string, req *models.UpdateMasteryRequest) error {
 	// Get user by Firebase UID
 	user, err := s.userRepo.GetByFirebaseUID(firebaseUID)
 	if err != nil {
@@ -82,3 +90,136 @@ func (s *MasteryService) UpdateMastery(firebaseUID, topicKey string, req *models
 
 	return nil
 }
+
+// RecordSolve adds a problem to the topic's solved list and recalculates
+// confidence (33% per problem, max 100%). Solving a problem twice is a no-op;
+// it reports whether the problem was newly solved. If hints were used, the
+// problem's share of confidence is reduced by the hint credit penalty.
+func (s *MasteryService) RecordSolve(firebaseUID, topicKey, problemID string) (bool, error) {
+	return s.recordSolve(firebaseUID, topicKey, problemID, 0)
+}
+
+// RecordUnverifiedSolve is RecordSolve for a pass the learner hasn't yet
+// explained in a verification quiz: the problem's share of confidence is
+// further reduced by penalty percent until VerifySolve grants the rest. It
+// reports whether the problem was newly solved.
+func (s *MasteryService) RecordUnverifiedSolve(firebaseUID, topicKey, problemID string, penalty int) (bool, error) {
+	return s.recordSolve(firebaseUID, topicKey, problemID, penalty)
+}
+
+// VerifySolve grants the confidence withheld by RecordUnverifiedSolve with
+// the same penalty, once the learner has passed the verification quiz
+func (s *MasteryService) VerifySolve(firebaseUID, topicKey, problemID string, penalty int) error {
+	masteryResp, err := s.GetMasteryByFirebaseUID(firebaseUID)
+	if err != nil {
+		return fmt.Errorf("failed to get mastery: %w", err)
+	}
+
+	currentMastery, ok := masteryResp.Mastery[topicKey]
+	if !ok {
+		return nil
+	}
+	position := -1
+	for i, id := range currentMastery.Solved {
+		if id == problemID {
+			position = i
+		}
+	}
+	if position < 0 {
+		return nil
+	}
+
+	full, err := s.solveCredit(firebaseUID, topicKey, problemID, position+1, 0)
+	if err != nil {
+		return err
+	}
+	granted, err := s.solveCredit(firebaseUID, topicKey, problemID, position+1, penalty)
+	if err != nil {
+		return err
+	}
+	if full == granted {
+		return nil
+	}
+
+	updateReq := &models.UpdateMasteryRequest{
+		Confidence:     addCredit(currentMastery.Confidence, full-granted),
+		SolvedProblems: currentMastery.Solved,
+	}
+
+	return s.updateMastery(firebaseUID, topicKey, updateReq)
+}
+
+// recordSolve adds the problem to the solved list with its confidence step
+// reduced by the hint penalty and then by unverifiedPenalty percent
+func (s *MasteryService) recordSolve(firebaseUID, topicKey, problemID string, unverifiedPenalty int) (bool, error) {
+	// Get current mastery
+	masteryResp, err := s.GetMasteryByFirebaseUID(firebaseUID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get mastery: %w", err)
+	}
+
+	currentMastery, ok := masteryResp.Mastery[topicKey]
+	if !ok {
+		currentMastery = models.MasteryData{Confidence: 0, Solved: []string{}}
+	}
+
+	// Add problem to solved list if not already there
+	solved := currentMastery.Solved
+	for _, id := range solved {
+		if id == problemID {
+			return false, nil
+		}
+	}
+	solved = append(solved, problemID)
+
+	// Add this problem's step (33% per problem, max 100%) to the current
+	// confidence so reductions on earlier solves are kept
+	step, err := s.solveCredit(firebaseUID, topicKey, problemID, len(solved), unverifiedPenalty)
+	if err != nil {
+		return false, err
+	}
+
+	// Update mastery
+	updateReq := &models.UpdateMasteryRequest{
+		Confidence:     addCredit(currentMastery.Confidence, step),
+		SolvedProblems: solved,
+	}
+
+	if err := s.updateMastery(firebaseUID, topicKey, updateReq); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// solveCredit is the confidence earned by the problem solved in the given
+// position (1-based), after the hint penalty and an extra penalty percent
+func (s *MasteryService) solveCredit(firebaseUID, topicKey, problemID string, position, penalty int) (int, error) {
+	step := solveConfidence(position) - solveConfidence(position-1)
+
+	if s.hints != nil {
+		hintPenalty, err := s.hints.CreditPenalty(firebaseUID, topicKey, problemID)
+		if err != nil {
+			return 0, err
+		}
+		step = step * (100 - hintPenalty) / 100
+	}
+
+	return step * (100 - penalty) / 100, nil
+}
+
+// addCredit adds credit to a topic's confidence, capped at 100. Solves and
+// verifications only ever add to the stored confidence so the reduced credit
+// of one solve isn't undone by the next.
+func addCredit(confidence, credit int) int {
+	return min(confidence+credit, 100)
+}
+
+// solveConfidence is the full confidence credit for a number of solved
+// problems
+func solveConfidence(solved int) int {
+	confidence := (solved * 100) / 3
+	if confidence > 100 {
+		confidence = 100
+	}
+	return confidence
+}
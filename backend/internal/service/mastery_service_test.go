@@ -0,0 +1,40 @@
+package service
+
+import "testing"
+
+// An unverified solve, then a clean solve, then verifying the first must end
+// at the same confidence as two clean solves
+func TestSolveCreditUnverifiedCleanVerify(t *testing.T) {
+	s := &MasteryService{}
+	const penalty = 50
+
+	credit := func(position, penalty int) int {
+		t.Helper()
+		step, err := s.solveCredit("uid", "TWO_POINTERS", "p", position, penalty)
+		if err != nil {
+			t.Fatalf("solveCredit(%d, %d): %v", position, penalty, err)
+		}
+		return step
+	}
+
+	confidence := addCredit(0, credit(1, penalty))
+	if confidence != 16 {
+		t.Fatalf("after unverified solve: confidence = %d, want 16", confidence)
+	}
+
+	confidence = addCredit(confidence, credit(2, 0))
+	if confidence != 49 {
+		t.Fatalf("after clean solve: confidence = %d, want 49", confidence)
+	}
+
+	confidence = addCredit(confidence, credit(1, 0)-credit(1, penalty))
+	if want := solveConfidence(2); confidence != want {
+		t.Fatalf("after verify: confidence = %d, want %d", confidence, want)
+	}
+}
+
+func TestAddCreditCapsAt100(t *testing.T) {
+	if got := addCredit(90, 34); got != 100 {
+		t.Fatalf("addCredit(90, 34) = %d, want 100", got)
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/sandbox"
+)
+
+// ErrInvalidPattern means the requested pattern is too long
+var ErrInvalidPattern = errors.New("pattern must be at most 100 characters")
+
+const (
+	// maxGenerationAttempts bounds how often a draft that fails validation
+	// is sent back to the model with the failures
+	maxGenerationAttempts = 2
+	generatedTestCount    = 12
+	maxPatternLength      = 100
+	// maxFeedbackFailures is how many failing tests are described to the model
+	maxFeedbackFailures = 5
+)
+
+// SetGeminiService lets admins draft problems with the LLM
+func (s *ProblemService) SetGeminiService(geminiService *GeminiService) {
+	s.gemini = geminiService
+}
+
+// Generate asks the LLM to draft a problem for a topic and pattern, runs
+// the draft's reference solution against its tests in the sandbox and
+// stores it unpublished for an admin to approve. A draft that fails is sent
+// back to the model with the failures. With DryRun the validated draft is
+// returned without being stored.
+func (s *ProblemService) Generate(ctx context.Context, firebaseUID string, req *models.GenerateProblemRequest) (*models.AuthoredProblem, error) {
+	brief, err := s.brief(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var version *models.ProblemVersion
+	for attempt := 1; ; attempt++ {
+		draft, promptVersion, err := s.gemini.GenerateProblem(ctx, firebaseUID, brief)
+		if err != nil {
+			return nil, err
+		}
+		// The harness is chosen by the request, whatever the model wrote
+		draft.Solution.Language = brief.Language
+		if draft.Solution.Entry == "" {
+			draft.Solution.Entry = brief.Entry
+		}
+
+		version, err = s.validate(ctx, draft)
+		if err == nil {
+			version.PromptVersion = promptVersion
+			break
+		}
+		feedback, retry := generationFeedback(err, draft.Tests)
+		if !retry || attempt == maxGenerationAttempts {
+			return nil, err
+		}
+		brief.Feedback = feedback
+	}
+
+	if req.DryRun {
+		version.TopicKey, version.Source = req.TopicKey, models.ProblemSourceGenerated
+		return &models.AuthoredProblem{
+			TopicKey: req.TopicKey,
+			Source:   models.ProblemSourceGenerated,
+			Versions: []models.ProblemVersion{*version},
+		}, nil
+	}
+	return s.create(req.TopicKey, "", models.ProblemSourceGenerated, version)
+}
+
+// ThinTopics returns the topics with no built-in or published authored
+// problems, in skill tree order
+func (s *ProblemService) ThinTopics() ([]string, error) {
+	var thin []string
+	for _, topicKey := range config.AllTopics {
+		problems, err := s.List(topicKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(problems) == 0 {
+			thin = append(thin, topicKey)
+		}
+	}
+	return thin, nil
+}
+
+// brief checks a generation request and fills in its defaults and the
+// problems the draft must not repeat
+func (s *ProblemService) brief(req *models.GenerateProblemRequest) (ProblemBrief, error) {
+	node, ok := data.DAGStructure[req.TopicKey]
+	if !slices.Contains(config.AllTopics, req.TopicKey) || !ok {
+		return ProblemBrief{}, ErrInvalidTopic
+	}
+
+	req.Pattern = strings.TrimSpace(req.Pattern)
+	if req.Pattern == "" {
+		req.Pattern = node.Label
+	}
+	if len(req.Pattern) > maxPatternLength {
+		return ProblemBrief{}, ErrInvalidPattern
+	}
+	if req.Difficulty == "" {
+		req.Difficulty = "Medium"
+	}
+	if !slices.Contains(problemDifficulties, req.Difficulty) {
+		return ProblemBrief{}, fmt.Errorf("%w: difficulty must be Easy, Medium or Hard", ErrInvalidProblemDraft)
+	}
+	if req.Language == "" {
+		req.Language = sandbox.Python
+	}
+	if req.Language != sandbox.Python && req.Language != sandbox.JavaScript {
+		return ProblemBrief{}, sandbox.ErrUnsupportedLanguage
+	}
+
+	var existing []string
+	catalog, err := s.List(req.TopicKey)
+	if err != nil {
+		return ProblemBrief{}, err
+	}
+	for _, problem := range catalog {
+		existing = append(existing, problem.Title)
+	}
+	pending, err := s.problemRepo.ListByStatus(models.ProblemSourceGenerated, models.ProblemVersionPending, 100, 0)
+	if err != nil {
+		return ProblemBrief{}, err
+	}
+	for _, v := range pending {
+		if v.TopicKey == req.TopicKey {
+			existing = append(existing, v.Title)
+		}
+	}
+
+	return ProblemBrief{
+		Topic:       req.TopicKey,
+		TopicLabel:  node.Label,
+		TopicTheory: node.Theory,
+		Pattern:     req.Pattern,
+		Difficulty:  req.Difficulty,
+		Language:    req.Language,
+		Entry:       "solution",
+		Tests:       generatedTestCount,
+		Existing:    existing,
+	}, nil
+}
+
+// generationFeedback describes a validation failure for the model and
+// reports whether another attempt could fix it
+func generationFeedback(err error, tests []models.ProblemTestCase) (string, bool) {
+	var validationErr *ProblemValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		if validationErr.Message != "" {
+			return "The solution failed to run: " + validationErr.Message, true
+		}
+		var lines []string
+		for i, result := range validationErr.Validation.Results {
+			if result.Passed {
+				continue
+			}
+			if len(lines) == maxFeedbackFailures {
+				break
+			}
+			if result.Error != "" {
+				lines = append(lines, fmt.Sprintf("- test %d, args %s: raised %s", i+1, tests[i].Args, result.Error))
+			} else {
+				lines = append(lines, fmt.Sprintf("- test %d, args %s: expected %s, solution returned %s", i+1, tests[i].Args, tests[i].Expected, result.Output))
+			}
+		}
+		if validationErr.Validation.TimedOut {
+			lines = append(lines, "- the run timed out; keep tests small")
+		}
+		return strings.Join(lines, "\n"), true
+	case errors.Is(err, ErrInvalidProblemDraft), errors.Is(err, sandbox.ErrInvalidTestCase), errors.Is(err, sandbox.ErrInvalidEntry):
+		return err.Error(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/yourusername/skilltree/internal/config"
+	"github.com/yourusername/skilltree/internal/curriculum"
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+	"github.com/yourusername/skilltree/internal/sandbox"
+)
+
+var (
+	// ErrProblemNotFound means the authored problem or version doesn't exist
+	// or isn't visible to the caller
+	ErrProblemNotFound = errors.New("problem not found")
+	// ErrInvalidProblemDraft means a field of a submitted problem is missing
+	// or out of bounds; the wrapped message says which
+	ErrInvalidProblemDraft = errors.New("invalid problem")
+	// ErrRevisionPending means the problem already has a version awaiting
+	// moderation
+	ErrRevisionPending = errors.New("a revision is already awaiting moderation")
+	// ErrSelfModeration means a moderator tried to review their own problem
+	ErrSelfModeration = errors.New("you cannot moderate your own problem")
+	// ErrInvalidDecision means the moderation decision is unknown
+	ErrInvalidDecision = errors.New("decision must be approve or reject")
+	// ErrProblemAlreadyReviewed means the version is no longer pending
+	ErrProblemAlreadyReviewed = repository.ErrProblemAlreadyReviewed
+)
+
+// Limits on authored problems
+const (
+	maxProblemTitle     = 200
+	maxProblemStatement = 10000
+	maxProblemInvariant = 500
+	maxProblemExamples  = 10
+	maxProblemCode      = 20000
+	maxProblemIDLength  = 48
+)
+
+var problemDifficulties = []string{"Easy", "Medium", "Hard"}
+
+// What the author sees when the reference solution fails. The program's
+// own output only goes to the log so it can't carry anything the sandbox
+// can read back out.
+const (
+	solutionFailedMessage = "it exited without running its tests; check that it parses and defines the entry function"
+	testErrorMessage      = "raised an error"
+)
+
+// ProblemValidationError means the reference solution failed to run or
+// didn't pass its own tests
+type ProblemValidationError struct {
+	// Message is set when the program itself failed (syntax error, missing
+	// entry function); otherwise Validation holds the failing results
+	Message    string
+	Validation models.ProblemValidation
+}
+
+func (e *ProblemValidationError) Error() string {
+	if e.Message != "" {
+		return "reference solution failed: " + e.Message
+	}
+	return "reference solution does not pass its tests"
+}
+
+// ProblemService handles user-authored problems: submission with a
+// sandboxed check of the reference solution, moderation and lookups that
+// merge published versions into the built-in catalog
+type ProblemService struct {
+	problemRepo *repository.ProblemRepository
+	runner      *sandbox.Runner
+	gemini      *GeminiService
+}
+
+// NewProblemService creates the service; runner may be nil, in which case
+// new problems and revisions are refused because they can't be validated
+func NewProblemService(problemRepo *repository.ProblemRepository, runner *sandbox.Runner) *ProblemService {
+	return &ProblemService{
+		problemRepo: problemRepo,
+		runner:      runner,
+	}
+}
+
+// Submit validates a new problem and queues its first version for
+// moderation
+func (s *ProblemService) Submit(ctx context.Context, authorUID string, req *models.SubmitProblemRequest) (*models.AuthoredProblem, error) {
+	if !slices.Contains(config.AllTopics, req.TopicKey) {
+		return nil, ErrInvalidTopic
+	}
+	version, err := s.validate(ctx, &req.ProblemDraft)
+	if err != nil {
+		return nil, err
+	}
+	return s.create(req.TopicKey, authorUID, models.ProblemSourceUser, version)
+}
+
+// create stores a validated first version as a new problem in the topic
+func (s *ProblemService) create(topicKey, authorUID, source string, version *models.ProblemVersion) (*models.AuthoredProblem, error) {
+	problemID, err := s.newProblemID(topicKey, version.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	problem := &models.AuthoredProblem{
+		TopicKey:  topicKey,
+		ProblemID: problemID,
+		AuthorUID: authorUID,
+		Source:    source,
+	}
+	if err := s.problemRepo.Create(problem, version); err != nil {
+		return nil, err
+	}
+	version.TopicKey, version.ProblemID, version.AuthorUID, version.Source = topicKey, problemID, authorUID, source
+	problem.Versions = []models.ProblemVersion{*version}
+
+	return problem, nil
+}
+
+// Revise validates a new version of one of the author's problems and
+// queues it for moderation. The published version stays live until the
+// revision is approved.
+func (s *ProblemService) Revise(ctx context.Context, authorUID string, id int64, draft *models.ProblemDraft) (*models.ProblemVersion, error) {
+	problem, err := s.Get(authorUID, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range problem.Versions {
+		if v.Status == models.ProblemVersionPending {
+			return nil, ErrRevisionPending
+		}
+	}
+
+	version, err := s.validate(ctx, draft)
+	if err != nil {
+		return nil, err
+	}
+	version.AuthoredProblemID = problem.ID
+	if err := s.problemRepo.AddVersion(version); err != nil {
+		return nil, err
+	}
+	version.TopicKey, version.ProblemID, version.AuthorUID = problem.TopicKey, problem.ProblemID, problem.AuthorUID
+	version.Source = problem.Source
+
+	return version, nil
+}
+
+// Mine returns the author's problems with all their versions
+func (s *ProblemService) Mine(authorUID string) ([]models.AuthoredProblem, error) {
+	ids, err := s.problemRepo.ListIDsByAuthor(authorUID)
+	if err != nil {
+		return nil, err
+	}
+
+	problems := make([]models.AuthoredProblem, 0, len(ids))
+	for _, id := range ids {
+		problem, err := s.problemRepo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if problem != nil {
+			problems = append(problems, *problem)
+		}
+	}
+	return problems, nil
+}
+
+// Get returns one of the author's problems with all its versions
+func (s *ProblemService) Get(authorUID string, id int64) (*models.AuthoredProblem, error) {
+	problem, err := s.problemRepo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if problem == nil || problem.AuthorUID != authorUID {
+		return nil, ErrProblemNotFound
+	}
+	return problem, nil
+}
+
+// Queue lists versions of problems from a source in a moderation status,
+// oldest first
+func (s *ProblemService) Queue(source, status string, limit, offset int) ([]models.ProblemVersion, error) {
+	return s.problemRepo.ListByStatus(source, status, limit, offset)
+}
+
+// GetVersion returns a version of a problem from source for its moderators
+func (s *ProblemService) GetVersion(versionID int64, source string) (*models.ProblemVersion, error) {
+	version, err := s.problemRepo.GetVersion(versionID)
+	if err != nil {
+		return nil, err
+	}
+	if version == nil || version.Source != source {
+		return nil, ErrProblemNotFound
+	}
+	return version, nil
+}
+
+// Moderate approves or rejects a pending version of a problem from source.
+// Approval publishes it: new submissions are judged against it while older
+// ones keep pointing at the version they solved.
+func (s *ProblemService) Moderate(moderatorUID string, versionID int64, source string, req *models.ModerateProblemRequest) (*models.ProblemVersion, error) {
+	var status string
+	switch strings.ToLower(strings.TrimSpace(req.Decision)) {
+	case models.ProblemDecisionApprove:
+		status = models.ProblemVersionApproved
+	case models.ProblemDecisionReject:
+		status = models.ProblemVersionRejected
+	default:
+		return nil, ErrInvalidDecision
+	}
+
+	version, err := s.GetVersion(versionID, source)
+	if err != nil {
+		return nil, err
+	}
+	if version.AuthorUID == moderatorUID {
+		return nil, ErrSelfModeration
+	}
+	if version.Status != models.ProblemVersionPending {
+		return nil, ErrProblemAlreadyReviewed
+	}
+
+	if err := s.problemRepo.Review(version, status, moderatorUID, strings.TrimSpace(req.Note)); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// Find looks a problem up in the built-in catalog, then among published
+// authored problems. version is the published version of an authored
+// problem and nil for built-in ones. A nil service only knows the built-in
+// catalog.
+func (s *ProblemService) Find(topicKey, problemID string) (problem *data.Problem, version *int, err error) {
+	problem, err = findProblem(topicKey, problemID)
+	if err == nil || s == nil || !slices.Contains(config.AllTopics, topicKey) {
+		return problem, nil, err
+	}
+
+	published, lookupErr := s.problemRepo.GetPublished(topicKey, problemID)
+	if lookupErr != nil {
+		return nil, nil, lookupErr
+	}
+	if published == nil {
+		return nil, nil, ErrInvalidProblem
+	}
+	return catalogProblem(published), &published.Version, nil
+}
+
+// FindVersion looks a problem up as a submission saw it: the given version
+// of an authored problem, or the built-in problem when version is nil
+func (s *ProblemService) FindVersion(topicKey, problemID string, version *int) (*data.Problem, error) {
+	if version == nil || s == nil {
+		problem, _, err := s.Find(topicKey, problemID)
+		return problem, err
+	}
+
+	v, err := s.problemRepo.GetByNumber(topicKey, problemID, *version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil {
+		return nil, ErrInvalidProblem
+	}
+	return catalogProblem(v), nil
+}
+
+// List returns a topic's problems: the built-in ones, then the published
+// authored ones
+func (s *ProblemService) List(topicKey string) ([]models.CatalogProblem, error) {
+	if !slices.Contains(config.AllTopics, topicKey) {
+		return nil, ErrInvalidTopic
+	}
+
+	problems := []models.CatalogProblem{}
+	for _, problem := range data.ProblemsDB[topicKey] {
+		problems = append(problems, models.CatalogProblem{Problem: problem})
+	}
+
+	published, err := s.problemRepo.ListPublished(topicKey)
+	if err != nil {
+		return nil, err
+	}
+	for i := range published {
+		problems = append(problems, models.CatalogProblem{
+			Problem:  *catalogProblem(&published[i]),
+			Authored: true,
+			Version:  published[i].Version,
+		})
+	}
+
+	return problems, nil
+}
+
+// validate checks a draft's fields, then runs the reference solution
+// against its tests in the sandbox. It returns the version to store.
+func (s *ProblemService) validate(ctx context.Context, draft *models.ProblemDraft) (*models.ProblemVersion, error) {
+	if s.runner == nil {
+		return nil, ErrSandboxDisabled
+	}
+
+	draft.Title = strings.TrimSpace(draft.Title)
+	draft.Statement = strings.TrimSpace(draft.Statement)
+	draft.Invariant = strings.TrimSpace(draft.Invariant)
+	switch {
+	case len(draft.Title) < 3 || len(draft.Title) > maxProblemTitle:
+		return nil, fmt.Errorf("%w: title must be 3 to %d characters", ErrInvalidProblemDraft, maxProblemTitle)
+	case !slices.Contains(problemDifficulties, draft.Difficulty):
+		return nil, fmt.Errorf("%w: difficulty must be Easy, Medium or Hard", ErrInvalidProblemDraft)
+	case draft.Statement == "" || len(draft.Statement) > maxProblemStatement:
+		return nil, fmt.Errorf("%w: statement must be 1 to %d characters", ErrInvalidProblemDraft, maxProblemStatement)
+	case draft.Invariant == "" || len(draft.Invariant) > maxProblemInvariant:
+		return nil, fmt.Errorf("%w: invariant must be 1 to %d characters", ErrInvalidProblemDraft, maxProblemInvariant)
+	case len(draft.Examples) > maxProblemExamples:
+		return nil, fmt.Errorf("%w: at most %d examples", ErrInvalidProblemDraft, maxProblemExamples)
+	case draft.Solution.Code == "" || len(draft.Solution.Code) > maxProblemCode:
+		return nil, fmt.Errorf("%w: solution code must be 1 to %d bytes", ErrInvalidProblemDraft, maxProblemCode)
+	}
+	for _, example := range draft.Examples {
+		if strings.TrimSpace(example.Input) == "" || strings.TrimSpace(example.Output) == "" {
+			return nil, fmt.Errorf("%w: examples need an input and an output", ErrInvalidProblemDraft)
+		}
+	}
+	if draft.Examples == nil {
+		draft.Examples = []data.ProblemExample{}
+	}
+	if draft.Constraints == nil {
+		draft.Constraints = []string{}
+	}
+
+	if draft.Solution.Entry == "" {
+		draft.Solution.Entry = "solution"
+	}
+	tests := make([]sandbox.TestCase, len(draft.Tests))
+	for i, test := range draft.Tests {
+		tests[i] = sandbox.TestCase(test)
+	}
+	program := sandbox.Program{
+		Language: draft.Solution.Language,
+		Code:     draft.Solution.Code,
+		Entry:    draft.Solution.Entry,
+	}
+	report, err := s.runner.RunTests(ctx, program, tests)
+	var programErr *sandbox.ProgramError
+	if errors.As(err, &programErr) {
+		log.Printf("Reference solution %q failed to run: %s", draft.Title, programErr.Message)
+		return nil, &ProblemValidationError{Message: solutionFailedMessage}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	validation := models.ProblemValidation{
+		Passed:   report.Passed(),
+		TimedOut: report.TimedOut,
+		Results:  make([]models.ProblemTestResult, len(report.Results)),
+	}
+	for i, result := range report.Results {
+		validation.Results[i] = models.ProblemTestResult(result)
+		if result.Error != "" {
+			log.Printf("Reference solution %q raised on test %d: %s", draft.Title, i, result.Error)
+			validation.Results[i].Error = testErrorMessage
+		}
+	}
+	if !validation.Passed {
+		return nil, &ProblemValidationError{Validation: validation}
+	}
+
+	return &models.ProblemVersion{ProblemDraft: *draft, Validation: validation}, nil
+}
+
+// newProblemID derives a catalog ID from the title that no built-in or
+// authored problem in the topic uses yet
+func (s *ProblemService) newProblemID(topicKey, title string) (string, error) {
+	base := slugify(title)
+	if base == "" {
+		base = "problem"
+	}
+	for n := 1; ; n++ {
+		id := base
+		if n > 1 {
+			id = fmt.Sprintf("%s_%d", base, n)
+		}
+		if _, err := findProblem(topicKey, id); err == nil {
+			continue
+		}
+		taken, err := s.problemRepo.ProblemIDTaken(topicKey, id)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return id, nil
+		}
+	}
+}
+
+// slugify lowercases s and joins its words with underscores, like the IDs
+// of built-in problems
+func slugify(s string) string {
+	var b strings.Builder
+	gap := false
+	for _, r := range strings.ToLower(s) {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			gap = b.Len() > 0
+			continue
+		}
+		if b.Len() >= maxProblemIDLength {
+			break
+		}
+		if gap {
+			b.WriteByte('_')
+			gap = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// catalogProblem presents a published version like a built-in problem
+func catalogProblem(v *models.ProblemVersion) *data.Problem {
+	return &data.Problem{
+		ID:          v.ProblemID,
+		Title:       v.Title,
+		Diff:        v.Difficulty,
+		Invariant:   v.Invariant,
+		Description: v.Statement,
+		Examples:    v.Examples,
+		Constraints: v.Constraints,
+	}
+}
+
+// problemInCurriculum reports whether c offers a problem. Curated problem
+// lists only cover the built-in catalog; published authored problems are
+// offered wherever their topic is.
+func problemInCurriculum(c *curriculum.Curriculum, topicKey, problemID string) bool {
+	if _, err := findProblem(topicKey, problemID); err != nil {
+		return c.Topic(topicKey) != nil
+	}
+	return c.HasProblem(topicKey, problemID)
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/curriculum"
+	"github.com/yourusername/skilltree/internal/events"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/progression"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+// ErrInvalidTimezone means the timezone isn't a known IANA name
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// XP per first solve of a practice problem, by difficulty
+var practiceXP = map[string]int{
+	"Easy":   10,
+	"Medium": 25,
+	"Hard":   50,
+}
+
+const (
+	// checkpointXPBase and checkpointXPPerTier price a checkpoint pass
+	checkpointXPBase    = 100
+	checkpointXPPerTier = 50
+	// reviewXP is earned by the mentor for each resolved review
+	reviewXP = 15
+	// recentXPLimit is how many ledger entries the profile shows
+	recentXPLimit = 20
+)
+
+// ProgressService turns domain events into XP awards and daily streaks
+type ProgressService struct {
+	progressRepo *repository.ProgressRepository
+	settingsRepo *repository.SettingsRepository
+	streakRules  progression.StreakRules
+}
+
+func NewProgressService(progressRepo *repository.ProgressRepository, settingsRepo *repository.SettingsRepository, streakRules progression.StreakRules) *ProgressService {
+	return &ProgressService{
+		progressRepo: progressRepo,
+		settingsRepo: settingsRepo,
+		streakRules:  streakRules,
+	}
+}
+
+// Handle awards XP and records streak activity for an event. Awards are
+// keyed on the event's subject, so replaying an event awards nothing.
+func (s *ProgressService) Handle(event events.Event) error {
+	switch event.Type {
+	case events.PracticeSubmitted, events.CheckpointAttempted:
+		return s.recordActivity(event.UserUID, event.At)
+	case events.PracticeSolved:
+		amount, ok := practiceXP[event.Difficulty]
+		if !ok {
+			amount = practiceXP["Easy"]
+		}
+		return s.award(event, models.XPReasonPracticeSolve, amount)
+	case events.CheckpointPassed:
+		return s.award(event, models.XPReasonCheckpointPass, checkpointXPBase+checkpointXPPerTier*event.Tier)
+	case events.ReviewResolved:
+		return s.award(event, models.XPReasonReview, reviewXP)
+	}
+	return nil
+}
+
+func (s *ProgressService) award(event events.Event, reason string, amount int) error {
+	_, err := s.progressRepo.Award(&models.XPAward{
+		UserUID:    event.UserUID,
+		SourceType: event.SubjectType,
+		SourceID:   event.SubjectID,
+		Reason:     reason,
+		TopicKey:   event.TopicKey,
+		Amount:     amount,
+	})
+	return err
+}
+
+// recordActivity counts at as activity on the calendar day it falls on in
+// the user's timezone
+func (s *ProgressService) recordActivity(firebaseUID string, at time.Time) error {
+	location, err := s.location(firebaseUID)
+	if err != nil {
+		return err
+	}
+	return s.progressRepo.RecordActivity(firebaseUID, at.In(location), s.streakRules)
+}
+
+// Stats returns the user's XP, level and streak. A streak whose gap the
+// freeze tokens can't cover reads as 0 even before the next activity
+// resets it.
+func (s *ProgressService) Stats(firebaseUID string) (*models.ProfileStats, error) {
+	settings, err := s.settingsRepo.Get(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	progress, err := s.progressRepo.Get(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	recent, err := s.progressRepo.ListAwards(firebaseUID, recentXPLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	today := time.Now().In(location)
+	streak := progression.Streak{
+		Current:       progress.CurrentStreak,
+		Longest:       progress.LongestStreak,
+		LastActiveDay: progress.LastActiveDay,
+		FreezeTokens:  progress.FreezeTokens,
+	}
+	current := streak.Current
+	if !streak.Alive(today) {
+		current = 0
+	}
+
+	level := progression.LevelFor(progress.TotalXP)
+	return &models.ProfileStats{
+		TotalXP:        progress.TotalXP,
+		Level:          level.Level,
+		XPIntoLevel:    level.XPIntoLevel,
+		XPForNextLevel: level.XPForNextLevel,
+		Streak: models.StreakStats{
+			Current:       current,
+			Longest:       progress.LongestStreak,
+			LastActiveDay: progress.LastActiveDay,
+			ActiveToday:   progress.LastActiveDay == today.Format(progression.DayLayout),
+			FreezeTokens:  progress.FreezeTokens,
+			FreezesUsed:   progress.FreezesUsed,
+		},
+		Timezone: settings.Timezone,
+		RecentXP: recent,
+	}, nil
+}
+
+// GetSettings returns the user's settings
+func (s *ProgressService) GetSettings(firebaseUID string) (*models.UserSettings, error) {
+	return s.settingsRepo.Get(firebaseUID)
+}
+
+// UpdateSettings changes the fields set in req and returns the result
+func (s *ProgressService) UpdateSettings(firebaseUID string, req *models.UpdateSettingsRequest) (*models.UserSettings, error) {
+	settings, err := s.settingsRepo.Get(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Timezone != nil {
+		timezone := strings.TrimSpace(*req.Timezone)
+		// LoadLocation treats "" and "Local" as the server's zone
+		if timezone == "" || timezone == "Local" {
+			return nil, ErrInvalidTimezone
+		}
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return nil, ErrInvalidTimezone
+		}
+		settings.Timezone = timezone
+	}
+	if req.LeaderboardOptOut != nil {
+		settings.LeaderboardOptOut = *req.LeaderboardOptOut
+	}
+	if req.Curriculum != nil {
+		key := strings.TrimSpace(*req.Curriculum)
+		if key != "" && curriculum.Find(key) == nil {
+			return nil, ErrUnknownCurriculum
+		}
+		settings.Curriculum = key
+	}
+
+	if err := s.settingsRepo.Save(firebaseUID, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// location returns the user's timezone, falling back to UTC if the stored
+// name no longer loads
+func (s *ProgressService) location(firebaseUID string) (*time.Location, error) {
+	settings, err := s.settingsRepo.Get(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	location, err := time.LoadLocation(settings.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return location, nil
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+var (
+	// ErrQuizNotFound means there is no quiz for the submission and user
+	ErrQuizNotFound = errors.New("quiz not found")
+	// ErrQuizNotPending means the quiz was already answered or has expired
+	ErrQuizNotPending = repository.ErrQuizNotPending
+	// ErrQuizIncomplete means an answer is missing, empty or too long
+	ErrQuizIncomplete = errors.New("every question needs an answer")
+)
+
+// maxQuizAnswerLength bounds each free-text answer sent to the grader
+const maxQuizAnswerLength = 2000
+
+// QuizService asks learners to explain code they submitted and grades the
+// explanation, so a pass backed by code they can't explain doesn't count
+type QuizService struct {
+	gemini   *GeminiService
+	quizRepo *repository.QuizRepository
+	// questions is how many questions each quiz asks
+	questions int
+	// passPercent is the mean answer score needed to pass
+	passPercent int
+	// ttl is how long the learner has to answer
+	ttl time.Duration
+}
+
+func NewQuizService(gemini *GeminiService, quizRepo *repository.QuizRepository, questions, passPercent int, ttl time.Duration) *QuizService {
+	return &QuizService{
+		gemini:      gemini,
+		quizRepo:    quizRepo,
+		questions:   questions,
+		passPercent: passPercent,
+		ttl:         ttl,
+	}
+}
+
+// Create generates and stores a quiz about the code submitted for a subject
+func (s *QuizService) Create(ctx context.Context, firebaseUID, subjectType string, subjectID int64, reason, code, problem, invariant string) (*models.VerificationQuiz, error) {
+	questions, promptVersion, err := s.gemini.GenerateExplainQuiz(ctx, firebaseUID, code, problem, invariant, s.questions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate quiz: %w", err)
+	}
+
+	quiz := &models.VerificationQuiz{
+		UserUID:       firebaseUID,
+		SubjectType:   subjectType,
+		SubjectID:     subjectID,
+		Reason:        reason,
+		Questions:     questions,
+		PromptVersion: promptVersion,
+		ExpiresAt:     time.Now().Add(s.ttl),
+	}
+	if err := s.quizRepo.Create(quiz); err != nil {
+		return nil, err
+	}
+
+	return quiz, nil
+}
+
+// Get returns the learner's quiz for a subject
+func (s *QuizService) Get(firebaseUID, subjectType string, subjectID int64) (*models.VerificationQuiz, error) {
+	quiz, err := s.quizRepo.GetBySubject(subjectType, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	if quiz == nil || quiz.UserUID != firebaseUID {
+		return nil, ErrQuizNotFound
+	}
+	return quiz, nil
+}
+
+// Grade scores the answers to a pending quiz and records the outcome. A
+// quiz answered after it expired fails without being graded.
+func (s *QuizService) Grade(ctx context.Context, quiz *models.VerificationQuiz, code, problem string, answers []models.QuizAnswer) (*models.QuizResult, error) {
+	if quiz.Status != models.QuizPending {
+		return nil, ErrQuizNotPending
+	}
+
+	if time.Now().After(quiz.ExpiresAt) {
+		quiz.Status = models.QuizExpired
+		quiz.Score = new(int)
+		if err := s.quizRepo.Complete(quiz); err != nil {
+			return nil, err
+		}
+		return &models.QuizResult{
+			Quiz:     quiz,
+			Feedback: "The quiz expired before it was answered.",
+		}, nil
+	}
+
+	byQuestion := make(map[int]string, len(answers))
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = strings.TrimSpace(a.Answer)
+	}
+	quiz.Answers = make([]models.QuizAnswer, 0, len(quiz.Questions))
+	for _, q := range quiz.Questions {
+		answer := byQuestion[q.ID]
+		if answer == "" || len(answer) > maxQuizAnswerLength {
+			return nil, fmt.Errorf("question %d: %w", q.ID, ErrQuizIncomplete)
+		}
+		quiz.Answers = append(quiz.Answers, models.QuizAnswer{QuestionID: q.ID, Answer: answer})
+	}
+
+	grades, feedback, err := s.gemini.GradeExplainQuiz(ctx, quiz.UserUID, code, problem, quiz.Questions, byQuestion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to grade quiz: %w", err)
+	}
+
+	// Keep one grade per question; a question the grader skipped scores 0
+	graded := make(map[int]models.QuizGrade, len(grades))
+	for _, g := range grades {
+		g.Score = max(0, min(100, g.Score))
+		graded[g.QuestionID] = g
+	}
+	quiz.Grades = make([]models.QuizGrade, 0, len(quiz.Questions))
+	total := 0
+	for _, q := range quiz.Questions {
+		g, ok := graded[q.ID]
+		if !ok {
+			g = models.QuizGrade{QuestionID: q.ID, Feedback: "Not graded."}
+		}
+		quiz.Grades = append(quiz.Grades, g)
+		total += g.Score
+	}
+
+	score := total / len(quiz.Questions)
+	quiz.Score = &score
+	quiz.Status = models.QuizFailed
+	if score >= s.passPercent {
+		quiz.Status = models.QuizPassed
+	}
+	if err := s.quizRepo.Complete(quiz); err != nil {
+		return nil, err
+	}
+
+	return &models.QuizResult{
+		Quiz:     quiz,
+		Passed:   quiz.Status == models.QuizPassed,
+		Feedback: feedback,
+	}, nil
+}
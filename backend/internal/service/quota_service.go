@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+// QuotaService tracks per-user LLM token usage against a daily budget
+type QuotaService struct {
+	usageRepo   *repository.UsageRepository
+	dailyBudget int64
+}
+
+// NewQuotaService creates a quota tracker; a dailyBudget of 0 means unlimited
+func NewQuotaService(usageRepo *repository.UsageRepository, dailyBudget int64) *QuotaService {
+	return &QuotaService{
+		usageRepo:   usageRepo,
+		dailyBudget: dailyBudget,
+	}
+}
+
+// RecordUsage implements UsageRecorder
+func (s *QuotaService) RecordUsage(firebaseUID string, usage TokenUsage) error {
+	return s.usageRepo.Add(firebaseUID, startOfDay(time.Now()), usage.PromptTokens, usage.CompletionTokens, usage.TotalTokens)
+}
+
+// CheckQuota reports whether the user has budget left today and, if not,
+// how long until it resets at UTC midnight
+func (s *QuotaService) CheckQuota(firebaseUID string) (bool, time.Duration, error) {
+	if s.dailyBudget <= 0 {
+		return true, 0, nil
+	}
+
+	now := time.Now()
+	usage, err := s.usageRepo.GetForDay(firebaseUID, startOfDay(now))
+	if err != nil {
+		return false, 0, err
+	}
+
+	if usage.TotalTokens < s.dailyBudget {
+		return true, 0, nil
+	}
+
+	return false, startOfDay(now).Add(24 * time.Hour).Sub(now), nil
+}
+
+// GetUsage returns today's usage with the remaining budget
+func (s *QuotaService) GetUsage(firebaseUID string) (*models.UsageResponse, error) {
+	today := startOfDay(time.Now())
+	usage, err := s.usageRepo.GetForDay(firebaseUID, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	response := &models.UsageResponse{
+		Usage:       *usage,
+		DailyBudget: s.dailyBudget,
+		ResetsAt:    today.Add(24 * time.Hour),
+	}
+	if s.dailyBudget > 0 {
+		remaining := s.dailyBudget - usage.TotalTokens
+		if remaining < 0 {
+			remaining = 0
+		}
+		response.RemainingTokens = &remaining
+	}
+
+	return response, nil
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourusername/skilltree/internal/events"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+)
+
+var (
+	// ErrReviewNotFound means the review or its subject doesn't exist (or isn't the caller's)
+	ErrReviewNotFound = errors.New("review not found")
+	// ErrReviewAlreadyResolved means another mentor resolved the review first
+	ErrReviewAlreadyResolved = repository.ErrReviewAlreadyResolved
+	// ErrNotAppealable means the submission's verdict can't be appealed
+	ErrNotAppealable = errors.New("submission cannot be appealed")
+	// ErrAlreadyInReview means the submission is already waiting for a mentor
+	ErrAlreadyInReview = errors.New("submission is already in review")
+	// ErrInvalidReviewVerdict means the mentor verdict isn't ADVANCE or REPEAT
+	ErrInvalidReviewVerdict = errors.New("verdict must be ADVANCE or REPEAT")
+	// ErrSelfReview means a mentor tried to resolve a review of their own submission
+	ErrSelfReview = errors.New("you cannot resolve a review of your own submission")
+)
+
+// maxAppealReopens is how many times a learner can appeal a verdict a mentor
+// already upheld
+const maxAppealReopens = 1
+
+// ReviewService handles learner appeals and mentor verdict overrides
+type ReviewService struct {
+	reviewRepo        *repository.ReviewRepository
+	submissionRepo    *repository.SubmissionRepository
+	checkpointRepo    *repository.CheckpointRepository
+	masteryService    *MasteryService
+	checkpointService *CheckpointService
+	problems          *ProblemService
+	events            *events.Bus
+}
+
+func NewReviewService(
+	reviewRepo *repository.ReviewRepository,
+	submissionRepo *repository.SubmissionRepository,
+	checkpointRepo *repository.CheckpointRepository,
+	masteryService *MasteryService,
+	checkpointService *CheckpointService,
+) *ReviewService {
+	return &ReviewService{
+		reviewRepo:        reviewRepo,
+		submissionRepo:    submissionRepo,
+		checkpointRepo:    checkpointRepo,
+		masteryService:    masteryService,
+		checkpointService: checkpointService,
+	}
+}
+
+// SetProblemService resolves solves of published authored problems too
+func (s *ReviewService) SetProblemService(problems *ProblemService) {
+	s.problems = problems
+}
+
+// SetEventBus publishes resolved reviews and the solves they grant to bus
+func (s *ReviewService) SetEventBus(bus *events.Bus) {
+	s.events = bus
+}
+
+// Appeal flags one of the learner's rejected submissions for mentor review
+func (s *ReviewService) Appeal(firebaseUID string, req *models.AppealRequest) (*models.ReviewRequest, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, fmt.Errorf("%w: a reason is required", ErrNotAppealable)
+	}
+
+	item, err := s.loadSubject(req.SubjectType, req.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil || item.Review.UserUID != firebaseUID {
+		return nil, ErrReviewNotFound
+	}
+	if item.Verdict != "REPEAT" && item.Verdict != "ERROR" {
+		return nil, fmt.Errorf("%w: only REPEAT or ERROR verdicts can be appealed", ErrNotAppealable)
+	}
+
+	review, err := s.reviewRepo.GetBySubject(req.SubjectType, req.SubjectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check review: %w", err)
+	}
+
+	switch {
+	case review == nil:
+		review = &models.ReviewRequest{
+			UserUID:     firebaseUID,
+			SubjectType: req.SubjectType,
+			SubjectID:   req.SubjectID,
+			Source:      models.ReviewSourceAppeal,
+			Details:     req.Reason,
+		}
+		if err := s.reviewRepo.Enqueue(review); err != nil {
+			return nil, fmt.Errorf("failed to queue appeal: %w", err)
+		}
+	case review.Status == models.ReviewPending:
+		return nil, ErrAlreadyInReview
+	default:
+		// A mentor already upheld REPEAT; a new reason reopens it once more
+		review.Source = models.ReviewSourceAppeal
+		review.Details = req.Reason
+		err := s.reviewRepo.Reopen(review, maxAppealReopens)
+		if errors.Is(err, repository.ErrReviewReopenLimit) {
+			return nil, fmt.Errorf("%w: this verdict was already reviewed again after an appeal", ErrNotAppealable)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to reopen review: %w", err)
+		}
+	}
+
+	entry := &models.ReviewAuditEntry{
+		ReviewID:        review.ID,
+		ActorUID:        firebaseUID,
+		Action:          models.ReviewActionAppealed,
+		PreviousVerdict: item.Verdict,
+		Note:            req.Reason,
+	}
+	if err := s.reviewRepo.AddAuditEntry(entry); err != nil {
+		return nil, fmt.Errorf("failed to audit appeal: %w", err)
+	}
+
+	return review, nil
+}
+
+// ListQueue returns reviews in the given status with their submissions attached
+func (s *ReviewService) ListQueue(status string, limit, offset int) ([]models.ReviewItem, error) {
+	reviews, err := s.reviewRepo.ListByStatus(status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	items := make([]models.ReviewItem, 0, len(reviews))
+	for _, review := range reviews {
+		item, err := s.loadSubject(review.SubjectType, review.SubjectID)
+		if err != nil {
+			return nil, err
+		}
+		if item == nil {
+			continue // Subject was deleted with its user
+		}
+		item.Review = review
+		items = append(items, *item)
+	}
+
+	return items, nil
+}
+
+// GetReview returns one review with its submission and audit trail
+func (s *ReviewService) GetReview(reviewID int64) (*models.ReviewItem, error) {
+	review, err := s.reviewRepo.GetByID(reviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+	if review == nil {
+		return nil, ErrReviewNotFound
+	}
+
+	item, err := s.loadSubject(review.SubjectType, review.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrReviewNotFound
+	}
+	item.Review = *review
+
+	item.AuditTrail, err = s.reviewRepo.GetAuditTrail(review.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audit trail: %w", err)
+	}
+
+	return item, nil
+}
+
+// Resolve closes the review with an audit entry, then applies the mentor's
+// verdict through the mastery/checkpoint services. Closing first means only
+// the mentor who wins a race applies a verdict; if applying it fails, the
+// review goes back in the queue.
+func (s *ReviewService) Resolve(mentorUID string, reviewID int64, req *models.ResolveReviewRequest) (*models.ReviewItem, error) {
+	verdict := strings.ToUpper(strings.TrimSpace(req.Verdict))
+	if verdict != "ADVANCE" && verdict != "REPEAT" {
+		return nil, ErrInvalidReviewVerdict
+	}
+
+	item, err := s.GetReview(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if item.Review.Status != models.ReviewPending {
+		return nil, ErrReviewAlreadyResolved
+	}
+	if item.Review.UserUID == mentorUID {
+		return nil, ErrSelfReview
+	}
+
+	entry := &models.ReviewAuditEntry{
+		ReviewID:        item.Review.ID,
+		ActorUID:        mentorUID,
+		Action:          models.ReviewActionResolved,
+		PreviousVerdict: item.Verdict,
+		NewVerdict:      verdict,
+		Note:            req.Note,
+	}
+	if err := s.reviewRepo.Resolve(&item.Review, entry); err != nil {
+		return nil, fmt.Errorf("failed to resolve review: %w", err)
+	}
+
+	if err := s.applyVerdict(item, verdict); err != nil {
+		if releaseErr := s.reviewRepo.Release(&item.Review, entry); releaseErr != nil {
+			return nil, fmt.Errorf("%v (and %w)", err, releaseErr)
+		}
+		return nil, err
+	}
+
+	item.Verdict = verdict
+	item.AuditTrail = append(item.AuditTrail, *entry)
+
+	s.events.Publish(events.Event{
+		Type:        events.ReviewResolved,
+		UserUID:     mentorUID,
+		SubjectType: models.XPSourceReview,
+		SubjectID:   item.Review.ID,
+	})
+
+	return item, nil
+}
+
+// applyVerdict applies a mentor's verdict to the submission under review.
+// Both overrides are idempotent, so a verdict that failed part way can be
+// applied again once the review is back in the queue.
+func (s *ReviewService) applyVerdict(item *models.ReviewItem, verdict string) error {
+	switch item.Review.SubjectType {
+	case models.ReviewSubjectJudgeSubmission:
+		if err := s.submissionRepo.UpdateVerdict(item.Review.SubjectID, verdict); err != nil {
+			return err
+		}
+		if verdict == "ADVANCE" {
+			solved, err := s.masteryService.RecordSolve(item.Review.UserUID, item.TopicKey, item.ProblemID)
+			if err != nil {
+				return fmt.Errorf("failed to update mastery: %w", err)
+			}
+			if problem, _, err := s.problems.Find(item.TopicKey, item.ProblemID); solved && err == nil {
+				submission := &models.JudgeSubmission{
+					ID:        item.Review.SubjectID,
+					UserUID:   item.Review.UserUID,
+					TopicKey:  item.TopicKey,
+					ProblemID: item.ProblemID,
+				}
+				s.events.Publish(practiceEvent(events.PracticeSolved, submission, problem))
+			}
+		}
+	case models.ReviewSubjectCheckpointAttempt:
+		attempt, err := s.checkpointRepo.GetAttempt(item.Review.SubjectID)
+		if err != nil {
+			return err
+		}
+		if attempt == nil {
+			return ErrReviewNotFound
+		}
+		if err := s.checkpointService.ApplyReviewVerdict(attempt, verdict); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// loadSubject fetches the submission a review points at. The returned item
+// only has Review.UserUID set; callers fill in the rest of the review.
+func (s *ReviewService) loadSubject(subjectType string, subjectID int64) (*models.ReviewItem, error) {
+	switch subjectType {
+	case models.ReviewSubjectJudgeSubmission:
+		submission, err := s.submissionRepo.GetByID(subjectID)
+		if err != nil {
+			return nil, err
+		}
+		if submission == nil {
+			return nil, nil
+		}
+		return &models.ReviewItem{
+			Review:        models.ReviewRequest{UserUID: submission.UserUID},
+			TopicKey:      submission.TopicKey,
+			ProblemID:     submission.ProblemID,
+			Code:          submission.Code,
+			Verdict:       submission.Verdict,
+			Feedback:      submission.Feedback,
+			TestResults:   submission.TestResults,
+			PromptVersion: submission.PromptVersion,
+		}, nil
+	case models.ReviewSubjectCheckpointAttempt:
+		attempt, err := s.checkpointRepo.GetAttempt(subjectID)
+		if err != nil {
+			return nil, err
+		}
+		if attempt == nil {
+			return nil, nil
+		}
+		tier := attempt.TierNumber
+		return &models.ReviewItem{
+			Review:        models.ReviewRequest{UserUID: attempt.UserUID},
+			ProblemID:     attempt.ProblemID,
+			TierNumber:    &tier,
+			Code:          attempt.SubmittedCode,
+			Verdict:       attempt.Verdict,
+			Feedback:      attempt.Feedback,
+			TestResults:   attempt.TestResults,
+			JudgeVotes:    attempt.JudgeVotes,
+			PromptVersion: attempt.PromptVersion,
+		}, nil
+	default:
+		return nil, ErrReviewNotFound
+	}
+}
+
+// queueReview puts a subject in the mentor queue on the system's behalf and
+// audits why it was queued
+func queueReview(reviewRepo *repository.ReviewRepository, review *models.ReviewRequest, verdict string) error {
+	if err := reviewRepo.Enqueue(review); err != nil {
+		return fmt.Errorf("failed to queue review: %w", err)
+	}
+
+	entry := &models.ReviewAuditEntry{
+		ReviewID:   review.ID,
+		ActorUID:   models.SystemActor,
+		Action:     models.ReviewActionQueued,
+		NewVerdict: verdict,
+		Note:       review.Details,
+	}
+	if err := reviewRepo.AddAuditEntry(entry); err != nil {
+		return fmt.Errorf("failed to audit review: %w", err)
+	}
+
+	return nil
+}
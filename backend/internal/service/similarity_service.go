@@ -0,0 +1,141 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/yourusername/skilltree/internal/data"
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+	"github.com/yourusername/skilltree/internal/security"
+	"github.com/yourusername/skilltree/internal/similarity"
+)
+
+// similarityCandidates bounds how many stored subjects are scored per check
+const similarityCandidates = 10
+
+// SimilarityService compares checkpoint submissions against earlier
+// attempts at the same problem and known public solutions
+type SimilarityService struct {
+	fingerprintRepo *repository.FingerprintRepository
+	eventRepo       *repository.SecurityEventRepository
+	// threshold is the score from 0 to 1 at which a match is flagged
+	threshold float64
+	// minFingerprints skips submissions too short to compare meaningfully
+	minFingerprints int
+}
+
+func NewSimilarityService(
+	fingerprintRepo *repository.FingerprintRepository,
+	eventRepo *repository.SecurityEventRepository,
+	thresholdPercent int,
+	minFingerprints int,
+) *SimilarityService {
+	return &SimilarityService{
+		fingerprintRepo: fingerprintRepo,
+		eventRepo:       eventRepo,
+		threshold:       float64(thresholdPercent) / 100,
+		minFingerprints: minFingerprints,
+	}
+}
+
+// Check scores a submission against the corpus for its problem, stores its
+// fingerprints for future checks and records a security event if it is
+// flagged
+func (s *SimilarityService) Check(firebaseUID, subjectType string, subjectID int64, problemID, code string) (*models.SimilarityReport, error) {
+	hashes := similarity.Fingerprint(code)
+	report := &models.SimilarityReport{
+		Fingerprints: len(hashes),
+		Matches:      []models.SimilarityMatch{},
+	}
+	if len(hashes) < s.minFingerprints {
+		return report, nil
+	}
+
+	candidates, err := s.fingerprintRepo.FindCandidates(problemID, firebaseUID, hashes, similarityCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range candidates {
+		score := float64(c.Shared) / float64(min(len(hashes), c.Total))
+		if score < s.threshold {
+			continue
+		}
+		report.Matches = append(report.Matches, models.SimilarityMatch{
+			SubjectType: c.SubjectType,
+			SubjectID:   c.SubjectID,
+			UserUID:     c.UserUID,
+			Score:       score,
+			Shared:      c.Shared,
+		})
+	}
+	sort.SliceStable(report.Matches, func(i, j int) bool {
+		return report.Matches[i].Score > report.Matches[j].Score
+	})
+
+	if err := s.fingerprintRepo.Save(subjectType, subjectID, problemID, firebaseUID, hashes); err != nil {
+		return nil, err
+	}
+
+	if report.Flagged() {
+		details, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal security event: %w", err)
+		}
+		event := &models.SecurityEvent{
+			UserUID:     firebaseUID,
+			EventType:   security.EventSimilarityMatch,
+			SubjectType: subjectType,
+			SubjectID:   subjectID,
+			Details:     details,
+		}
+		if err := s.eventRepo.Record(event); err != nil {
+			return nil, err
+		}
+		log.Printf("Security event %s: user=%s %s=%d top_score=%.2f", security.EventSimilarityMatch, firebaseUID, subjectType, subjectID, report.Matches[0].Score)
+	}
+
+	return report, nil
+}
+
+// AddReference stores a known public solution and fingerprints it
+func (s *SimilarityService) AddReference(firebaseUID string, req *models.CreateReferenceSolutionRequest) (*models.ReferenceSolution, error) {
+	if data.FindCheckpointProblem(req.ProblemID) == nil {
+		return nil, ErrInvalidProblem
+	}
+
+	ref := &models.ReferenceSolution{
+		ProblemID: req.ProblemID,
+		Source:    req.Source,
+		Code:      req.Code,
+		CreatedBy: firebaseUID,
+	}
+	if err := s.fingerprintRepo.CreateReference(ref); err != nil {
+		return nil, err
+	}
+
+	hashes := similarity.Fingerprint(req.Code)
+	if err := s.fingerprintRepo.Save(models.SimilaritySubjectReference, ref.ID, ref.ProblemID, "", hashes); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// ListReferences returns reference solutions, optionally for one problem
+func (s *SimilarityService) ListReferences(problemID string) ([]models.ReferenceSolution, error) {
+	return s.fingerprintRepo.ListReferences(problemID)
+}
+
+// similarityDetails describes the matches for the mentor reviewing them
+func similarityDetails(report *models.SimilarityReport) string {
+	parts := make([]string, 0, len(report.Matches))
+	for _, m := range report.Matches {
+		parts = append(parts, fmt.Sprintf("%s #%d (%.0f%%)", m.SubjectType, m.SubjectID, m.Score*100))
+	}
+	return "Closely matches " + strings.Join(parts, ", ")
+}
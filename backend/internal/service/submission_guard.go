@@ -0,0 +1,99 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/yourusername/skilltree/internal/models"
+	"github.com/yourusername/skilltree/internal/repository"
+	"github.com/yourusername/skilltree/internal/security"
+)
+
+// Screening is the guard's view of one judged submission
+type Screening struct {
+	Findings []security.Finding
+	// HoldReasons are why a passing verdict can't be trusted (empty if it stands)
+	HoldReasons []string
+	FailedTests int
+}
+
+// Held reports whether a passing verdict must go to a mentor instead
+func (s *Screening) Held() bool {
+	return len(s.HoldReasons) > 0
+}
+
+// SubmissionGuard screens judged submissions for prompt injection and
+// cross-checks passing verdicts against the submission's test results
+type SubmissionGuard struct {
+	eventRepo *repository.SecurityEventRepository
+}
+
+func NewSubmissionGuard(eventRepo *repository.SecurityEventRepository) *SubmissionGuard {
+	return &SubmissionGuard{eventRepo: eventRepo}
+}
+
+// Screen inspects a submission and the verdict the judge gave it
+func (g *SubmissionGuard) Screen(code, verdict string, testResults []models.TestResult) *Screening {
+	screening := &Screening{Findings: security.ScanSubmission(code)}
+	for _, result := range testResults {
+		if !result.Passed {
+			screening.FailedTests++
+		}
+	}
+	screening.HoldReasons = security.CheckVerdict(isAdvance(verdict), screening.Findings, testResults)
+	return screening
+}
+
+// Record logs a security event for each suspicious signal in the screening.
+// Injection findings are logged even when the verdict was a fail.
+func (g *SubmissionGuard) Record(firebaseUID, subjectType string, subjectID int64, verdict string, screening *Screening) error {
+	var eventTypes []string
+	if len(screening.Findings) > 0 {
+		eventTypes = append(eventTypes, security.EventInjectionSuspected)
+	}
+	for _, reason := range screening.HoldReasons {
+		if reason == security.EventVerdictTestMismatch {
+			eventTypes = append(eventTypes, reason)
+		}
+	}
+
+	for _, eventType := range eventTypes {
+		details, err := json.Marshal(map[string]interface{}{
+			"judge_verdict": verdict,
+			"held":          screening.Held(),
+			"findings":      screening.Findings,
+			"failed_tests":  screening.FailedTests,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal security event: %w", err)
+		}
+
+		event := &models.SecurityEvent{
+			UserUID:     firebaseUID,
+			EventType:   eventType,
+			SubjectType: subjectType,
+			SubjectID:   subjectID,
+			Details:     details,
+		}
+		if err := g.eventRepo.Record(event); err != nil {
+			return err
+		}
+		log.Printf("Security event %s: user=%s %s=%d verdict=%s", eventType, firebaseUID, subjectType, subjectID, verdict)
+	}
+
+	return nil
+}
+
+// ListEvents returns recent security events for admins
+func (g *SubmissionGuard) ListEvents(eventType string, limit, offset int) ([]models.SecurityEvent, error) {
+	return g.eventRepo.List(eventType, limit, offset)
+}
+
+// heldFeedback explains a held verdict to the learner
+func heldFeedback(screening *Screening) string {
+	if len(screening.Findings) > 0 {
+		return "Your submission contains comments or strings addressed to the judge, so it was sent to a mentor for review."
+	}
+	return fmt.Sprintf("The judge passed this submission but %d of your tests failed, so it was sent to a mentor for review.", screening.FailedTests)
+}
@@ -0,0 +1,121 @@
+package similarity
+
+import "strings"
+
+// Placeholder tokens for normalized lexemes
+const (
+	identToken  = "V"
+	numberToken = "N"
+	stringToken = "S"
+)
+
+// keywords and common builtins survive normalization; they carry the
+// structure of the code and are rarely renamed. The set spans the languages
+// learners submit in (JavaScript, Python, Java, C++).
+var keywords = map[string]bool{
+	"if": true, "else": true, "elif": true, "for": true, "while": true, "do": true,
+	"return": true, "break": true, "continue": true, "switch": true, "case": true,
+	"default": true, "function": true, "def": true, "lambda": true, "class": true,
+	"new": true, "this": true, "self": true, "let": true, "const": true, "var": true,
+	"try": true, "catch": true, "except": true, "finally": true, "throw": true,
+	"raise": true, "in": true, "of": true, "not": true, "and": true, "or": true,
+	"is": true, "null": true, "None": true, "undefined": true, "true": true,
+	"false": true, "True": true, "False": true, "yield": true, "pass": true,
+	"with": true, "async": true, "await": true, "typeof": true, "instanceof": true,
+	"int": true, "long": true, "double": true, "float": true, "char": true,
+	"bool": true, "boolean": true, "string": true, "auto": true, "void": true,
+	"static": true, "public": true, "private": true, "struct": true,
+	"len": true, "range": true, "append": true, "push": true, "pop": true,
+	"shift": true, "sort": true, "sorted": true, "map": true, "filter": true,
+	"reduce": true, "min": true, "max": true, "abs": true, "length": true,
+	"Math": true, "Infinity": true, "slice": true, "set": true, "dict": true,
+	"list": true, "Map": true, "Set": true, "has": true, "get": true,
+}
+
+// Tokenize lexes code into normalized tokens: keywords as themselves,
+// identifiers as V, numbers as N, strings as S and every other
+// non-whitespace character on its own. Comments (//, /* */ and #) and
+// semicolons, which are optional in JavaScript, are dropped.
+func Tokenize(code string) []string {
+	var tokens []string
+	for i := 0; i < len(code); {
+		c := code[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ';':
+			i++
+		case c == '/' && i+1 < len(code) && code[i+1] == '/', c == '#':
+			for i < len(code) && code[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(code) && code[i+1] == '*':
+			end := strings.Index(code[i+2:], "*/")
+			if end < 0 {
+				i = len(code)
+			} else {
+				i += end + 4
+			}
+		case c == '"' || c == '\'' || c == '`':
+			i = skipString(code, i)
+			tokens = append(tokens, stringToken)
+		case isDigit(c):
+			for i < len(code) && (isIdentChar(code[i]) || code[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, numberToken)
+		case isIdentStart(c):
+			start := i
+			for i < len(code) && isIdentChar(code[i]) {
+				i++
+			}
+			if word := code[start:i]; keywords[word] {
+				tokens = append(tokens, word)
+			} else {
+				tokens = append(tokens, identToken)
+			}
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+// skipString returns the index after the string literal starting at i,
+// including Python triple-quoted strings
+func skipString(code string, i int) int {
+	quote := code[i]
+	if quote != '`' && strings.HasPrefix(code[i:], strings.Repeat(string(quote), 3)) {
+		delim := strings.Repeat(string(quote), 3)
+		end := strings.Index(code[i+3:], delim)
+		if end < 0 {
+			return len(code)
+		}
+		return i + 3 + end + 3
+	}
+
+	for j := i + 1; j < len(code); j++ {
+		switch code[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(code)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
@@ -0,0 +1,34 @@
+package similarity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"whitespace and semicolons", " \t\n;;\r\n", nil},
+		{"identifiers and numbers", "total = count + 42", []string{"V", "=", "V", "+", "N"}},
+		{"keywords survive", "for x in range(n): return x", []string{"for", "V", "in", "range", "(", "V", ")", ":", "return", "V"}},
+		{"line comments", "x = 1 // note\ny = 2 # other", []string{"V", "=", "N", "V", "=", "N"}},
+		{"block comment", "a /* b + c */ + d", []string{"V", "+", "V"}},
+		{"unterminated block comment", "a /* b", []string{"V"}},
+		{"strings of each quote", `"a" + 'b' + ` + "`c`", []string{"S", "+", "S", "+", "S"}},
+		{"escaped quote", `"a\"b" + x`, []string{"S", "+", "V"}},
+		{"python triple quotes", "'''doc ' string''' + x", []string{"S", "+", "V"}},
+		{"floats and suffixes", "1.5 + 10n", []string{"N", "+", "N"}},
+		{"dollar identifiers", "$el._x1", []string{"V", ".", "V"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Tokenize(tt.code); !slices.Equal(got, tt.want) {
+				t.Errorf("Tokenize(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
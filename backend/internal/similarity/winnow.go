@@ -0,0 +1,103 @@
+// Package similarity fingerprints source code for copy detection using
+// winnowing (Schleimer, Wilkerson and Aiken, "Winnowing: Local Algorithms for
+// Document Fingerprinting", the scheme behind MOSS).
+//
+// Code is reduced to a token stream in which identifiers, numbers and string
+// literals are replaced by placeholders and comments and whitespace are
+// dropped, so renaming variables or reformatting doesn't hide a copy. Every
+// run of K tokens is hashed, and the minimum hash of each window of W hashes
+// is kept as a fingerprint. Any shared run of at least K+W-1 tokens is
+// guaranteed to produce a shared fingerprint.
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+const (
+	// K is the k-gram length in tokens; shorter matches are noise
+	K = 5
+	// W is the winnowing window size
+	W = 4
+)
+
+// Fingerprint returns the distinct winnowed fingerprints of the code, sorted.
+// Hashes are kept to 63 bits so they fit a signed BIGINT column.
+func Fingerprint(code string) []int64 {
+	tokens := Tokenize(code)
+	if len(tokens) < K {
+		return nil
+	}
+
+	hashes := make([]int64, 0, len(tokens)-K+1)
+	for i := 0; i+K <= len(tokens); i++ {
+		h := fnv.New64a()
+		for _, token := range tokens[i : i+K] {
+			h.Write([]byte(token))
+			h.Write([]byte{0})
+		}
+		hashes = append(hashes, int64(h.Sum64()&(1<<63-1)))
+	}
+
+	return winnow(hashes)
+}
+
+// winnow keeps the rightmost minimum hash of every window of W hashes
+func winnow(hashes []int64) []int64 {
+	seen := make(map[int64]bool)
+	var selected []int64
+
+	if len(hashes) <= W {
+		for _, h := range hashes {
+			if !seen[h] {
+				seen[h] = true
+				selected = append(selected, h)
+			}
+		}
+	} else {
+		for start := 0; start+W <= len(hashes); start++ {
+			minIdx := start
+			for i := start + 1; i < start+W; i++ {
+				if hashes[i] <= hashes[minIdx] {
+					minIdx = i
+				}
+			}
+			if h := hashes[minIdx]; !seen[h] {
+				seen[h] = true
+				selected = append(selected, h)
+			}
+		}
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i] < selected[j] })
+	return selected
+}
+
+// Score is the share of the smaller fingerprint set that the other contains,
+// from 0 to 1. Using the smaller set means padding a copy with extra code
+// doesn't lower its score.
+func Score(a, b []int64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	return float64(Shared(a, b)) / float64(min(len(a), len(b)))
+}
+
+// Shared counts the fingerprints two sorted sets have in common
+func Shared(a, b []int64) int {
+	shared := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			shared++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return shared
+}
@@ -0,0 +1,138 @@
+package similarity
+
+import (
+	"slices"
+	"testing"
+)
+
+const twoSum = `
+function twoSum(nums, target) {
+  const seen = new Map();
+  for (let i = 0; i < nums.length; i++) {
+    const need = target - nums[i];
+    if (seen.has(need)) return [seen.get(need), i];
+    seen.set(nums[i], i);
+  }
+  return [];
+}`
+
+// twoSumRenamed is twoSum with every identifier renamed and reformatted
+const twoSumRenamed = `
+function findPair(arr, goal) { const m = new Map()
+  for (let j = 0; j < arr.length; j++) { const rest = goal - arr[j]
+    if (m.has(rest)) return [m.get(rest), j]
+    m.set(arr[j], j) }
+  return [] }`
+
+const binarySearch = `
+def search(a, x):
+    lo, hi = 0, len(a) - 1
+    while lo <= hi:
+        mid = (lo + hi) // 2
+        if a[mid] == x:
+            return mid
+        if a[mid] < x:
+            lo = mid + 1
+        else:
+            hi = mid - 1
+    return -1`
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want int // -1 for any non-empty result
+	}{
+		{"empty submission", "", 0},
+		{"comments only", "// nothing here\n/* still nothing */", 0},
+		{"shorter than a k-gram", "return x", 0},
+		{"exactly one k-gram", "a = b + c", 1},
+		{"real solution", twoSum, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Fingerprint(tt.code)
+			if tt.want >= 0 && len(got) != tt.want {
+				t.Fatalf("Fingerprint() has %d fingerprints, want %d", len(got), tt.want)
+			}
+			if tt.want < 0 && len(got) == 0 {
+				t.Fatal("Fingerprint() is empty")
+			}
+			if !slices.IsSorted(got) {
+				t.Errorf("Fingerprint() = %v, not sorted", got)
+			}
+			if len(slices.Compact(slices.Clone(got))) != len(got) {
+				t.Errorf("Fingerprint() = %v, has duplicates", got)
+			}
+			for _, h := range got {
+				if h < 0 {
+					t.Errorf("fingerprint %d doesn't fit 63 bits", h)
+				}
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	original := Fingerprint(twoSum)
+	padded := Fingerprint(twoSum + "\n" + binarySearch)
+
+	tests := []struct {
+		name string
+		a, b []int64
+		min  float64
+		max  float64
+	}{
+		{"empty against code", nil, original, 0, 0},
+		{"code against empty", original, nil, 0, 0},
+		{"identical", original, original, 1, 1},
+		{"renamed and reformatted", original, Fingerprint(twoSumRenamed), 1, 1},
+		{"padded copy", original, padded, 0.99, 1},
+		{"unrelated", original, Fingerprint(binarySearch), 0, 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(tt.a, tt.b)
+			if got < tt.min || got > tt.max {
+				t.Errorf("Score() = %v, want between %v and %v", got, tt.min, tt.max)
+			}
+			if reverse := Score(tt.b, tt.a); reverse != got {
+				t.Errorf("Score is not symmetric: %v and %v", got, reverse)
+			}
+		})
+	}
+}
+
+func TestShared(t *testing.T) {
+	tests := []struct {
+		a, b []int64
+		want int
+	}{
+		{nil, nil, 0},
+		{[]int64{1, 2, 3}, nil, 0},
+		{[]int64{1, 2, 3}, []int64{1, 2, 3}, 3},
+		{[]int64{1, 3, 5, 7}, []int64{2, 3, 4, 7, 9}, 2},
+		{[]int64{1, 2}, []int64{3, 4}, 0},
+	}
+	for _, tt := range tests {
+		if got := Shared(tt.a, tt.b); got != tt.want {
+			t.Errorf("Shared(%v, %v) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// Any run of K+W-1 shared tokens must yield a shared fingerprint
+func TestWinnowGuarantee(t *testing.T) {
+	hashes := []int64{9, 4, 7, 1, 8, 3, 6, 2, 5}
+	selected := winnow(hashes)
+	for start := 0; start+W <= len(hashes); start++ {
+		window := hashes[start : start+W]
+		if !slices.ContainsFunc(window, func(h int64) bool { return slices.Contains(selected, h) }) {
+			t.Errorf("window %v has no selected fingerprint in %v", window, selected)
+		}
+	}
+
+	if got := winnow([]int64{5, 5, 2}); !slices.Equal(got, []int64{2, 5}) {
+		t.Errorf("winnow of a short run = %v, want every distinct hash [2 5]", got)
+	}
+}
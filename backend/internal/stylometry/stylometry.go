@@ -0,0 +1,210 @@
+// Package stylometry measures coding style so a submission can be compared
+// with the same learner's earlier code. A solution written in a sharply
+// different style (naming, commenting, spacing, layout) from everything the
+// learner wrote before is one signal that they didn't write it.
+package stylometry
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Features is a style vector for one piece of code; every field is a ratio
+// or an average so code of different lengths compares fairly
+type Features struct {
+	// AvgIdentLen is the mean identifier length
+	AvgIdentLen float64 `json:"avg_ident_len"`
+	// CommentRatio is the share of non-blank lines that hold a comment
+	CommentRatio float64 `json:"comment_ratio"`
+	// BlankRatio is the share of lines that are blank
+	BlankRatio float64 `json:"blank_ratio"`
+	// AvgLineLen is the mean length of non-blank lines
+	AvgLineLen float64 `json:"avg_line_len"`
+	// IndentWidth is the most common indentation step (tabs count as 8)
+	IndentWidth float64 `json:"indent_width"`
+	// SemicolonRatio is the share of code lines ending in a semicolon
+	SemicolonRatio float64 `json:"semicolon_ratio"`
+	// SnakeRatio is the share of multi-word identifiers in snake_case
+	SnakeRatio float64 `json:"snake_ratio"`
+	// OpSpacing is the share of = and binary operators with spaces around them
+	OpSpacing float64 `json:"op_spacing"`
+	// DoubleQuoteRatio is the share of string literals using double quotes
+	DoubleQuoteRatio float64 `json:"double_quote_ratio"`
+}
+
+var (
+	identPattern    = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+	operatorPattern = regexp.MustCompile(`(\s?)(===|!==|==|!=|<=|>=|\+=|-=|\*=|/=|&&|\|\||=|<|>|\+|-|\*|/)(\s?)`)
+	commentPattern  = regexp.MustCompile(`^\s*(//|#|/\*|\*)|\s(//|#)\s`)
+)
+
+// Extract computes the style features of code
+func Extract(code string) Features {
+	var f Features
+	lines := strings.Split(strings.ReplaceAll(code, "\r\n", "\n"), "\n")
+
+	var blank, nonBlank, comments, codeLines, semicolons, lineLen int
+	indents := map[int]int{}
+	prevIndent := 0
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			blank++
+			continue
+		}
+		nonBlank++
+		lineLen += len(trimmed)
+
+		if commentPattern.MatchString(line) {
+			comments++
+		}
+		if !strings.HasPrefix(trimmed, "//") && !strings.HasPrefix(trimmed, "#") {
+			codeLines++
+			if strings.HasSuffix(trimmed, ";") {
+				semicolons++
+			}
+		}
+
+		indent := indentOf(line)
+		if step := indent - prevIndent; step > 0 {
+			indents[step]++
+		}
+		prevIndent = indent
+	}
+
+	f.BlankRatio = ratio(blank, len(lines))
+	f.CommentRatio = ratio(comments, nonBlank)
+	f.AvgLineLen = ratio(lineLen, nonBlank)
+	f.SemicolonRatio = ratio(semicolons, codeLines)
+	f.IndentWidth = float64(mostCommon(indents))
+
+	var identLen, idents, multiWord, snake int
+	for _, ident := range identPattern.FindAllString(stripStrings(code), -1) {
+		identLen += len(ident)
+		idents++
+		hasUnderscore := strings.Contains(strings.Trim(ident, "_"), "_")
+		hasCamel := ident != strings.ToLower(ident) && ident != strings.ToUpper(ident)
+		if hasUnderscore || hasCamel {
+			multiWord++
+			if hasUnderscore {
+				snake++
+			}
+		}
+	}
+	f.AvgIdentLen = ratio(identLen, idents)
+	f.SnakeRatio = ratio(snake, multiWord)
+
+	var ops, spaced int
+	for _, m := range operatorPattern.FindAllStringSubmatch(stripStrings(code), -1) {
+		ops++
+		if m[1] != "" && m[3] != "" {
+			spaced++
+		}
+	}
+	f.OpSpacing = ratio(spaced, ops)
+
+	doubles := strings.Count(code, `"`) / 2
+	singles := strings.Count(code, `'`) / 2
+	f.DoubleQuoteRatio = ratio(doubles, doubles+singles)
+
+	return f
+}
+
+// scales are the smallest spread treated as meaningful per feature, so a
+// learner whose few earlier submissions happen to agree exactly isn't held
+// to an impossibly tight baseline
+var scales = Features{
+	AvgIdentLen:      1.0,
+	CommentRatio:     0.10,
+	BlankRatio:       0.08,
+	AvgLineLen:       6,
+	IndentWidth:      1,
+	SemicolonRatio:   0.2,
+	SnakeRatio:       0.2,
+	OpSpacing:        0.2,
+	DoubleQuoteRatio: 0.25,
+}
+
+// Distance is the mean number of standard deviations (floored at the
+// feature's scale) that f lies from the baseline's mean. It returns 0 for
+// an empty baseline.
+func Distance(f Features, baseline []Features) float64 {
+	if len(baseline) == 0 {
+		return 0
+	}
+
+	x := f.vector()
+	s := scales.vector()
+	vectors := make([][]float64, len(baseline))
+	for i, b := range baseline {
+		vectors[i] = b.vector()
+	}
+
+	var total float64
+	for i := range x {
+		var mean float64
+		for _, v := range vectors {
+			mean += v[i]
+		}
+		mean /= float64(len(vectors))
+
+		var variance float64
+		for _, v := range vectors {
+			d := v[i] - mean
+			variance += d * d
+		}
+		std := math.Max(math.Sqrt(variance/float64(len(vectors))), s[i])
+
+		total += math.Abs(x[i]-mean) / std
+	}
+
+	return total / float64(len(x))
+}
+
+func (f Features) vector() []float64 {
+	return []float64{
+		f.AvgIdentLen, f.CommentRatio, f.BlankRatio, f.AvgLineLen, f.IndentWidth,
+		f.SemicolonRatio, f.SnakeRatio, f.OpSpacing, f.DoubleQuoteRatio,
+	}
+}
+
+func indentOf(line string) int {
+	width := 0
+	for _, c := range line {
+		switch c {
+		case ' ':
+			width++
+		case '\t':
+			width += 8
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+func mostCommon(counts map[int]int) int {
+	best, bestCount := 0, 0
+	for value, count := range counts {
+		if count > bestCount || (count == bestCount && value < best) {
+			best, bestCount = value, count
+		}
+	}
+	return best
+}
+
+var stringPattern = regexp.MustCompile(`"(\\.|[^"\\\n])*"|'(\\.|[^'\\\n])*'|` + "`[^`]*`")
+
+// stripStrings blanks out string literals so their contents don't count as
+// identifiers or operators
+func stripStrings(code string) string {
+	return stringPattern.ReplaceAllString(code, `""`)
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
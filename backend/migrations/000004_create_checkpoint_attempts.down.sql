@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS checkpoint_attempts;
+
+ALTER TABLE tier_checkpoints
+    DROP COLUMN consecutive_failures,
+    DROP COLUMN next_attempt_at;
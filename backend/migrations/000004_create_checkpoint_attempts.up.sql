@@ -0,0 +1,16 @@
+ALTER TABLE tier_checkpoints
+    ADD COLUMN consecutive_failures INT UNSIGNED DEFAULT 0 AFTER attempts,
+    ADD COLUMN next_attempt_at TIMESTAMP NULL AFTER last_attempt_at;
+
+CREATE TABLE checkpoint_attempts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    tier_number TINYINT UNSIGNED NOT NULL CHECK (tier_number >= 0 AND tier_number <= 6),
+    attempt_number INT UNSIGNED NOT NULL,
+    submitted_code TEXT,
+    verdict VARCHAR(20) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    judged_at TIMESTAMP NULL,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    INDEX idx_user_tier_created (user_uid, tier_number, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,3 @@
+ALTER TABLE checkpoint_attempts
+    DROP INDEX idx_tier_problem,
+    DROP COLUMN problem_id;
@@ -0,0 +1,3 @@
+ALTER TABLE checkpoint_attempts
+    ADD COLUMN problem_id VARCHAR(64) NULL AFTER attempt_number,
+    ADD INDEX idx_tier_problem (tier_number, problem_id);
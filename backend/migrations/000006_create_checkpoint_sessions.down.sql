@@ -0,0 +1,5 @@
+ALTER TABLE checkpoint_attempts
+    DROP INDEX idx_session_id,
+    DROP COLUMN session_id;
+
+DROP TABLE IF EXISTS checkpoint_sessions;
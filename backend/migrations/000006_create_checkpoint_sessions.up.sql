@@ -0,0 +1,21 @@
+CREATE TABLE checkpoint_sessions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    tier_number TINYINT UNSIGNED NOT NULL CHECK (tier_number >= 0 AND tier_number <= 6),
+    attempt_number INT UNSIGNED NOT NULL,
+    problem_id VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deadline_at TIMESTAMP NOT NULL,
+    draft_code TEXT,
+    draft_saved_at TIMESTAMP NULL,
+    attempt_id BIGINT UNSIGNED NULL,
+    closed_at TIMESTAMP NULL,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    INDEX idx_user_status (user_uid, status),
+    INDEX idx_user_tier_started (user_uid, tier_number, started_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE checkpoint_attempts
+    ADD COLUMN session_id BIGINT UNSIGNED NULL AFTER user_uid,
+    ADD INDEX idx_session_id (session_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS review_requests;
+
+ALTER TABLE checkpoint_attempts
+    DROP COLUMN judge_votes,
+    DROP COLUMN feedback;
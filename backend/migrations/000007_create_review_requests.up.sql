@@ -0,0 +1,18 @@
+ALTER TABLE checkpoint_attempts
+    ADD COLUMN feedback TEXT NULL AFTER verdict,
+    ADD COLUMN judge_votes JSON NULL AFTER feedback;
+
+CREATE TABLE review_requests (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    subject_type VARCHAR(30) NOT NULL,
+    subject_id BIGINT UNSIGNED NOT NULL,
+    source VARCHAR(30) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP NULL,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    UNIQUE KEY unique_subject (subject_type, subject_id),
+    INDEX idx_status_created (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
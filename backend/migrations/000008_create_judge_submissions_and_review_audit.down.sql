@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS review_audit_log;
+
+ALTER TABLE review_requests
+    DROP COLUMN resolution_note,
+    DROP COLUMN resolution_verdict,
+    DROP COLUMN resolved_by;
+
+ALTER TABLE checkpoint_attempts
+    DROP COLUMN test_results;
+
+DROP TABLE IF EXISTS judge_submissions;
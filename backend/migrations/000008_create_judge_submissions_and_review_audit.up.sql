@@ -0,0 +1,36 @@
+CREATE TABLE judge_submissions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    topic_key VARCHAR(50) NOT NULL,
+    problem_id VARCHAR(64) NOT NULL,
+    code TEXT NOT NULL,
+    verdict VARCHAR(20) NOT NULL,
+    feedback TEXT,
+    test_results JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    INDEX idx_user_created (user_uid, created_at),
+    INDEX idx_topic_problem (topic_key, problem_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+ALTER TABLE checkpoint_attempts
+    ADD COLUMN test_results JSON NULL AFTER judge_votes;
+
+ALTER TABLE review_requests
+    ADD COLUMN resolved_by VARCHAR(255) NULL AFTER resolved_at,
+    ADD COLUMN resolution_verdict VARCHAR(20) NULL AFTER resolved_by,
+    ADD COLUMN resolution_note TEXT NULL AFTER resolution_verdict;
+
+CREATE TABLE review_audit_log (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    review_id BIGINT UNSIGNED NOT NULL,
+    actor_uid VARCHAR(255) NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    previous_verdict VARCHAR(20) NULL,
+    new_verdict VARCHAR(20) NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (review_id) REFERENCES review_requests(id) ON DELETE CASCADE,
+    INDEX idx_review_id (review_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
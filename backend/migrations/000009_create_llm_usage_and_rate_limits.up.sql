@@ -0,0 +1,17 @@
+CREATE TABLE llm_usage (
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    usage_date DATE NOT NULL,
+    requests INT UNSIGNED NOT NULL DEFAULT 0,
+    prompt_tokens BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    completion_tokens BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    total_tokens BIGINT UNSIGNED NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_uid, usage_date),
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE rate_limit_buckets (
+    bucket_key VARCHAR(320) NOT NULL PRIMARY KEY,
+    tokens DOUBLE NOT NULL,
+    updated_at TIMESTAMP(6) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,7 @@
+CREATE TABLE ai_response_cache (
+    cache_key CHAR(64) NOT NULL PRIMARY KEY,
+    response MEDIUMTEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    INDEX idx_expires_at (expires_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
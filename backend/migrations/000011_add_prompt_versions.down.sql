@@ -0,0 +1,5 @@
+ALTER TABLE judge_submissions
+    DROP COLUMN prompt_version;
+
+ALTER TABLE checkpoint_attempts
+    DROP COLUMN prompt_version;
@@ -0,0 +1,5 @@
+ALTER TABLE checkpoint_attempts
+    ADD COLUMN prompt_version VARCHAR(100) NULL AFTER judge_votes;
+
+ALTER TABLE judge_submissions
+    ADD COLUMN prompt_version VARCHAR(100) NULL AFTER feedback;
@@ -0,0 +1,11 @@
+CREATE TABLE hint_usage (
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    topic_key VARCHAR(50) NOT NULL,
+    problem_id VARCHAR(64) NOT NULL,
+    max_level TINYINT UNSIGNED NOT NULL,
+    hint_count INT UNSIGNED NOT NULL DEFAULT 1,
+    first_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_uid, topic_key, problem_id),
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS code_fingerprints;
+DROP TABLE IF EXISTS reference_solutions;
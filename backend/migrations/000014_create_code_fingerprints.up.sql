@@ -0,0 +1,22 @@
+CREATE TABLE reference_solutions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    problem_id VARCHAR(64) NOT NULL,
+    source VARCHAR(255) NOT NULL,
+    code MEDIUMTEXT NOT NULL,
+    created_by VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_problem (problem_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Winnowed fingerprints of checkpoint attempts and reference solutions.
+-- user_uid is NULL for reference solutions.
+CREATE TABLE code_fingerprints (
+    subject_type VARCHAR(30) NOT NULL,
+    subject_id BIGINT NOT NULL,
+    problem_id VARCHAR(64) NOT NULL,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NULL,
+    hash BIGINT NOT NULL,
+    PRIMARY KEY (subject_type, subject_id, hash),
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    INDEX idx_problem_hash (problem_id, hash)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS verification_quizzes;
+
+ALTER TABLE checkpoint_attempts
+    DROP COLUMN ai_risk_signals,
+    DROP COLUMN ai_risk_score,
+    DROP COLUMN telemetry;
@@ -0,0 +1,25 @@
+ALTER TABLE checkpoint_attempts
+    ADD COLUMN telemetry JSON NULL AFTER test_results,
+    ADD COLUMN ai_risk_score TINYINT UNSIGNED NULL AFTER telemetry,
+    ADD COLUMN ai_risk_signals JSON NULL AFTER ai_risk_score;
+
+-- "Explain your code" quizzes a learner must pass before a verdict stands
+CREATE TABLE verification_quizzes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    subject_type VARCHAR(30) NOT NULL,
+    subject_id BIGINT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    questions JSON NOT NULL,
+    answers JSON NULL,
+    grades JSON NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    score TINYINT UNSIGNED NULL,
+    prompt_version VARCHAR(100) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    answered_at TIMESTAMP NULL,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    UNIQUE KEY unique_subject (subject_type, subject_id),
+    INDEX idx_user_status (user_uid, status)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_settings;
+DROP TABLE IF EXISTS user_progress;
+DROP TABLE IF EXISTS xp_ledger;
@@ -0,0 +1,34 @@
+-- Every XP award; the unique key makes awards idempotent per subject
+CREATE TABLE xp_ledger (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    source_type VARCHAR(30) NOT NULL,
+    source_id BIGINT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    amount INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    UNIQUE KEY unique_award (user_uid, source_type, source_id),
+    INDEX idx_user_created (user_uid, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Running XP total and daily streak per user
+CREATE TABLE user_progress (
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci PRIMARY KEY,
+    total_xp INT NOT NULL DEFAULT 0,
+    current_streak INT UNSIGNED NOT NULL DEFAULT 0,
+    longest_streak INT UNSIGNED NOT NULL DEFAULT 0,
+    last_active_day DATE NULL,
+    freeze_tokens TINYINT UNSIGNED NOT NULL DEFAULT 0,
+    freezes_used INT UNSIGNED NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Per-user preferences; timezone sets where streak days begin
+CREATE TABLE user_settings (
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci PRIMARY KEY,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
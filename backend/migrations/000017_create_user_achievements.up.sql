@@ -0,0 +1,10 @@
+-- Badges earned; achievement_key refers to a rule in internal/achievements
+CREATE TABLE user_achievements (
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    achievement_key VARCHAR(64) NOT NULL,
+    subject_type VARCHAR(30) NULL,
+    subject_id BIGINT NULL,
+    awarded_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_uid, achievement_key),
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS leaderboard_scores;
+
+ALTER TABLE user_settings
+    DROP COLUMN leaderboard_opt_out;
+
+ALTER TABLE xp_ledger
+    DROP COLUMN topic_key;
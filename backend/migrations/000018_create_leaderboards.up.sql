@@ -0,0 +1,30 @@
+ALTER TABLE xp_ledger
+    ADD COLUMN topic_key VARCHAR(50) NULL AFTER reason;
+
+ALTER TABLE user_settings
+    ADD COLUMN leaderboard_opt_out BOOLEAN NOT NULL DEFAULT FALSE AFTER timezone;
+
+-- Running scores per leaderboard, updated as XP is awarded. board is
+-- "global", "weekly" or "topic:<TOPIC_KEY>"; period is "all" or an ISO week
+-- such as "2026-W07".
+CREATE TABLE leaderboard_scores (
+    board VARCHAR(64) NOT NULL,
+    period VARCHAR(16) NOT NULL,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    score INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (board, period, user_uid),
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    INDEX idx_ranking (board, period, score DESC, updated_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Seed the boards from XP awarded so far
+INSERT INTO leaderboard_scores (board, period, user_uid, score, updated_at)
+SELECT 'global', 'all', user_uid, SUM(amount), MAX(created_at)
+FROM xp_ledger
+GROUP BY user_uid;
+
+INSERT INTO leaderboard_scores (board, period, user_uid, score, updated_at)
+SELECT 'weekly', DATE_FORMAT(created_at, '%x-W%v'), user_uid, SUM(amount), MAX(created_at)
+FROM xp_ledger
+GROUP BY DATE_FORMAT(created_at, '%x-W%v'), user_uid;
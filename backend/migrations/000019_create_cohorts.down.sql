@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS cohort_assignments;
+DROP TABLE IF EXISTS cohort_members;
+DROP TABLE IF EXISTS cohorts;
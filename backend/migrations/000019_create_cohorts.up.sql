@@ -0,0 +1,38 @@
+-- Classes run by instructors; learners join with invite_code, co-instructors
+-- with instructor_code
+CREATE TABLE cohorts (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    invite_code VARCHAR(16) NOT NULL,
+    instructor_code VARCHAR(16) NOT NULL,
+    created_by VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_invite_code (invite_code),
+    UNIQUE KEY unique_instructor_code (instructor_code),
+    FOREIGN KEY (created_by) REFERENCES users(uid) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+CREATE TABLE cohort_members (
+    cohort_id BIGINT NOT NULL,
+    user_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NOT NULL,
+    role ENUM('instructor', 'learner') NOT NULL,
+    joined_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (cohort_id, user_uid),
+    FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_uid) REFERENCES users(uid) ON DELETE CASCADE,
+    INDEX idx_user (user_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Due dates for a problem (problem_id set) or a whole topic
+CREATE TABLE cohort_assignments (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    cohort_id BIGINT NOT NULL,
+    topic_key VARCHAR(50) NOT NULL,
+    problem_id VARCHAR(64) NULL,
+    due_at TIMESTAMP NOT NULL,
+    created_by VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (cohort_id) REFERENCES cohorts(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(uid) ON DELETE SET NULL,
+    INDEX idx_cohort_due (cohort_id, due_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
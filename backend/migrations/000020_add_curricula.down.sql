@@ -0,0 +1,5 @@
+ALTER TABLE cohorts
+    DROP COLUMN curriculum_key;
+
+ALTER TABLE user_settings
+    DROP COLUMN curriculum_key;
@@ -0,0 +1,7 @@
+-- Active curriculum keys (see internal/curriculum); NULL follows the
+-- cohort's curriculum, then the standard one
+ALTER TABLE user_settings
+    ADD COLUMN curriculum_key VARCHAR(64) NULL AFTER leaderboard_opt_out;
+
+ALTER TABLE cohorts
+    ADD COLUMN curriculum_key VARCHAR(64) NULL AFTER instructor_code;
@@ -0,0 +1,5 @@
+ALTER TABLE judge_submissions
+    DROP COLUMN problem_version;
+
+DROP TABLE IF EXISTS authored_problem_versions;
+DROP TABLE IF EXISTS authored_problems;
@@ -0,0 +1,46 @@
+-- Problems written by users, served next to the built-in catalog under
+-- (topic_key, problem_id). published_version is the version learners see;
+-- NULL until a moderator approves one.
+CREATE TABLE authored_problems (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    topic_key VARCHAR(50) NOT NULL,
+    problem_id VARCHAR(64) NOT NULL,
+    author_uid VARCHAR(255) COLLATE utf8mb4_0900_ai_ci NULL,
+    published_version INT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_topic_problem (topic_key, problem_id),
+    FOREIGN KEY (author_uid) REFERENCES users(uid) ON DELETE SET NULL,
+    INDEX idx_author (author_uid)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Every revision is kept so submissions can point at the version they
+-- solved. validation is the reference solution's sandbox report.
+CREATE TABLE authored_problem_versions (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    authored_problem_id BIGINT NOT NULL,
+    version INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    difficulty VARCHAR(10) NOT NULL,
+    statement TEXT NOT NULL,
+    invariant VARCHAR(500) NOT NULL,
+    examples JSON NOT NULL,
+    constraints JSON NOT NULL,
+    tests JSON NOT NULL,
+    solution_language VARCHAR(20) NOT NULL,
+    solution_entry VARCHAR(64) NOT NULL,
+    solution_code TEXT NOT NULL,
+    validation JSON NOT NULL,
+    status ENUM('pending', 'approved', 'rejected') NOT NULL DEFAULT 'pending',
+    reviewed_by VARCHAR(255) NULL,
+    review_note TEXT NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY unique_problem_version (authored_problem_id, version),
+    FOREIGN KEY (authored_problem_id) REFERENCES authored_problems(id) ON DELETE CASCADE,
+    INDEX idx_status_created (status, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+-- Version of an authored problem a submission was judged against; NULL for
+-- built-in problems
+ALTER TABLE judge_submissions
+    ADD COLUMN problem_version INT NULL AFTER problem_id;
@@ -0,0 +1,5 @@
+ALTER TABLE authored_problem_versions
+    DROP COLUMN prompt_version;
+
+ALTER TABLE authored_problems
+    DROP COLUMN source;
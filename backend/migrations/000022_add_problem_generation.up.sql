@@ -0,0 +1,7 @@
+-- Problems drafted by the LLM have no author and are moderated by admins
+ALTER TABLE authored_problems
+    ADD COLUMN source ENUM('user', 'generated') NOT NULL DEFAULT 'user' AFTER author_uid;
+
+-- Generation prompt that drafted a version; NULL for user-written ones
+ALTER TABLE authored_problem_versions
+    ADD COLUMN prompt_version VARCHAR(100) NULL AFTER validation;
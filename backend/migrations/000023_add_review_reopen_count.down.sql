@@ -0,0 +1,2 @@
+ALTER TABLE review_requests
+    DROP COLUMN reopen_count;
@@ -0,0 +1,3 @@
+-- How many times a learner's appeal reopened a resolved review
+ALTER TABLE review_requests
+    ADD COLUMN reopen_count INT NOT NULL DEFAULT 0 AFTER status;
@@ -0,0 +1,210 @@
+// Package httpfixture records HTTP exchanges to disk and replays them, so
+// code that calls external APIs can run deterministically without network.
+package httpfixture
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Modes
+const (
+	ModeOff    = "off"
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// MissingFixtureHeader marks the 404 returned when replay has no fixture
+const MissingFixtureHeader = "X-Fixture-Missing"
+
+// IgnoredQueryParams are stripped before matching and never written to disk
+var IgnoredQueryParams = []string{"key"}
+
+// Fixture is one recorded request/response pair
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body"`
+}
+
+type FixtureResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// Transport is an http.RoundTripper that records to or replays from dir
+type Transport struct {
+	mode string
+	dir  string
+	next http.RoundTripper
+}
+
+// NewTransport wraps next (http.DefaultTransport if nil). ModeOff returns next
+// unchanged.
+func NewTransport(mode, dir string, next http.RoundTripper) (http.RoundTripper, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	switch mode {
+	case "", ModeOff:
+		return next, nil
+	case ModeRecord:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create fixture dir: %w", err)
+		}
+	case ModeReplay:
+		if _, err := os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("fixture dir %s: %w", dir, err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown fixture mode %q", mode)
+	}
+
+	return &Transport{mode: mode, dir: dir, next: next}, nil
+}
+
+// NewReplayClient returns an http.Client that only serves fixtures from dir
+func NewReplayClient(dir string) (*http.Client, error) {
+	transport, err := NewTransport(ModeReplay, dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	request := FixtureRequest{
+		Method: req.Method,
+		URL:    sanitizeURL(req.URL),
+		Body:   string(body),
+	}
+	path := filepath.Join(t.dir, fixtureName(request))
+
+	if t.mode == ModeReplay {
+		return t.replay(req, path, request)
+	}
+	return t.record(req, path, request)
+}
+
+func (t *Transport) replay(req *http.Request, path string, request FixtureRequest) (*http.Response, error) {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		// A 404 rather than a transport error so callers fail fast instead of retrying
+		resp := newResponse(req, http.StatusNotFound, map[string]string{MissingFixtureHeader: filepath.Base(path)},
+			fmt.Sprintf("no fixture for %s %s (expected %s)", request.Method, request.URL, path))
+		return resp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return newResponse(req, fixture.Response.StatusCode, fixture.Response.Headers, fixture.Response.Body), nil
+}
+
+func (t *Transport) record(req *http.Request, path string, request FixtureRequest) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	headers := make(map[string]string)
+	for _, name := range []string{"Content-Type", "Retry-After"} {
+		if value := resp.Header.Get(name); value != "" {
+			headers[name] = value
+		}
+	}
+
+	fixture := Fixture{
+		Request: request,
+		Response: FixtureResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    headers,
+			Body:       string(respBody),
+		},
+	}
+	content, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write fixture: %w", err)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+// sanitizeURL drops ignored query parameters (the API key) and sorts the rest
+func sanitizeURL(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	for _, param := range IgnoredQueryParams {
+		query.Del(param)
+	}
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// fixtureName derives a stable file name from the matched request parts
+func fixtureName(request FixtureRequest) string {
+	sum := sha256.Sum256([]byte(request.Method + "\n" + request.URL + "\n" + request.Body))
+	return hex.EncodeToString(sum[:])[:24] + ".json"
+}
+
+func newResponse(req *http.Request, status int, headers map[string]string, body string) *http.Response {
+	header := make(http.Header)
+	for name, value := range headers {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -2,6 +2,7 @@ import { BrowserRouter, Routes, Route } from 'react-router-dom';
 import { AuthProvider } from './contexts/AuthContext';
 import { MasteryProvider } from './contexts/MasteryContext';
 import { CheckpointProvider } from './contexts/CheckpointContext';
+import { CurriculumProvider } from './contexts/CurriculumContext';
 import ProtectedRoute from './components/auth/ProtectedRoute';
 import HomePage from './components/home/HomePage';
 import TreeView from './components/tree/TreeView';
@@ -15,16 +16,18 @@ function App() {
       <AuthProvider>
         <MasteryProvider>
           <CheckpointProvider>
-            <Routes>
-              <Route path="/" element={<HomePage />} />
+            <CurriculumProvider>
+              <Routes>
+                <Route path="/" element={<HomePage />} />
 
-              <Route element={<ProtectedRoute />}>
-                <Route path="/tree" element={<TreeView />} />
-                <Route path="/hub/:topicKey" element={<HubView />} />
-                <Route path="/ide/:topicKey/:problemId" element={<IDEView />} />
-                <Route path="/checkpoint/:tier" element={<CheckpointIDE />} />
-              </Route>
-            </Routes>
+                <Route element={<ProtectedRoute />}>
+                  <Route path="/tree" element={<TreeView />} />
+                  <Route path="/hub/:topicKey" element={<HubView />} />
+                  <Route path="/ide/:topicKey/:problemId" element={<IDEView />} />
+                  <Route path="/checkpoint/:tier" element={<CheckpointIDE />} />
+                </Route>
+              </Routes>
+            </CurriculumProvider>
           </CheckpointProvider>
         </MasteryProvider>
       </AuthProvider>
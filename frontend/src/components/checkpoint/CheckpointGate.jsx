@@ -1,18 +1,16 @@
 import { useContext } from 'react';
 import { useNavigate } from 'react-router-dom';
-import { MasteryContext } from '../../contexts/MasteryContext';
 import { CheckpointContext } from '../../contexts/CheckpointContext';
 import { canAttemptCheckpoint, getCheckpointStatus } from '../../utils/dagLogic';
 import { getCheckpointByTier } from '../../data/checkpointsDB';
 
 export default function CheckpointGate({ tier }) {
   const navigate = useNavigate();
-  const { mastery } = useContext(MasteryContext);
   const { checkpoints } = useContext(CheckpointContext);
 
   const checkpointData = getCheckpointByTier(tier);
   const checkpointStatus = getCheckpointStatus(tier, checkpoints);
-  const canAttempt = canAttemptCheckpoint(tier, mastery);
+  const canAttempt = canAttemptCheckpoint(tier, checkpoints);
 
   if (!checkpointData) return null;
 
@@ -1,9 +1,88 @@
-import { useState, useContext } from 'react';
+import { useState, useEffect, useCallback, useContext, useRef } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
-import { getCheckpointByTier } from '../../data/checkpointsDB';
-import { attemptCheckpoint } from '../../services/checkpointService';
+import { attemptCheckpoint, saveCheckpointDraft, startCheckpointSession } from '../../services/checkpointService';
 import { CheckpointContext } from '../../contexts/CheckpointContext';
 import { useAuth } from '../../hooks/useAuth';
+import CheckpointQuiz from './CheckpointQuiz';
+
+const STARTER_CODE = '// Write your solution here...\n// Remember to use ALL required patterns!\n\n';
+
+// Wait for a pause in typing before autosaving the draft
+const AUTOSAVE_DELAY_MS = 2000;
+
+// Format milliseconds left on the session as m:ss
+const formatRemaining = (ms) => {
+  const totalSeconds = Math.max(0, Math.ceil(ms / 1000));
+  const minutes = Math.floor(totalSeconds / 60);
+  const seconds = totalSeconds % 60;
+  return `${minutes}:${seconds.toString().padStart(2, '0')}`;
+};
+
+// Gaps between keystrokes longer than this don't count as typing time
+const TYPING_IDLE_MS = 5000;
+
+const emptyTelemetry = () => ({
+  keystrokes: 0,
+  paste_events: 0,
+  pasted_chars: 0,
+  largest_paste_chars: 0,
+  active_typing_ms: 0,
+});
+
+// Telemetry is stored per session so reloading the page doesn't reset it
+const telemetryKey = (sessionId) => `checkpoint-telemetry-${sessionId}`;
+
+const loadTelemetry = (sessionId) => {
+  try {
+    return { ...emptyTelemetry(), ...JSON.parse(localStorage.getItem(telemetryKey(sessionId))) };
+  } catch {
+    return emptyTelemetry();
+  }
+};
+
+// How each checkpoint verdict is presented
+const VERDICT_DISPLAY = {
+  ADVANCE: {
+    title: 'CHECKPOINT PASSED!',
+    message: 'Tier unlocked! You can now proceed to the next tier.',
+    icon: '✓',
+    panel: 'bg-emerald-500/5 border-emerald-500/20',
+    badge: 'bg-emerald-500/20 text-emerald-400 shadow-[0_0_15px_rgba(16,185,129,0.2)]',
+    text: 'text-emerald-400',
+  },
+  REPEAT: {
+    title: 'REPEAT - Missing Patterns',
+    message: 'Review the feedback and implement missing patterns',
+    icon: '✕',
+    panel: 'bg-red-500/5 border-red-500/20',
+    badge: 'bg-red-500/20 text-red-400 shadow-[0_0_15px_rgba(239,68,68,0.2)]',
+    text: 'text-red-400',
+  },
+  QUIZ_REQUIRED: {
+    title: 'EXPLAIN YOUR SOLUTION',
+    message: 'Your solution passed the judge. Answer the quiz below to unlock the tier.',
+    icon: '?',
+    panel: 'bg-yellow-500/5 border-yellow-500/20',
+    badge: 'bg-yellow-500/20 text-yellow-400 shadow-[0_0_15px_rgba(234,179,8,0.2)]',
+    text: 'text-yellow-400',
+  },
+  NEEDS_REVIEW: {
+    title: 'SENT FOR MENTOR REVIEW',
+    message: 'A mentor will decide this attempt. Your checkpoint status updates once they do.',
+    icon: '⏳',
+    panel: 'bg-blue-500/5 border-blue-500/20',
+    badge: 'bg-blue-500/20 text-blue-400 shadow-[0_0_15px_rgba(59,130,246,0.2)]',
+    text: 'text-blue-400',
+  },
+  ERROR: {
+    title: 'JUDGE ERROR',
+    message: 'This attempt could not be judged.',
+    icon: '!',
+    panel: 'bg-red-500/5 border-red-500/20',
+    badge: 'bg-red-500/20 text-red-400 shadow-[0_0_15px_rgba(239,68,68,0.2)]',
+    text: 'text-red-400',
+  },
+};
 
 export default function CheckpointIDE() {
   const { tier } = useParams();
@@ -12,48 +91,157 @@ export default function CheckpointIDE() {
   const { logout } = useAuth();
 
   const tierNum = parseInt(tier);
-  const checkpoint = getCheckpointByTier(tierNum);
   const checkpointStatus = getCheckpointStatus(tierNum);
 
-  const [code, setCode] = useState('// Write your solution here...\n// Remember to use ALL required patterns!\n\n');
+  // The session and its assigned problem come from the server, so the
+  // deadline applies and the learner is judged on the variant they see
+  const [session, setSession] = useState(null);
+  const [problem, setProblem] = useState(null);
+  const [sessionError, setSessionError] = useState(null);
+  const [clockOffset, setClockOffset] = useState(0);
+  const [now, setNow] = useState(() => Date.now());
+  const [code, setCode] = useState(STARTER_CODE);
+  const [savedCode, setSavedCode] = useState(STARTER_CODE);
+  const [saveState, setSaveState] = useState('saved'); // 'saved', 'saving' or 'error'
   const [judgeResult, setJudgeResult] = useState(null);
   const [isJudging, setIsJudging] = useState(false);
   const [sidebarOpen, setSidebarOpen] = useState(false);
 
-  if (!checkpoint) {
+  // Editor telemetry for AI-generation screening; a ref because counting
+  // keystrokes shouldn't re-render the page
+  const telemetry = useRef(emptyTelemetry());
+  const pendingPaste = useRef(false);
+  const lastKeystrokeAt = useRef(0);
+
+  // Start a session, or resume the tier's running one with its draft
+  const openSession = useCallback(async () => {
+    try {
+      const result = await startCheckpointSession(tierNum);
+      const draft = result.session.draft_code || STARTER_CODE;
+      setSession(result.session);
+      setProblem(result.problem);
+      telemetry.current = loadTelemetry(result.session.id);
+      lastKeystrokeAt.current = 0;
+      setClockOffset(new Date(result.server_time).getTime() - Date.now());
+      setNow(Date.now());
+      setCode(draft);
+      setSavedCode(draft);
+      setSaveState('saved');
+      setJudgeResult(null);
+      setSessionError(null);
+    } catch (error) {
+      console.error('Failed to start checkpoint session:', error);
+      setSessionError(error.response?.data?.error || 'Failed to start the checkpoint. Please try again.');
+    }
+  }, [tierNum]);
+
+  useEffect(() => {
+    openSession();
+  }, [openSession]);
+
+  const isActive = session?.status === 'active';
+  const remainingMs = session ? new Date(session.deadline_at).getTime() - (now + clockOffset) : 0;
+  const isOpen = isActive && remainingMs > 0;
+
+  // Tick the countdown while the session is open
+  useEffect(() => {
+    if (!isActive) return;
+    const timer = setInterval(() => setNow(Date.now()), 1000);
+    return () => clearInterval(timer);
+  }, [isActive]);
+
+  // Autosave the draft once typing pauses; an expired session keeps the
+  // last saved draft as its submission
+  useEffect(() => {
+    if (!isOpen || code === savedCode) return;
+    const timer = setTimeout(async () => {
+      setSaveState('saving');
+      try {
+        await saveCheckpointDraft(session.id, code);
+        setSavedCode(code);
+        setSaveState('saved');
+      } catch (error) {
+        if (error.response?.status === 409) {
+          setSession((current) => current && { ...current, status: 'expired' });
+        }
+        setSaveState('error');
+      }
+    }, AUTOSAVE_DELAY_MS);
+    return () => clearTimeout(timer);
+  }, [code, savedCode, isOpen, session?.id]);
+
+  if (!problem) {
     return (
-      <div className="min-h-screen bg-[#030712] flex items-center justify-center">
-        <div className="text-white text-xl font-display">Checkpoint not found</div>
+      <div className="min-h-screen bg-[#030712] flex flex-col items-center justify-center gap-4">
+        <div className="text-white text-xl font-display">
+          {sessionError || 'Starting checkpoint...'}
+        </div>
+        {sessionError && (
+          <button onClick={() => navigate('/tree')} className="px-4 py-2 rounded-lg text-sm text-gray-300 border border-white/10 hover:bg-white/5 transition-colors">
+            Back to Skill Tree
+          </button>
+        )}
       </div>
     );
   }
 
+  // Count the paste; the change event it causes isn't a keystroke
+  const handlePaste = (e) => {
+    const pasted = e.clipboardData.getData('text').length;
+    const t = telemetry.current;
+    t.paste_events += 1;
+    t.pasted_chars += pasted;
+    t.largest_paste_chars = Math.max(t.largest_paste_chars, pasted);
+    pendingPaste.current = true;
+  };
+
+  const handleCodeChange = (e) => {
+    setCode(e.target.value);
+    const t = telemetry.current;
+    if (pendingPaste.current) {
+      pendingPaste.current = false;
+    } else {
+      const at = Date.now();
+      t.keystrokes += 1;
+      if (lastKeystrokeAt.current && at - lastKeystrokeAt.current < TYPING_IDLE_MS) {
+        t.active_typing_ms += at - lastKeystrokeAt.current;
+      }
+      lastKeystrokeAt.current = at;
+    }
+    localStorage.setItem(telemetryKey(session.id), JSON.stringify(t));
+  };
+
   const handleJudge = async () => {
+    if (!isOpen) return;
     try {
       setIsJudging(true);
       setJudgeResult(null);
-      const result = await attemptCheckpoint(tierNum, code);
+      const result = await attemptCheckpoint(session.id, code, telemetry.current);
+      localStorage.removeItem(telemetryKey(session.id));
       setJudgeResult(result);
-
-      // If verdict is ADVANCE (checkpoint passed), refresh checkpoint data
-      if (result.verdict === 'ADVANCE') {
-        setTimeout(() => {
-          refreshCheckpoints();
-        }, 1000);
-      }
+      // A session produces exactly one attempt
+      setSession({ ...session, status: 'submitted' });
+      refreshCheckpoints();
     } catch (error) {
       console.error('Judge failed:', error);
+      if (error.response?.status === 409) {
+        setSession({ ...session, status: 'expired' });
+      }
       setJudgeResult({
         verdict: 'ERROR',
-        feedback: 'Failed to judge checkpoint. Please try again.',
+        feedback: error.response?.status === 409
+          ? 'This session has closed. Your last autosaved draft was recorded as the attempt.'
+          : 'Failed to judge checkpoint. Please try again.',
         patterns_found: [],
-        missing_patterns: checkpoint.requiredPatterns
+        missing_patterns: problem.required_patterns
       });
     } finally {
       setIsJudging(false);
     }
   };
 
+  const verdictDisplay = judgeResult && (VERDICT_DISPLAY[judgeResult.verdict] || VERDICT_DISPLAY.ERROR);
+
   return (
     <div className="min-h-screen text-gray-200 font-sans selection:bg-yellow-500/30">
       {/* Background decorations */}
@@ -85,11 +273,15 @@ export default function CheckpointIDE() {
                 </h1>
               </div>
               <div className="flex items-center gap-3 mt-1">
-                <span className="px-2.5 py-0.5 rounded-full text-[10px] uppercase font-bold tracking-wider border bg-red-500/10 border-red-500/20 text-red-400">
-                  {checkpoint.difficulty}
+                <span className={`px-2.5 py-0.5 rounded-full text-[10px] uppercase font-bold tracking-wider border font-mono ${
+                  isOpen && remainingMs > 5 * 60 * 1000
+                    ? 'bg-yellow-500/10 border-yellow-500/20 text-yellow-400'
+                    : 'bg-red-500/10 border-red-500/20 text-red-400'
+                }`}>
+                  {isOpen ? `⏱ ${formatRemaining(remainingMs)}` : isActive ? "Time's up" : 'Closed'}
                 </span>
                 <span className="text-yellow-400/80 text-xs font-mono font-medium tracking-tight hidden sm:inline-block">
-                  {checkpoint.title}
+                  {problem.title}
                 </span>
               </div>
             </div>
@@ -141,7 +333,7 @@ export default function CheckpointIDE() {
             <div className="px-4">
               <h3 className="text-xs font-bold text-gray-500 uppercase tracking-widest mb-4">Checkpoint Info</h3>
               <p className="text-yellow-400 font-semibold mb-1">Tier {tier} Gate</p>
-              <p className="text-gray-400 text-sm mb-4 leading-relaxed font-light">{checkpoint.invariant}</p>
+              <p className="text-gray-400 text-sm mb-4 leading-relaxed font-light">{problem.invariant}</p>
 
               <div className="glass p-4 rounded-2xl border-l-2 border-l-yellow-500 bg-gradient-to-br from-yellow-500/5 to-transparent">
                 <div className="flex justify-between items-center">
@@ -182,8 +374,8 @@ export default function CheckpointIDE() {
             
             {/* Challenge Banner */}
             <div className="glass p-6 rounded-2xl border-l-4 border-l-yellow-500 bg-gradient-to-r from-yellow-500/5 to-transparent">
-               <h3 className="text-xl font-display font-bold text-yellow-400 mb-2">{checkpoint.title}</h3>
-               <p className="text-gray-300 leading-relaxed font-light text-lg">{checkpoint.description}</p>
+               <h3 className="text-xl font-display font-bold text-yellow-400 mb-2">{problem.title}</h3>
+               <p className="text-gray-300 leading-relaxed font-light text-lg">{problem.description}</p>
             </div>
 
             {/* Invariant Strategy */}
@@ -191,7 +383,7 @@ export default function CheckpointIDE() {
               <h3 className="text-blue-400 font-bold mb-3 flex items-center gap-2 text-sm uppercase tracking-wider">
                 <span>🎯</span> Invariant Strategy
               </h3>
-              <p className="text-blue-200/90 font-mono text-sm leading-relaxed">{checkpoint.invariant}</p>
+              <p className="text-blue-200/90 font-mono text-sm leading-relaxed">{problem.invariant}</p>
             </div>
 
             {/* Required Patterns - Critical! */}
@@ -200,7 +392,7 @@ export default function CheckpointIDE() {
                 <span>⚠️</span> Required Patterns
               </h3>
               <div className="grid grid-cols-1 md:grid-cols-2 gap-3">
-                {checkpoint.requiredPatterns.map((pattern, idx) => (
+                {problem.required_patterns.map((pattern, idx) => (
                   <div key={idx} className="flex items-center gap-3 bg-red-500/5 p-3 rounded-lg border border-red-500/10">
                     <span className="text-red-400">▸</span>
                     <span className="font-semibold text-gray-200">{pattern}</span>
@@ -209,63 +401,13 @@ export default function CheckpointIDE() {
               </div>
             </div>
 
-            {/* Examples */}
-            {checkpoint.examples.length > 0 && (
-              <div className="space-y-4">
-                <h3 className="text-lg font-semibold text-white flex items-center gap-2">
-                  <span className="w-1.5 h-1.5 rounded-full bg-blue-500 shadow-[0_0_8px_#3b82f6]"></span>
-                  Examples
-                </h3>
-                  {checkpoint.examples.map((ex, idx) => (
-                    <div key={idx} className="group glass-card rounded-xl p-5 hover:bg-white/5 transition-colors border border-white/5">
-                      <div className="grid grid-cols-1 md:grid-cols-2 gap-4">
-                        <div>
-                          <span className="text-xs font-mono text-gray-500 uppercase tracking-widest block mb-2">Input</span>
-                          <div className="font-mono text-sm bg-black/40 p-3 rounded-lg text-emerald-400 border border-white/5">
-                            {ex.input}
-                          </div>
-                        </div>
-                        <div>
-                          <span className="text-xs font-mono text-gray-500 uppercase tracking-widest block mb-2">Output</span>
-                          <div className="font-mono text-sm bg-black/40 p-3 rounded-lg text-blue-400 border border-white/5 shadow-[0_0_15px_rgba(59,130,246,0.1)]">
-                            {ex.output}
-                          </div>
-                        </div>
-                      </div>
-                      {ex.explain && (
-                        <div className="mt-4 pt-4 border-t border-white/5">
-                          <p className="text-sm text-gray-400 italic">"{ex.explain}"</p>
-                        </div>
-                      )}
-                    </div>
-                  ))}
-              </div>
-            )}
-
-            {/* Constraints */}
-            {checkpoint.constraints.length > 0 && (
-              <div className="bg-amber-500/5 border border-amber-500/10 rounded-xl p-6">
-                <h3 className="text-amber-400 font-semibold mb-4 flex items-center gap-2">
-                  <span className="text-lg">⚠️</span> Constraints
-                </h3>
-                <ul className="space-y-3">
-                  {checkpoint.constraints.map((constraint, idx) => (
-                    <li key={idx} className="flex items-start gap-3 text-sm text-amber-200/80">
-                      <span className="mt-1.5 w-1 h-1 rounded-full bg-amber-400 shadow-[0_0_5px_#fbbf24]"></span>
-                      <span className="font-mono">{constraint}</span>
-                    </li>
-                  ))}
-                </ul>
-              </div>
-            )}
-
             {/* Hint */}
-            {checkpoint.hint && (
+            {problem.hint && (
               <div className="bg-purple-500/5 p-6 rounded-2xl border border-purple-500/20">
                 <h3 className="text-purple-400 font-semibold mb-2 flex items-center gap-2 text-sm uppercase tracking-wider">
                   <span>💭</span> Hint
                 </h3>
-                <p className="text-purple-200/80 text-sm leading-relaxed">{checkpoint.hint}</p>
+                <p className="text-purple-200/80 text-sm leading-relaxed">{problem.hint}</p>
               </div>
             )}
           </div>
@@ -277,9 +419,26 @@ export default function CheckpointIDE() {
 
           {/* Code Editor Header */}
           <div className="px-6 py-4 flex items-center justify-between border-b border-white/5 bg-[#0a0a15]">
-            <div className="flex items-center gap-2">
+            <div className="flex items-center gap-3">
                <span className="text-sm font-mono text-gray-500">checkpoint.py</span>
+               {isOpen && (
+                 <span className={`text-xs font-mono ${saveState === 'error' ? 'text-red-400' : 'text-gray-600'}`}>
+                   {saveState === 'error'
+                     ? 'Autosave failed'
+                     : saveState === 'saving'
+                       ? 'Saving...'
+                       : code === savedCode ? 'Draft saved' : 'Unsaved changes'}
+                 </span>
+               )}
             </div>
+            {!isOpen && !isJudging ? (
+              <button
+                onClick={openSession}
+                className="px-6 py-2 rounded-lg text-sm font-bold text-white bg-white/10 hover:bg-white/20 border border-white/10 transition-all"
+              >
+                Start Next Attempt
+              </button>
+            ) : (
             <button
                 onClick={handleJudge}
                 disabled={isJudging}
@@ -296,13 +455,26 @@ export default function CheckpointIDE() {
                   </div>
                 )}
               </button>
+            )}
           </div>
 
           {/* Code Editor Area */}
           <div className="flex-1 relative group">
+            {isActive && !isOpen && (
+              <div className="absolute top-0 inset-x-0 z-10 px-6 py-2 bg-red-500/10 border-b border-red-500/20 text-red-300 text-sm">
+                Time's up. Your last autosaved draft will be recorded as this attempt.
+              </div>
+            )}
+            {!isOpen && sessionError && (
+              <div className="absolute top-0 inset-x-0 z-10 px-6 py-2 bg-red-500/10 border-b border-red-500/20 text-red-300 text-sm">
+                {sessionError}
+              </div>
+            )}
             <textarea
               value={code}
-              onChange={(e) => setCode(e.target.value)}
+              onChange={handleCodeChange}
+              onPaste={handlePaste}
+              readOnly={!isOpen}
               className="w-full h-full p-6 bg-transparent text-gray-300 font-mono text-[14px] leading-relaxed resize-none focus:outline-none focus:bg-white/[0.02] transition-colors custom-scrollbar"
               style={{ height: 'calc(100vh - 400px)' }}
               spellCheck={false}
@@ -322,40 +494,32 @@ export default function CheckpointIDE() {
                 </div>
 
                 <div className="flex-1 overflow-y-auto p-6 custom-scrollbar">
-                    <div className={`p-6 rounded-xl border ${
-                        judgeResult.verdict === 'ADVANCE'
-                          ? 'bg-emerald-500/5 border-emerald-500/20'
-                          : 'bg-red-500/5 border-red-500/20'
-                      }`}>
+                    <div className={`p-6 rounded-xl border ${verdictDisplay.panel}`}>
                         <div className="flex items-start gap-4">
-                          <div className={`p-3 rounded-full ${
-                            judgeResult.verdict === 'ADVANCE' 
-                            ? 'bg-emerald-500/20 text-emerald-400 shadow-[0_0_15px_rgba(16,185,129,0.2)]' 
-                            : 'bg-red-500/20 text-red-400 shadow-[0_0_15px_rgba(239,68,68,0.2)]'
-                          }`}>
-                            <span className="text-2xl block leading-none">
-                              {judgeResult.verdict === 'ADVANCE' ? '✓' : '✕'}
-                            </span>
+                          <div className={`p-3 rounded-full ${verdictDisplay.badge}`}>
+                            <span className="text-2xl block leading-none">{verdictDisplay.icon}</span>
                           </div>
                           <div>
-                            <span className={`text-xl font-bold mb-1 block ${
-                                judgeResult.verdict === 'ADVANCE' ? 'text-emerald-400' : 'text-red-400'
-                            }`}>
-                              {judgeResult.verdict === 'ADVANCE' ? (
-                                <span>CHECKPOINT PASSED!</span>
-                              ) : (
-                                <span>REPEAT - Missing Patterns</span>
-                              )}
+                            <span className={`text-xl font-bold mb-1 block ${verdictDisplay.text}`}>
+                              {verdictDisplay.title}
                             </span>
-                            <p className="text-gray-300 leading-relaxed text-sm opacity-90 mb-1">{
-                              judgeResult.verdict === 'ADVANCE'
-                                ? 'Tier unlocked! You can now proceed to the next tier.'
-                                : 'Review the feedback and implement missing patterns'
-                            }</p>
+                            <p className="text-gray-300 leading-relaxed text-sm opacity-90 mb-1">{verdictDisplay.message}</p>
                             <p className="text-gray-400 text-xs italic opacity-70 mt-2 border-t border-white/10 pt-2">{judgeResult.feedback}</p>
                           </div>
                         </div>
 
+                        {/* Explain quiz before a QUIZ_REQUIRED pass counts */}
+                        {judgeResult.verdict === 'QUIZ_REQUIRED' && (
+                          <div className="mt-6">
+                            <h4 className="text-yellow-400 font-bold text-xs uppercase tracking-wider mb-3">Explain Your Solution</h4>
+                            <CheckpointQuiz
+                              attemptId={judgeResult.attempt_id}
+                              initialQuiz={judgeResult.quiz}
+                              onComplete={refreshCheckpoints}
+                            />
+                          </div>
+                        )}
+
                         {/* Pattern Analysis */}
                         <div className="grid grid-cols-1 md:grid-cols-2 gap-4 mt-6">
                           {judgeResult.patterns_found && judgeResult.patterns_found.length > 0 && (
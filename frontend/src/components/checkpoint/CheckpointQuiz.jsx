@@ -0,0 +1,97 @@
+import { useState, useEffect } from 'react';
+import { getCheckpointQuiz, submitCheckpointQuiz } from '../../services/checkpointService';
+
+// Explain quiz for a QUIZ_REQUIRED checkpoint attempt: the pass only counts
+// once the learner explains their own solution
+export default function CheckpointQuiz({ attemptId, initialQuiz, onComplete }) {
+  const [quiz, setQuiz] = useState(initialQuiz || null);
+  const [answers, setAnswers] = useState({});
+  const [result, setResult] = useState(null);
+  const [isSubmitting, setIsSubmitting] = useState(false);
+  const [error, setError] = useState(null);
+
+  // The attempt response carries the quiz; fetch it if it didn't
+  useEffect(() => {
+    if (quiz) return;
+    getCheckpointQuiz(attemptId)
+      .then(setQuiz)
+      .catch((err) => setError(err.response?.data?.error || 'Failed to load the quiz.'));
+  }, [attemptId, quiz]);
+
+  if (error && !quiz) {
+    return <p className="text-red-300 text-sm">{error}</p>;
+  }
+  if (!quiz) {
+    return <p className="text-gray-400 text-sm">Loading quiz...</p>;
+  }
+
+  const complete = quiz.questions.every((q) => (answers[q.id] || '').trim() !== '');
+
+  const handleSubmit = async () => {
+    try {
+      setIsSubmitting(true);
+      setError(null);
+      const graded = await submitCheckpointQuiz(
+        attemptId,
+        quiz.questions.map((q) => ({ question_id: q.id, answer: answers[q.id].trim() }))
+      );
+      setResult(graded);
+      onComplete(graded);
+    } catch (err) {
+      console.error('Quiz submission failed:', err);
+      setError(err.response?.data?.error || 'Failed to submit the quiz. Please try again.');
+    } finally {
+      setIsSubmitting(false);
+    }
+  };
+
+  if (result) {
+    return (
+      <div className={`p-4 rounded-xl border ${
+        result.passed
+          ? 'bg-emerald-500/5 border-emerald-500/20'
+          : result.needs_review
+            ? 'bg-blue-500/5 border-blue-500/20'
+            : 'bg-red-500/5 border-red-500/20'
+      }`}>
+        <p className={`font-bold mb-1 ${result.passed ? 'text-emerald-400' : result.needs_review ? 'text-blue-400' : 'text-red-400'}`}>
+          {result.passed
+            ? 'Quiz passed - tier unlocked!'
+            : result.needs_review
+              ? 'Quiz not passed - a mentor will review your attempt'
+              : 'Quiz not passed'}
+          {result.quiz?.score != null && <span className="ml-2 font-mono text-xs opacity-70">{result.quiz.score}%</span>}
+        </p>
+        {result.feedback && <p className="text-gray-300 text-sm leading-relaxed">{result.feedback}</p>}
+      </div>
+    );
+  }
+
+  return (
+    <div className="space-y-4">
+      {quiz.questions.map((q, idx) => (
+        <div key={q.id} className="bg-yellow-500/5 p-4 rounded-xl border border-yellow-500/10">
+          <label htmlFor={`quiz-${q.id}`} className="block text-yellow-200/90 text-sm font-medium mb-2">
+            {idx + 1}. {q.question}
+          </label>
+          <textarea
+            id={`quiz-${q.id}`}
+            value={answers[q.id] || ''}
+            onChange={(e) => setAnswers({ ...answers, [q.id]: e.target.value })}
+            rows={3}
+            className="w-full p-3 rounded-lg bg-black/30 border border-white/10 text-gray-200 text-sm resize-none focus:outline-none focus:border-yellow-500/40"
+            placeholder="Explain in your own words..."
+          />
+        </div>
+      ))}
+      {error && <p className="text-red-300 text-sm">{error}</p>}
+      <button
+        onClick={handleSubmit}
+        disabled={!complete || isSubmitting}
+        className="px-6 py-2 rounded-lg text-sm font-bold text-white bg-gradient-to-r from-yellow-600 to-amber-600 hover:from-yellow-500 hover:to-amber-500 transition-all disabled:opacity-50"
+      >
+        {isSubmitting ? 'Grading...' : 'Submit Answers'}
+      </button>
+    </div>
+  );
+}
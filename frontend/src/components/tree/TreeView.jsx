@@ -1,17 +1,15 @@
-import { useMastery } from '../../hooks/useMastery';
 import { useAuth } from '../../hooks/useAuth';
 import { useNavigate } from 'react-router-dom';
-import { getTopicsByTier, getStatus, getStatusColor } from '../../utils/dagLogic';
+import { getTopicsByTier } from '../../utils/dagLogic';
 import { useContext } from 'react';
-import { CheckpointContext } from '../../contexts/CheckpointContext';
+import { CurriculumContext } from '../../contexts/CurriculumContext';
 import CheckpointGate from '../checkpoint/CheckpointGate';
 
 export default function TreeView() {
-  const { mastery } = useMastery();
   const { logout } = useAuth();
   const navigate = useNavigate();
-  const { checkpoints } = useContext(CheckpointContext);
-  const tiers = getTopicsByTier();
+  const { curriculum, loading } = useContext(CurriculumContext);
+  const tiers = getTopicsByTier(curriculum);
 
   const handleTopicClick = (topicKey, status) => {
     if (status !== 'LOCKED' && status !== 'CHECKPOINT_BLOCKED') {
@@ -28,7 +26,7 @@ export default function TreeView() {
             <h1 className="text-3xl font-display font-bold text-white mb-2">Skill Tree</h1>
             <div className="flex items-center gap-2 text-sm text-gray-400">
               <span className="w-2 h-2 rounded-full bg-blue-500 animate-pulse"></span>
-              Neural Dependency Graph
+              {curriculum?.curriculum.title || 'Neural Dependency Graph'}
             </div>
           </div>
           <div className="flex gap-4">
@@ -71,6 +69,13 @@ export default function TreeView() {
           </div>
         </div>
 
+        {loading && !curriculum && (
+          <p className="text-center text-gray-400 font-mono text-sm">Loading curriculum...</p>
+        )}
+        {!loading && !curriculum && (
+          <p className="text-center text-red-400 font-mono text-sm">Failed to load your curriculum.</p>
+        )}
+
         {/* Tiers */}
         <div className="space-y-16 relative">
           {/* Central Line Connector */}
@@ -95,8 +100,7 @@ export default function TreeView() {
 
                   <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-6">
                     {tiers[tierNum].map((topic) => {
-                      const status = getStatus(topic.key, mastery, checkpoints);
-                      const topicMastery = mastery[topic.key] || { confidence: 0, solved: [] };
+                      const status = topic.status;
 
                       // Dynamic Styles based on Status
                       let cardStyle = "bg-slate-900/40 border-slate-700/50 hover:border-slate-500/50";
@@ -161,14 +165,14 @@ export default function TreeView() {
                                   <span className={status === 'MASTERED' ? 'text-emerald-400' : 'text-blue-400'}>
                                     {status === 'MASTERED' ? 'MAXIMIZED' : 'PROFICIENCY'}
                                   </span>
-                                  <span className="text-white">{topicMastery.confidence}%</span>
+                                  <span className="text-white">{topic.confidence}%<span className="text-gray-500"> / {topic.threshold}%</span></span>
                                 </div>
                                 <div className="w-full bg-slate-800 rounded-full h-1.5 overflow-hidden">
                                   <div
                                     className={`h-full rounded-full transition-all duration-1000 ${
                                       status === 'MASTERED' ? 'bg-emerald-500 shadow-[0_0_10px_#10b981]' : 'bg-blue-500 shadow-[0_0_10px_#3b82f6]'
                                     }`}
-                                    style={{ width: `${topicMastery.confidence}%` }}
+                                    style={{ width: `${topic.confidence}%` }}
                                   ></div>
                                 </div>
                               </div>
@@ -0,0 +1,50 @@
+import { createContext, useState, useEffect, useContext } from 'react';
+import { getCurriculum } from '../services/curriculumService';
+import { AuthContext } from './AuthContext';
+import { MasteryContext } from './MasteryContext';
+import { CheckpointContext } from './CheckpointContext';
+
+export const CurriculumContext = createContext();
+
+export const CurriculumProvider = ({ children }) => {
+  const { user } = useContext(AuthContext);
+  const { mastery } = useContext(MasteryContext);
+  const { checkpoints } = useContext(CheckpointContext);
+  const [curriculum, setCurriculum] = useState(null);
+  const [loading, setLoading] = useState(true);
+
+  const loadCurriculum = async () => {
+    if (!user) {
+      setCurriculum(null);
+      setLoading(false);
+      return;
+    }
+
+    try {
+      setLoading(true);
+      const data = await getCurriculum();
+      setCurriculum(data);
+    } catch (error) {
+      console.error('Failed to load curriculum:', error);
+      setCurriculum(null);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  // Topic statuses are evaluated server-side, so reload whenever mastery or
+  // checkpoints change
+  useEffect(() => {
+    loadCurriculum();
+  }, [user, mastery, checkpoints]);
+
+  const refreshCurriculum = async () => {
+    await loadCurriculum();
+  };
+
+  return (
+    <CurriculumContext.Provider value={{ curriculum, loading, refreshCurriculum }}>
+      {children}
+    </CurriculumContext.Provider>
+  );
+};
@@ -1,5 +1,5 @@
 import { createContext, useState, useEffect, useContext } from 'react';
-import { getMastery, updateMastery as apiUpdateMastery } from '../services/masteryService';
+import { getMastery } from '../services/masteryService';
 import { AuthContext } from './AuthContext';
 
 export const MasteryContext = createContext();
@@ -32,33 +32,12 @@ export const MasteryProvider = ({ children }) => {
     loadMastery();
   }, [user]);
 
-  const updateMastery = async (topicKey, confidence, solvedProblems) => {
-    try {
-      await apiUpdateMastery(topicKey, {
-        confidence,
-        solved_problems: solvedProblems,
-      });
-
-      // Update local state
-      setMastery((prev) => ({
-        ...prev,
-        [topicKey]: {
-          confidence,
-          solved: solvedProblems,
-        },
-      }));
-    } catch (error) {
-      console.error('Failed to update mastery:', error);
-      throw error;
-    }
-  };
-
   const refreshMastery = async () => {
     await loadMastery();
   };
 
   return (
-    <MasteryContext.Provider value={{ mastery, loading, updateMastery, refreshMastery }}>
+    <MasteryContext.Provider value={{ mastery, loading, refreshMastery }}>
       {children}
     </MasteryContext.Provider>
   );
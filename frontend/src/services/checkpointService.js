@@ -11,12 +11,38 @@ export const getCheckpoints = async () => {
   }
 };
 
-// Attempt a checkpoint problem
-export const attemptCheckpoint = async (tier, code) => {
+// Start (or resume) a timed checkpoint session
+export const startCheckpointSession = async (tier) => {
+  try {
+    const response = await api.post(`/checkpoints/${tier}/start`);
+    return response.data;
+  } catch (error) {
+    console.error('Failed to start checkpoint session:', error);
+    throw error;
+  }
+};
+
+// Autosave draft code for an open session
+export const saveCheckpointDraft = async (sessionId, code) => {
+  try {
+    const response = await api.put(`/checkpoints/sessions/${sessionId}/draft`, {
+      code: code,
+    });
+    return response.data;
+  } catch (error) {
+    console.error('Failed to save checkpoint draft:', error);
+    throw error;
+  }
+};
+
+// Submit code for an open checkpoint session with the editor telemetry
+// (keystrokes, paste_events, pasted_chars, largest_paste_chars, active_typing_ms)
+export const attemptCheckpoint = async (sessionId, code, telemetry) => {
   try {
     const response = await api.post('/checkpoints/attempt', {
-      tier_number: tier,
+      session_id: sessionId,
       code: code,
+      telemetry: telemetry,
     });
     return response.data;
   } catch (error) {
@@ -24,3 +50,27 @@ export const attemptCheckpoint = async (tier, code) => {
     throw error;
   }
 };
+
+// Get the explain quiz a QUIZ_REQUIRED attempt must pass
+export const getCheckpointQuiz = async (attemptId) => {
+  try {
+    const response = await api.get(`/checkpoints/attempts/${attemptId}/quiz`);
+    return response.data;
+  } catch (error) {
+    console.error('Failed to fetch checkpoint quiz:', error);
+    throw error;
+  }
+};
+
+// Answer the explain quiz; answers is a list of { question_id, answer }
+export const submitCheckpointQuiz = async (attemptId, answers) => {
+  try {
+    const response = await api.post(`/checkpoints/attempts/${attemptId}/quiz`, {
+      answers: answers,
+    });
+    return response.data;
+  } catch (error) {
+    console.error('Failed to submit checkpoint quiz:', error);
+    throw error;
+  }
+};
@@ -0,0 +1,12 @@
+import api from './api';
+
+// Get the learner's active curriculum with each topic's status
+export const getCurriculum = async () => {
+  try {
+    const response = await api.get('/curriculum');
+    return response.data;
+  } catch (error) {
+    console.error('Failed to fetch curriculum:', error);
+    throw error;
+  }
+};
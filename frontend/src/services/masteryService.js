@@ -9,8 +9,3 @@ export const getMastery = async () => {
   const response = await api.get('/mastery');
   return response.data;
 };
-
-export const updateMastery = async (topicKey, data) => {
-  const response = await api.put(`/mastery/${topicKey}`, data);
-  return response.data;
-};
@@ -1,60 +1,36 @@
 import { DAG_STRUCTURE } from '../data/dagStructure';
 
-const CONFIDENCE_THRESHOLD = 70;
-
-export const getStatus = (topicKey, mastery, checkpoints = []) => {
-  const node = DAG_STRUCTURE[topicKey];
-  if (!node) return 'LOCKED';
-
-  const topicMastery = mastery[topicKey] || { confidence: 0, solved: [] };
-
-  // Check checkpoint gate: if tier > 0, previous tier checkpoint must be passed
-  if (node.tier > 0) {
-    const previousTier = node.tier - 1;
-    const previousCheckpoint = checkpoints.find(cp => cp.tier_number === previousTier);
-
-    if (previousCheckpoint && !previousCheckpoint.is_passed) {
-      // Previous checkpoint not passed - entire tier is blocked
-      return 'CHECKPOINT_BLOCKED';
-    }
-  }
-
-  // If this topic is mastered (100% confidence)
-  if (topicMastery.confidence >= 100) {
-    return 'MASTERED';
-  }
-
-  // If this topic has some progress
-  if (topicMastery.confidence > 0) {
-    return 'IN_PROGRESS';
-  }
-
-  // Check if all prerequisites are met
-  if (node.reqs.length === 0) {
-    return 'UNLOCKED'; // Root node, no prerequisites
-  }
-
-  const allPrereqsMet = node.reqs.every((reqKey) => {
-    const reqMastery = mastery[reqKey] || { confidence: 0 };
-    return reqMastery.confidence >= CONFIDENCE_THRESHOLD;
-  });
-
-  return allPrereqsMet ? 'UNLOCKED' : 'LOCKED';
-};
-
 export const calculateConfidence = (solvedCount) => {
   // Each problem is worth ~33.3%, max 100%
   return Math.min(Math.floor((solvedCount / 3) * 100), 100);
 };
 
-export const getTopicsByTier = () => {
+// Group the active curriculum's topics by tier. Status, confidence and
+// threshold come from the server; DAG_STRUCTURE only supplies labels.
+export const getTopicsByTier = (active) => {
   const tiers = {};
+  if (!active) return tiers;
 
-  Object.entries(DAG_STRUCTURE).forEach(([key, node]) => {
-    if (!tiers[node.tier]) {
-      tiers[node.tier] = [];
+  const progress = {};
+  (active.topics || []).forEach((topic) => {
+    progress[topic.key] = topic;
+  });
+
+  active.curriculum.topics.forEach((topic) => {
+    const node = DAG_STRUCTURE[topic.key] || { label: topic.key, desc: '' };
+    const standing = progress[topic.key] || { status: 'LOCKED', confidence: 0 };
+    if (!tiers[topic.tier]) {
+      tiers[topic.tier] = [];
     }
-    tiers[node.tier].push({ key, ...node });
+    tiers[topic.tier].push({
+      ...node,
+      key: topic.key,
+      tier: topic.tier,
+      reqs: topic.reqs,
+      threshold: topic.threshold,
+      status: standing.status,
+      confidence: standing.confidence,
+    });
   });
 
   return tiers;
@@ -77,16 +53,11 @@ export const getStatusColor = (status) => {
   }
 };
 
-// Check if user can attempt a checkpoint (all tier topics ≥70% confidence)
-export const canAttemptCheckpoint = (tier, mastery) => {
-  const tierTopics = Object.entries(DAG_STRUCTURE)
-    .filter(([_, node]) => node.tier === tier)
-    .map(([key, _]) => key);
-
-  return tierTopics.every((topicKey) => {
-    const topicMastery = mastery[topicKey] || { confidence: 0 };
-    return topicMastery.confidence >= CONFIDENCE_THRESHOLD;
-  });
+// Check if user can attempt a checkpoint (every topic of the tier in their
+// curriculum at its threshold, as evaluated by the server)
+export const canAttemptCheckpoint = (tier, checkpoints = []) => {
+  const checkpoint = checkpoints.find(cp => cp.tier_number === tier);
+  return checkpoint?.can_attempt || false;
 };
 
 // Get checkpoint status for a tier